@@ -2,6 +2,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"github.com/kubex-ecosystem/logz/internal/formatter"
 	"github.com/kubex-ecosystem/logz/internal/module/info"
 	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/internal/writer"
 	"github.com/spf13/cobra"
 
 	gl "github.com/kubex-ecosystem/logz"
@@ -24,7 +26,7 @@ func LogzCmd() *cobra.Command {
 }
 
 func LoggerCmd() *cobra.Command {
-	var Output, Format, Level, MinLevel, MaxLevel string
+	var Output, Format, Level, MinLevel, MaxLevel, Theme string
 	var DisableColors, ShowTraceID, ShowFields, ShowStack, DisableIcons bool
 
 	short := "Logger related operations"
@@ -57,8 +59,23 @@ You can configure the logger to suit your application's needs.`
 
 			// Configurar argumentos do logger com valores padrão se não especificados
 
-			kbx.LoggerArgs.Format = kbx.GetValueOrDefaultSimple(kbx.LoggerArgs.Format, kbx.GetValueOrDefaultSimple(Format, "text"))
-			kbx.LoggerArgs.Output = kbx.GetValueOrDefaultSimple(kbx.LoggerArgs.Output, gl.ParseWriter(Output))
+			kbx.LoggerArgs.Format = kbx.GetValueOrDefaultSimple(kbx.LoggerArgs.Format, kbx.GetValueOrDefaultSimple(Format, kbx.GetEnvOrDefault("LOGZ_LOG_FORMAT", "text")))
+			kbx.LoggerArgs.Theme = kbx.GetValueOrDefaultSimple(kbx.LoggerArgs.Theme, Theme)
+			// LoggerArgs.Output já vem preenchido pelo logger global; o
+			// --output explícito tem prioridade.
+			// A saída aberta aqui é fechada no fim (lote HTTP, compressão
+			// dos rotacionados); stdout/stderr ficam abertos.
+			var opened writer.LogzWriter
+			if kbx.LoggerArgs.Output == nil || cmd.Flags().Changed("output") {
+				out, err := writer.Open(Output)
+				if err != nil {
+					return err
+				}
+				kbx.LoggerArgs.Output = out
+				if w := out.GetIOWriter(); w != os.Stdout && w != os.Stderr {
+					opened = out
+				}
+			}
 			kbx.LoggerArgs.Level = kbx.GetValueOrDefaultSimple(kbx.LoggerArgs.Level, gl.ParseLevel(Level))
 			kbx.LoggerArgs.MinLevel = kbx.GetValueOrDefaultSimple(kbx.LoggerArgs.MinLevel, gl.ParseLevel(MinLevel))
 			kbx.LoggerArgs.MaxLevel = kbx.GetValueOrDefaultSimple(kbx.LoggerArgs.MaxLevel, gl.ParseLevel(MaxLevel))
@@ -93,13 +110,12 @@ You can configure the logger to suit your application's needs.`
 				opts.LogzAdvancedOptions.Metadata = fields
 			}
 
-			// Aplicar formato se especificado
-			if kbx.LoggerArgs.Format != "" {
-				opts.Formatter = formatter.ParseFormatter(kbx.LoggerArgs.Format, kbx.DefaultTrue(kbx.LoggerArgs.ShowColor))
-			} else {
-				// Usar formatter padrão se não especificado
-				opts.Formatter = formatter.NewTextFormatter(kbx.DefaultTrue(kbx.LoggerArgs.ShowColor))
+			// Aplicar formato (nome desconhecido é erro, não cai no text)
+			f, err := formatter.New(kbx.LoggerArgs.Format, kbx.DefaultTrue(kbx.LoggerArgs.ShowColor))
+			if err != nil {
+				return err
 			}
+			opts.Formatter = f
 
 			// Criar logger
 			logger := core.NewLogger(kbx.GetValueOrDefaultSimple(kbx.LoggerArgs.Prefix, "LogzCLI"), opts, false)
@@ -120,8 +136,14 @@ You can configure the logger to suit your application's needs.`
 				WithStack(kbx.LoggerArgs.ShowStack).
 				WithCaller("CLI")
 
-			// Usar o level correto ao invés de "error" fixo
-			return logger.Log(kbx.LoggerArgs.Level, entry)
+			// Usar o level correto ao invés de "error" fixo; o Close envia o
+			// que os exportadores (OTLP) ainda têm em buffer.
+			err = logger.Log(kbx.LoggerArgs.Level, entry)
+			err = errors.Join(err, logger.Close())
+			if opened != nil {
+				err = errors.Join(err, opened.Close())
+			}
+			return err
 		},
 	}
 
@@ -129,10 +151,11 @@ You can configure the logger to suit your application's needs.`
 	loggerCmd.Flags().StringVarP(&Level, "level", "l", "info", "Set the logging level (e.g., debug, info, warn, error)")
 	loggerCmd.Flags().StringVarP(&MinLevel, "min-level", "L", "debug", "Set the minimum logging level")
 	loggerCmd.Flags().StringVarP(&MaxLevel, "max-level", "U", "fatal", "Set the maximum logging level")
-	loggerCmd.Flags().StringVarP(&Output, "output", "o", "stdout", "Set the logging output (e.g., stdout, file)")
-	loggerCmd.Flags().StringVarP(&Format, "format", "f", "text", "Set the logging format (e.g., json, text)")
+	loggerCmd.Flags().StringVarP(&Output, "output", "o", "stdout", "Set the logging output (e.g., stdout, stderr, a file path, file:///var/log/app.log?rotate=true&max_size=100MB, syslog+udp://host:514, tcp://host:port, unix:///path, https://host/path, null://)")
+	loggerCmd.Flags().StringVarP(&Format, "format", "f", "", "Set the logging format (e.g., json, text, msgpack, cbor, protobuf or a registered format; default $LOGZ_LOG_FORMAT or text)")
 	loggerCmd.Flags().StringArrayVarP(&kbx.LoggerArgs.Messages, "message", "m", []string{}, "Log message parts")
 	loggerCmd.Flags().StringToStringVarP(&kbx.LoggerArgs.Metadata, "metadata", "M", map[string]string{}, "Set metadata key-value pairs for the log entry")
+	loggerCmd.Flags().StringVarP(&Theme, "theme", "T", "", "Set the color/icon theme (default, ascii, high-contrast, monochrome)")
 	loggerCmd.Flags().BoolVarP(&DisableColors, "disableColors", "c", false, "Enable colored output")
 	loggerCmd.Flags().BoolVarP(&DisableIcons, "disableIcons", "i", false, "Enable icons in the log entry")
 	loggerCmd.Flags().BoolVarP(&ShowTraceID, "showTraceID", "t", false, "Include trace ID in the log entry")
@@ -142,5 +165,7 @@ You can configure the logger to suit your application's needs.`
 
 	loggerCmd.MarkFlagFilename("output")
 
+	loggerCmd.AddCommand(readCmd())
+
 	return loggerCmd
 }
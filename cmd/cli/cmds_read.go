@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kubex-ecosystem/logz/internal/binlog"
+	"github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/spf13/cobra"
+)
+
+// readCmd lê de volta arquivos gravados pelos formatters binários
+// (msgpack/cbor/protobuf) e os reimprime no formato pedido.
+func readCmd() *cobra.Command {
+	var format string
+	var disableColors bool
+
+	cmd := &cobra.Command{
+		Use:   "read [file...]",
+		Short: "Read binary (msgpack/cbor/protobuf) log files",
+		Long: `Decode length-prefixed MessagePack, CBOR or protobuf log records and print them.
+With no file, or when file is "-", records are read from stdin.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := formatter.New(format, !disableColors)
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				args = []string{"-"}
+			}
+			for _, name := range args {
+				if err := readBinaryLog(name, f, cmd.OutOrStdout()); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format (e.g., text, json, console or a registered format)")
+	cmd.Flags().BoolVarP(&disableColors, "disableColors", "c", false, "Disable colored output")
+
+	return cmd
+}
+
+func readBinaryLog(name string, f formatter.Formatter, out io.Writer) error {
+	var in io.Reader = os.Stdin
+	if name != "-" {
+		fh, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		in = fh
+	}
+	rd := binlog.NewReader(in)
+	for n := 1; ; n++ {
+		r, err := rd.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: record %d: %w", name, n, err)
+		}
+		b, err := f.Format(core.EntryFromRecord(r))
+		if errors.Is(err, formatter.ErrDropped) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: record %d: %w", name, n, err)
+		}
+		if !formatter.IsBinary(f) && (len(b) == 0 || b[len(b)-1] != '\n') {
+			b = append(b, '\n')
+		}
+		if _, err := out.Write(b); err != nil {
+			return err
+		}
+	}
+}
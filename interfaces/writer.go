@@ -1,5 +1,13 @@
 package interfaces
 
+import "github.com/kubex-ecosystem/logz/internal/module/kbx"
+
 type Writer interface {
 	Write([]byte) (int, error)
 }
+
+// EntryWriter é um destino que recebe a Entry já redigida, em vez dos bytes
+// formatados. Exportadores estruturados (OTLP...) implementam isso.
+type EntryWriter interface {
+	WriteEntry(e kbx.Entry) error
+}
@@ -0,0 +1,175 @@
+package binlog_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/binlog"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+var (
+	codecs = []binlog.Codec{binlog.MsgPack, binlog.CBOR, binlog.Proto}
+	levels = []kbx.Level{
+		kbx.LevelTrace, kbx.LevelDebug, kbx.LevelInfo, kbx.LevelNotice, kbx.LevelSuccess,
+		kbx.LevelWarn, kbx.LevelError, kbx.LevelAlert, kbx.LevelCritical, kbx.LevelFatal,
+		kbx.LevelPanic, kbx.LevelBug, kbx.LevelAnswer, kbx.LevelSilent,
+	}
+	ts = time.Date(2024, 2, 29, 23, 59, 58, 123456789, time.UTC)
+)
+
+// fieldCases: valor logado -> valor lido de volta (os tipos que os três
+// codecs têm em comum).
+var fieldCases = []struct {
+	name     string
+	in, want any
+}{
+	{"nil", nil, nil},
+	{"bool", true, true},
+	{"string", "olá, 世界", "olá, 世界"},
+	{"empty string", "", ""},
+	{"small int", 42, int64(42)},
+	{"negative int", int8(-7), int64(-7)},
+	{"max int64", int64(math.MaxInt64), int64(math.MaxInt64)},
+	{"min int64", int64(math.MinInt64), int64(math.MinInt64)},
+	{"uint32", uint32(math.MaxUint32), int64(math.MaxUint32)},
+	{"max uint64", uint64(math.MaxUint64), uint64(math.MaxUint64)},
+	{"float", 3.25, 3.25},
+	{"float32", float32(0.5), 0.5},
+	{"bytes", []byte{0, 1, 0xfe, 0xff}, []byte{0, 1, 0xfe, 0xff}},
+	{"empty bytes", []byte{}, []byte{}},
+	{"time", ts, ts},
+	{"duration", 1500 * time.Millisecond, int64(1500 * time.Millisecond)},
+	{"error", errors.New("boom"), "boom"},
+	{"slice", []any{1, "two", 3.5, nil, false}, []any{int64(1), "two", 3.5, nil, false}},
+	{"typed slice", []string{"a", "b"}, []any{"a", "b"}},
+	{"empty slice", []any{}, []any{}},
+	{"map of strings", map[string]string{"k": "v"}, map[string]any{"k": "v"}},
+	{"nested", map[string]any{
+		"user": map[string]any{"id": 7, "roles": []any{"admin", map[string]any{"scope": "all"}}},
+		"at":   ts,
+		"raw":  []byte("x"),
+	}, map[string]any{
+		"user": map[string]any{"id": int64(7), "roles": []any{"admin", map[string]any{"scope": "all"}}},
+		"at":   ts,
+		"raw":  []byte("x"),
+	}},
+	{"empty map", map[string]any{}, map[string]any{}},
+	{"struct", struct {
+		A int    `json:"a"`
+		B string `json:"b"`
+	}{1, "x"}, map[string]any{"a": int64(1), "b": "x"}},
+	// passa por encoding/json, mas sem virar float64 no caminho.
+	{"struct big int", struct{ N int64 }{1<<62 + 1}, map[string]any{"N": int64(1<<62 + 1)}},
+}
+
+func record(lvl kbx.Level) *binlog.Record {
+	fields := make(map[string]any, len(fieldCases))
+	for _, c := range fieldCases {
+		fields[c.name] = c.in
+	}
+	return &binlog.Record{
+		Version:    binlog.Version,
+		Time:       ts,
+		Level:      lvl,
+		Message:    "round trip " + string(lvl),
+		Context:    "billing",
+		Source:     "svc",
+		TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:     "00f067aa0ba902b7",
+		TraceFlags: "01",
+		Caller:     "main.go:10 main.main",
+		Severity:   lvl.Severity(),
+		Tags:       map[string]string{"env": "prod"},
+		Fields:     fields,
+		Error:      "wrapped: boom",
+		Format:     "json",
+		Flags:      binlog.FlagShowColor | binlog.FlagShowFields,
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, c := range codecs {
+		for _, lvl := range levels {
+			t.Run(c.Name()+"/"+string(lvl), func(t *testing.T) {
+				in := record(lvl)
+				framed, err := binlog.Encode(c, in)
+				if err != nil {
+					t.Fatal(err)
+				}
+				out, err := binlog.NewReader(bytes.NewReader(framed)).Next()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !out.Time.Equal(in.Time) {
+					t.Errorf("time = %v, want %v", out.Time, in.Time)
+				}
+				got, want := *out, *in
+				got.Time, want.Time, got.Fields, want.Fields = time.Time{}, time.Time{}, nil, nil
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("record:\n got %+v\nwant %+v", got, want)
+				}
+				for _, fc := range fieldCases {
+					if v := utc(out.Fields[fc.name]); !reflect.DeepEqual(v, fc.want) {
+						t.Errorf("field %s = %#v, want %#v", fc.name, v, fc.want)
+					}
+				}
+			})
+		}
+	}
+}
+
+// Um stream com os três codecs misturados é lido na ordem.
+func TestReaderMixedStream(t *testing.T) {
+	var stream bytes.Buffer
+	for i, c := range codecs {
+		b, err := binlog.Encode(c, &binlog.Record{Time: ts, Level: levels[i], Message: c.Name()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream.Write(b)
+	}
+	rd := binlog.NewReader(&stream)
+	for _, c := range codecs {
+		r, err := rd.Next()
+		if err != nil || r.Message != c.Name() || r.Version != binlog.Version {
+			t.Fatalf("Next = %+v, %v", r, err)
+		}
+	}
+	if _, err := rd.Next(); err != io.EOF {
+		t.Errorf("end of stream = %v, want io.EOF", err)
+	}
+}
+
+func TestReaderTruncated(t *testing.T) {
+	for _, c := range codecs {
+		b, _ := binlog.Encode(c, record(kbx.LevelInfo))
+		for _, n := range []int{1, len(b) / 2, len(b) - 1} {
+			if _, err := binlog.NewReader(bytes.NewReader(b[:n])).Next(); !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Errorf("%s cut at %d: %v", c.Name(), n, err)
+			}
+		}
+	}
+}
+
+// utc põe os tempos decodificados em UTC pra comparar com DeepEqual.
+func utc(v any) any {
+	switch x := v.(type) {
+	case time.Time:
+		return x.UTC()
+	case map[string]any:
+		for k, e := range x {
+			x[k] = utc(e)
+		}
+	case []any:
+		for i, e := range x {
+			x[i] = utc(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,338 @@
+package binlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// cborCodec implementa o esquema em CBOR (RFC 8949). Tempos usam a tag 0
+// (texto RFC 3339); na leitura a tag 1 (época) também é aceita.
+type cborCodec struct{}
+
+func (cborCodec) Name() string { return "cbor" }
+
+func (cborCodec) Marshal(r *Record) ([]byte, error) {
+	w := &cborWriter{buf: make([]byte, 0, 256)}
+	encodeRecord(w, r)
+	return w.buf, nil
+}
+
+func (cborCodec) Unmarshal(p []byte) (*Record, error) {
+	return decodeRecord(&cborReader{p: p})
+}
+
+// Tipos maiores do CBOR.
+const (
+	cborUint byte = iota << 5
+	cborNegInt
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// cborIndefinite é a informação adicional de tamanho indefinido.
+const cborIndefinite = 31
+
+type cborWriter struct {
+	buf []byte
+}
+
+// head escreve o tipo maior com o argumento na forma mais curta.
+func (w *cborWriter) head(major byte, n uint64) {
+	switch {
+	case n < 24:
+		w.buf = append(w.buf, major|byte(n))
+	case n <= math.MaxUint8:
+		w.buf = append(w.buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, major|26), uint32(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint64(append(w.buf, major|27), n)
+	}
+}
+
+func (w *cborWriter) writeNil() { w.buf = append(w.buf, 0xf6) }
+
+func (w *cborWriter) writeBool(b bool) {
+	if b {
+		w.buf = append(w.buf, 0xf5)
+		return
+	}
+	w.buf = append(w.buf, 0xf4)
+}
+
+func (w *cborWriter) writeInt(n int64) {
+	if n >= 0 {
+		w.head(cborUint, uint64(n))
+		return
+	}
+	w.head(cborNegInt, uint64(-1-n))
+}
+
+func (w *cborWriter) writeUint(n uint64) { w.head(cborUint, n) }
+
+func (w *cborWriter) writeFloat(f float64) {
+	w.buf = binary.BigEndian.AppendUint64(append(w.buf, 0xfb), math.Float64bits(f))
+}
+
+func (w *cborWriter) writeString(s string) {
+	w.head(cborText, uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *cborWriter) writeBytes(b []byte) {
+	w.head(cborBytes, uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *cborWriter) writeTime(t time.Time) {
+	w.head(cborTag, 0)
+	w.writeString(t.Format(time.RFC3339Nano))
+}
+
+func (w *cborWriter) writeArrayHead(n int) { w.head(cborArray, uint64(n)) }
+func (w *cborWriter) writeMapHead(n int)   { w.head(cborMap, uint64(n)) }
+
+type cborReader struct {
+	p []byte
+}
+
+func (r *cborReader) rest() int { return len(r.p) }
+
+func (r *cborReader) take(n uint64) ([]byte, error) {
+	if n > uint64(len(r.p)) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := r.p[:n]
+	r.p = r.p[n:]
+	return b, nil
+}
+
+// head lê o byte inicial e o argumento. indefinite indica tamanho
+// indefinido (informação adicional 31).
+func (r *cborReader) head() (major, info byte, arg uint64, indefinite bool, err error) {
+	b, err := r.take(1)
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	major, info = b[0]&0xe0, b[0]&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), false, nil
+	case info == cborIndefinite:
+		return major, info, 0, true, nil
+	case info > 27:
+		return 0, 0, 0, false, fmt.Errorf("binlog: cbor: reserved additional info %d", info)
+	}
+	size := uint64(1) << (info - 24)
+	p, err := r.take(size)
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	switch size {
+	case 1:
+		arg = uint64(p[0])
+	case 2:
+		arg = uint64(binary.BigEndian.Uint16(p))
+	case 4:
+		arg = uint64(binary.BigEndian.Uint32(p))
+	default:
+		arg = binary.BigEndian.Uint64(p)
+	}
+	return major, info, arg, false, nil
+}
+
+// isBreak consome o marcador de fim (0xff) de um item indefinido.
+func (r *cborReader) isBreak() bool {
+	if len(r.p) > 0 && r.p[0] == 0xff {
+		r.p = r.p[1:]
+		return true
+	}
+	return false
+}
+
+func (r *cborReader) readMapHead() (int, error) {
+	major, _, n, indefinite, err := r.head()
+	if err != nil {
+		return 0, err
+	}
+	if major != cborMap || indefinite {
+		return 0, fmt.Errorf("binlog: cbor: expected definite-length map, got major type %d", major>>5)
+	}
+	if n > uint64(len(r.p)) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return int(n), nil
+}
+
+func (r *cborReader) readValue(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("binlog: cbor: nesting deeper than %d", maxDepth)
+	}
+	major, info, arg, indefinite, err := r.head()
+	if err != nil {
+		return nil, err
+	}
+	if indefinite && (major == cborUint || major == cborNegInt || major == cborTag) {
+		return nil, fmt.Errorf("binlog: cbor: indefinite length on major type %d", major>>5)
+	}
+	switch major {
+	case cborUint:
+		return normInt(arg), nil
+	case cborNegInt:
+		if arg > math.MaxInt64 {
+			return nil, fmt.Errorf("binlog: cbor: negative integer out of range")
+		}
+		return -1 - int64(arg), nil
+	case cborBytes, cborText:
+		b, err := r.chunks(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborText {
+			return string(b), nil
+		}
+		return b, nil
+	case cborArray:
+		out := []any{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && r.isBreak() {
+				break
+			}
+			if !indefinite && arg > uint64(len(r.p)) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			v, err := r.readValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case cborMap:
+		if !indefinite && arg > uint64(len(r.p)) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		out := map[string]any{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && r.isBreak() {
+				break
+			}
+			k, err := r.readValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			v, err := r.readValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			out[mapKey(k)] = v
+		}
+		return out, nil
+	case cborTag:
+		v, err := r.readValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return cborTagged(arg, v)
+	}
+	// cborSimple: simples e floats.
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return halfFloat(uint16(arg)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(arg))), nil
+	case 27:
+		return math.Float64frombits(arg), nil
+	}
+	return nil, fmt.Errorf("binlog: cbor: unsupported simple value %d", info)
+}
+
+// chunks lê bytes ou texto, juntando os pedaços de um item indefinido.
+func (r *cborReader) chunks(major byte, n uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		b, err := r.take(n)
+		return bytes.Clone(b), err
+	}
+	var out []byte
+	for !r.isBreak() {
+		m, _, size, ind, err := r.head()
+		if err != nil {
+			return nil, err
+		}
+		if m != major || ind {
+			return nil, fmt.Errorf("binlog: cbor: bad chunk in indefinite string")
+		}
+		b, err := r.take(size)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b...)
+	}
+	return out, nil
+}
+
+// cborTagged interpreta as tags de tempo; as demais devolvem o valor
+// sem a tag.
+func cborTagged(tag uint64, v any) (any, error) {
+	switch tag {
+	case 0:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("binlog: cbor: tag 0 wants text, got %T", v)
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("binlog: cbor: %w", err)
+		}
+		return t, nil
+	case 1:
+		switch x := v.(type) {
+		case int64:
+			return time.Unix(x, 0).UTC(), nil
+		case float64:
+			sec, frac := math.Modf(x)
+			return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+		}
+		return nil, fmt.Errorf("binlog: cbor: tag 1 wants a number, got %T", v)
+	}
+	return v, nil
+}
+
+// halfFloat converte um float16 (IEEE 754 binary16).
+func halfFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,351 @@
+package binlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// msgpackCodec implementa o esquema em MessagePack
+// (https://github.com/msgpack/msgpack/blob/master/spec.md). Tempos usam a
+// extensão de timestamp (-1) de 96 bits.
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string { return "msgpack" }
+
+func (msgpackCodec) Marshal(r *Record) ([]byte, error) {
+	w := &msgpackWriter{buf: make([]byte, 0, 256)}
+	encodeRecord(w, r)
+	return w.buf, nil
+}
+
+func (msgpackCodec) Unmarshal(p []byte) (*Record, error) {
+	return decodeRecord(&msgpackReader{p: p})
+}
+
+const msgpackExtTime = -1
+
+type msgpackWriter struct {
+	buf []byte
+}
+
+func (w *msgpackWriter) writeNil() { w.buf = append(w.buf, 0xc0) }
+
+func (w *msgpackWriter) writeBool(b bool) {
+	if b {
+		w.buf = append(w.buf, 0xc3)
+		return
+	}
+	w.buf = append(w.buf, 0xc2)
+}
+
+func (w *msgpackWriter) writeInt(n int64) {
+	switch {
+	case n >= 0:
+		w.writeUint(uint64(n))
+	case n >= -32:
+		w.buf = append(w.buf, byte(n)) // negative fixint
+	case n >= math.MinInt8:
+		w.buf = append(w.buf, 0xd0, byte(n))
+	case n >= math.MinInt16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xd1), uint16(n))
+	case n >= math.MinInt32:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xd2), uint32(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint64(append(w.buf, 0xd3), uint64(n))
+	}
+}
+
+func (w *msgpackWriter) writeUint(n uint64) {
+	switch {
+	case n <= 0x7f:
+		w.buf = append(w.buf, byte(n)) // positive fixint
+	case n <= math.MaxUint8:
+		w.buf = append(w.buf, 0xcc, byte(n))
+	case n <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xcd), uint16(n))
+	case n <= math.MaxUint32:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xce), uint32(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint64(append(w.buf, 0xcf), n)
+	}
+}
+
+func (w *msgpackWriter) writeFloat(f float64) {
+	w.buf = binary.BigEndian.AppendUint64(append(w.buf, 0xcb), math.Float64bits(f))
+}
+
+func (w *msgpackWriter) writeString(s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		w.buf = append(w.buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		w.buf = append(w.buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xda), uint16(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xdb), uint32(n))
+	}
+	w.buf = append(w.buf, s...)
+}
+
+func (w *msgpackWriter) writeBytes(b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		w.buf = append(w.buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xc5), uint16(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xc6), uint32(n))
+	}
+	w.buf = append(w.buf, b...)
+}
+
+func (w *msgpackWriter) writeTime(t time.Time) {
+	// ext8, tipo -1 (0xff), 12 bytes: nsec uint32 + sec int64.
+	w.buf = append(w.buf, 0xc7, 12, 0xff)
+	w.buf = binary.BigEndian.AppendUint32(w.buf, uint32(t.Nanosecond()))
+	w.buf = binary.BigEndian.AppendUint64(w.buf, uint64(t.Unix()))
+}
+
+func (w *msgpackWriter) writeArrayHead(n int) {
+	switch {
+	case n < 16:
+		w.buf = append(w.buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xdc), uint16(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xdd), uint32(n))
+	}
+}
+
+func (w *msgpackWriter) writeMapHead(n int) {
+	switch {
+	case n < 16:
+		w.buf = append(w.buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, 0xde), uint16(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, 0xdf), uint32(n))
+	}
+}
+
+type msgpackReader struct {
+	p []byte
+}
+
+func (r *msgpackReader) rest() int { return len(r.p) }
+
+func (r *msgpackReader) take(n int) ([]byte, error) {
+	if n < 0 || n > len(r.p) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := r.p[:n]
+	r.p = r.p[n:]
+	return b, nil
+}
+
+func (r *msgpackReader) byte1() (byte, error) {
+	b, err := r.take(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (r *msgpackReader) uintN(size int) (uint64, error) {
+	b, err := r.take(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (r *msgpackReader) readMapHead() (int, error) {
+	c, err := r.byte1()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case c&0xf0 == 0x80:
+		return int(c & 0x0f), nil
+	case c == 0xde:
+		n, err := r.uintN(2)
+		return int(n), err
+	case c == 0xdf:
+		n, err := r.uintN(4)
+		return int(n), err
+	}
+	return 0, fmt.Errorf("binlog: msgpack: expected map, got 0x%02x", c)
+}
+
+func (r *msgpackReader) readValue(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("binlog: msgpack: nesting deeper than %d", maxDepth)
+	}
+	c, err := r.byte1()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0:
+		return r.str(int(c & 0x1f))
+	case c&0xf0 == 0x90:
+		return r.array(int(c&0x0f), depth)
+	case c&0xf0 == 0x80:
+		return r.mapBody(int(c&0x0f), depth)
+	}
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := r.uintN(1 << (c - 0xcc))
+		return normInt(n), err
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		n, err := r.uintN(size)
+		if err != nil {
+			return nil, err
+		}
+		switch size {
+		case 1:
+			return int64(int8(n)), nil
+		case 2:
+			return int64(int16(n)), nil
+		case 4:
+			return int64(int32(n)), nil
+		}
+		return int64(n), nil
+	case 0xca:
+		n, err := r.uintN(4)
+		return float64(math.Float32frombits(uint32(n))), err
+	case 0xcb:
+		n, err := r.uintN(8)
+		return math.Float64frombits(n), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := r.uintN(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return r.str(int(n))
+	case 0xc4, 0xc5, 0xc6:
+		n, err := r.uintN(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.take(int(n))
+		return bytes.Clone(b), err
+	case 0xdc, 0xdd:
+		n, err := r.uintN(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return r.array(int(n), depth)
+	case 0xde, 0xdf:
+		n, err := r.uintN(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return r.mapBody(int(n), depth)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return r.ext(1 << (c - 0xd4))
+	case 0xc7, 0xc8, 0xc9:
+		n, err := r.uintN(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return r.ext(int(n))
+	}
+	return nil, fmt.Errorf("binlog: msgpack: unsupported type 0x%02x", c)
+}
+
+func (r *msgpackReader) str(n int) (any, error) {
+	b, err := r.take(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (r *msgpackReader) array(n int, depth int) (any, error) {
+	if n > len(r.p) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	out := make([]any, n)
+	for i := range out {
+		v, err := r.readValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func (r *msgpackReader) mapBody(n int, depth int) (any, error) {
+	if n > len(r.p) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	out := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		k, err := r.readValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := r.readValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		out[mapKey(k)] = v
+	}
+	return out, nil
+}
+
+// ext lê uma extensão: o timestamp (-1) vira time.Time; as demais viram
+// os bytes crus.
+func (r *msgpackReader) ext(size int) (any, error) {
+	t, err := r.byte1()
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.take(size)
+	if err != nil {
+		return nil, err
+	}
+	if int8(t) != msgpackExtTime {
+		return append([]byte(nil), data...), nil
+	}
+	switch size {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0).UTC(), nil
+	case 8:
+		v := binary.BigEndian.Uint64(data)
+		return time.Unix(int64(v&0x3ffffffff), int64(v>>34)).UTC(), nil
+	case 12:
+		nsec := binary.BigEndian.Uint32(data[:4])
+		sec := int64(binary.BigEndian.Uint64(data[4:]))
+		return time.Unix(sec, int64(nsec)).UTC(), nil
+	}
+	return nil, fmt.Errorf("binlog: msgpack: bad timestamp size %d", size)
+}
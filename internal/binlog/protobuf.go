@@ -0,0 +1,459 @@
+package binlog
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// protoCodec implementa o esquema em protobuf, conforme
+// proto/logz/v1/entry.proto. O número de cada campo é a chave do esquema
+// mais um. Registros protobuf são enquadrados por um varint (Delimited),
+// não pelo prefixo de 4 bytes.
+type protoCodec struct{}
+
+func (protoCodec) Name() string { return "protobuf" }
+
+func (protoCodec) delimited() bool { return true }
+
+// Campos da mensagem Value.
+const (
+	pvNull protowire.Number = iota + 1
+	pvBool
+	pvInt
+	pvUint
+	pvDouble
+	pvString
+	pvBytes
+	pvTime
+	pvList
+	pvMap
+)
+
+func (protoCodec) Marshal(r *Record) ([]byte, error) {
+	b := make([]byte, 0, 256)
+	for _, p := range r.fields() {
+		num := protowire.Number(p.key + 1)
+		switch v := p.value.(type) {
+		case string:
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			b = protowire.AppendString(b, v)
+		case uint64:
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			b = protowire.AppendVarint(b, v)
+		case int64:
+			if p.key == keyTimestamp {
+				b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+				b = protowire.AppendFixed64(b, uint64(v))
+				continue
+			}
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			b = protowire.AppendVarint(b, uint64(v))
+		case map[string]string:
+			for _, k := range sortedStringKeys(v) {
+				entry := protowire.AppendTag(nil, 1, protowire.BytesType)
+				entry = protowire.AppendString(entry, k)
+				entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+				entry = protowire.AppendString(entry, v[k])
+				b = appendMessage(b, num, entry)
+			}
+		case map[string]any:
+			b = appendProtoMap(b, num, v)
+		default:
+			return nil, fmt.Errorf("binlog: protobuf: key %d: unexpected %T", p.key, p.value)
+		}
+	}
+	return b, nil
+}
+
+func (protoCodec) Unmarshal(p []byte) (*Record, error) {
+	var keys []int64
+	var values []any
+	tags := map[string]any{}
+	fields := map[string]any{}
+	for len(p) > 0 {
+		num, typ, n := protowire.ConsumeTag(p)
+		if n < 0 {
+			return nil, protoErr(n)
+		}
+		p = p[n:]
+		key := int64(num) - 1
+		var v any
+		switch {
+		case key == keyTimestamp && typ == protowire.Fixed64Type:
+			x, n := protowire.ConsumeFixed64(p)
+			if n < 0 {
+				return nil, protoErr(n)
+			}
+			p, v = p[n:], int64(x)
+		case (key == keyVersion || key == keySeverity || key == keyFlags) && typ == protowire.VarintType:
+			x, n := protowire.ConsumeVarint(p)
+			if n < 0 {
+				return nil, protoErr(n)
+			}
+			p, v = p[n:], int64(x)
+			if key == keySeverity {
+				v = int64(int32(x))
+			}
+		case (key == keyTags || key == keyFields) && typ == protowire.BytesType:
+			msg, n := protowire.ConsumeBytes(p)
+			if n < 0 {
+				return nil, protoErr(n)
+			}
+			p = p[n:]
+			k, val, err := decodeProtoEntry(msg, key == keyFields, 1)
+			if err != nil {
+				return nil, err
+			}
+			if key == keyTags {
+				tags[k] = val
+			} else {
+				fields[k] = val
+			}
+			continue
+		case key >= keyLevel && key <= keyFormat && typ == protowire.BytesType &&
+			key != keySeverity && key != keyTags && key != keyFields:
+			s, n := protowire.ConsumeString(p)
+			if n < 0 {
+				return nil, protoErr(n)
+			}
+			p, v = p[n:], s
+		case key >= keyVersion && key <= keyFlags:
+			return nil, fmt.Errorf("binlog: protobuf: field %d: unexpected wire type %d", num, typ)
+		default:
+			// campo de uma versão mais nova do esquema.
+			n := protowire.ConsumeFieldValue(num, typ, p)
+			if n < 0 {
+				return nil, protoErr(n)
+			}
+			p = p[n:]
+			continue
+		}
+		keys, values = append(keys, key), append(values, v)
+	}
+	if len(tags) > 0 {
+		keys, values = append(keys, keyTags), append(values, tags)
+	}
+	if len(fields) > 0 {
+		keys, values = append(keys, keyFields), append(values, fields)
+	}
+	return fromPairs(keys, values)
+}
+
+func protoErr(n int) error {
+	return fmt.Errorf("binlog: protobuf: %w", protowire.ParseError(n))
+}
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+// appendProtoMap escreve um map<string, Value> como campo num.
+func appendProtoMap(b []byte, num protowire.Number, m map[string]any) []byte {
+	for _, k := range sortedKeys(m) {
+		w := &protoValueWriter{}
+		encodeValue(w, m[k], 1)
+		entry := protowire.AppendTag(nil, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, k)
+		entry = appendMessage(entry, 2, w.out)
+		b = appendMessage(b, num, entry)
+	}
+	return b
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// protoValueWriter implementa encoder montando mensagens Value. Listas e
+// mapas chegam como cabeçalho + itens, mas em protobuf a mensagem filha
+// vem prefixada pelo tamanho: os contêineres abertos ficam numa pilha até
+// receberem todos os itens.
+type protoValueWriter struct {
+	stack []protoContainer
+	out   []byte
+}
+
+type protoContainer struct {
+	isMap   bool
+	left    int
+	buf     []byte
+	key     string
+	haveKey bool
+}
+
+// value entrega uma Value pronta ao contêiner do topo (ou ao resultado).
+// s é o texto da Value quando ela é uma string, usado como chave de mapa.
+func (w *protoValueWriter) value(msg []byte, s string) {
+	for {
+		if len(w.stack) == 0 {
+			w.out = msg
+			return
+		}
+		top := &w.stack[len(w.stack)-1]
+		if top.isMap && !top.haveKey {
+			top.key, top.haveKey = s, true
+			return
+		}
+		if top.isMap {
+			entry := protowire.AppendTag(nil, 1, protowire.BytesType)
+			entry = protowire.AppendString(entry, top.key)
+			entry = appendMessage(entry, 2, msg)
+			top.buf = appendMessage(top.buf, 1, entry)
+			top.haveKey = false
+		} else {
+			top.buf = appendMessage(top.buf, 1, msg)
+		}
+		top.left--
+		if top.left > 0 {
+			return
+		}
+		msg, s = w.pop(), ""
+	}
+}
+
+// pop fecha o contêiner do topo e devolve a Value que o embrulha.
+func (w *protoValueWriter) pop() []byte {
+	top := w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+	if top.isMap {
+		return appendMessage(nil, pvMap, top.buf)
+	}
+	return appendMessage(nil, pvList, top.buf)
+}
+
+func (w *protoValueWriter) open(isMap bool, n int) {
+	w.stack = append(w.stack, protoContainer{isMap: isMap, left: n})
+	if n == 0 {
+		w.value(w.pop(), "")
+	}
+}
+
+func (w *protoValueWriter) writeNil() {
+	w.value(protowire.AppendVarint(protowire.AppendTag(nil, pvNull, protowire.VarintType), 0), "")
+}
+
+func (w *protoValueWriter) writeBool(b bool) {
+	w.value(protowire.AppendVarint(protowire.AppendTag(nil, pvBool, protowire.VarintType), protowire.EncodeBool(b)), "")
+}
+
+func (w *protoValueWriter) writeInt(n int64) {
+	w.value(protowire.AppendVarint(protowire.AppendTag(nil, pvInt, protowire.VarintType), protowire.EncodeZigZag(n)), "")
+}
+
+func (w *protoValueWriter) writeUint(n uint64) {
+	if n <= math.MaxInt64 {
+		w.writeInt(int64(n))
+		return
+	}
+	w.value(protowire.AppendVarint(protowire.AppendTag(nil, pvUint, protowire.VarintType), n), "")
+}
+
+func (w *protoValueWriter) writeFloat(f float64) {
+	w.value(protowire.AppendFixed64(protowire.AppendTag(nil, pvDouble, protowire.Fixed64Type), math.Float64bits(f)), "")
+}
+
+func (w *protoValueWriter) writeString(s string) {
+	w.value(protowire.AppendString(protowire.AppendTag(nil, pvString, protowire.BytesType), s), s)
+}
+
+func (w *protoValueWriter) writeBytes(b []byte) {
+	w.value(protowire.AppendBytes(protowire.AppendTag(nil, pvBytes, protowire.BytesType), b), string(b))
+}
+
+func (w *protoValueWriter) writeTime(t time.Time) {
+	w.value(protowire.AppendFixed64(protowire.AppendTag(nil, pvTime, protowire.Fixed64Type), uint64(t.UnixNano())), "")
+}
+
+func (w *protoValueWriter) writeArrayHead(n int) { w.open(false, n) }
+func (w *protoValueWriter) writeMapHead(n int)   { w.open(true, n) }
+
+// decodeProtoEntry lê uma entrada de map<string, string> (value=false) ou
+// map<string, Value> (value=true).
+func decodeProtoEntry(p []byte, value bool, depth int) (string, any, error) {
+	var key string
+	var val any
+	if !value {
+		val = ""
+	}
+	for len(p) > 0 {
+		num, typ, n := protowire.ConsumeTag(p)
+		if n < 0 {
+			return "", nil, protoErr(n)
+		}
+		p = p[n:]
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			s, n := protowire.ConsumeString(p)
+			if n < 0 {
+				return "", nil, protoErr(n)
+			}
+			p, key = p[n:], s
+		case num == 2 && typ == protowire.BytesType:
+			b, n := protowire.ConsumeBytes(p)
+			if n < 0 {
+				return "", nil, protoErr(n)
+			}
+			p = p[n:]
+			if !value {
+				val = string(b)
+				continue
+			}
+			v, err := decodeProtoValue(b, depth+1)
+			if err != nil {
+				return "", nil, err
+			}
+			val = v
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, p)
+			if n < 0 {
+				return "", nil, protoErr(n)
+			}
+			p = p[n:]
+		}
+	}
+	return key, val, nil
+}
+
+// decodeProtoValue lê uma mensagem Value. Uma Value vazia (nenhum campo
+// do oneof) é nil.
+func decodeProtoValue(p []byte, depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("binlog: protobuf: nesting deeper than %d", maxDepth)
+	}
+	var out any
+	for len(p) > 0 {
+		num, typ, n := protowire.ConsumeTag(p)
+		if n < 0 {
+			return nil, protoErr(n)
+		}
+		p = p[n:]
+		var err error
+		switch typ {
+		case protowire.VarintType:
+			x, n := protowire.ConsumeVarint(p)
+			if n < 0 {
+				return nil, protoErr(n)
+			}
+			p = p[n:]
+			switch num {
+			case pvNull:
+				out = nil
+			case pvBool:
+				out = protowire.DecodeBool(x)
+			case pvInt:
+				out = protowire.DecodeZigZag(x)
+			case pvUint:
+				out = normInt(x)
+			}
+		case protowire.Fixed64Type:
+			x, n := protowire.ConsumeFixed64(p)
+			if n < 0 {
+				return nil, protoErr(n)
+			}
+			p = p[n:]
+			switch num {
+			case pvDouble:
+				out = math.Float64frombits(x)
+			case pvTime:
+				out = time.Unix(0, int64(x)).UTC()
+			}
+		case protowire.BytesType:
+			b, n := protowire.ConsumeBytes(p)
+			if n < 0 {
+				return nil, protoErr(n)
+			}
+			p = p[n:]
+			switch num {
+			case pvString:
+				out = string(b)
+			case pvBytes:
+				out = bytes.Clone(b)
+			case pvList:
+				out, err = decodeProtoList(b, depth)
+			case pvMap:
+				out, err = decodeProtoMap(b, depth)
+			}
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, p)
+			if n < 0 {
+				return nil, protoErr(n)
+			}
+			p = p[n:]
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func decodeProtoList(p []byte, depth int) ([]any, error) {
+	out := []any{}
+	for len(p) > 0 {
+		num, typ, n := protowire.ConsumeTag(p)
+		if n < 0 {
+			return nil, protoErr(n)
+		}
+		p = p[n:]
+		if num != 1 || typ != protowire.BytesType {
+			n := protowire.ConsumeFieldValue(num, typ, p)
+			if n < 0 {
+				return nil, protoErr(n)
+			}
+			p = p[n:]
+			continue
+		}
+		b, n := protowire.ConsumeBytes(p)
+		if n < 0 {
+			return nil, protoErr(n)
+		}
+		p = p[n:]
+		v, err := decodeProtoValue(b, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func decodeProtoMap(p []byte, depth int) (map[string]any, error) {
+	out := map[string]any{}
+	for len(p) > 0 {
+		num, typ, n := protowire.ConsumeTag(p)
+		if n < 0 {
+			return nil, protoErr(n)
+		}
+		p = p[n:]
+		if num != 1 || typ != protowire.BytesType {
+			n := protowire.ConsumeFieldValue(num, typ, p)
+			if n < 0 {
+				return nil, protoErr(n)
+			}
+			p = p[n:]
+			continue
+		}
+		b, n := protowire.ConsumeBytes(p)
+		if n < 0 {
+			return nil, protoErr(n)
+		}
+		p = p[n:]
+		k, v, err := decodeProtoEntry(b, true, depth)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = v
+	}
+	return out, nil
+}
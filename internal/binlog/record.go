@@ -0,0 +1,345 @@
+// Package binlog é o formato binário dos logs: um esquema estável pra
+// Entry e três codecs (MessagePack, CBOR e protobuf), com cada registro
+// prefixado pelo tamanho pra que arquivos e streams possam ser lidos de
+// volta.
+//
+// Enquadramento: registros MessagePack e CBOR levam um uint32 big-endian
+// com o tamanho do payload, seguido do payload (um mapa); o codec é
+// reconhecido pelo primeiro byte do payload. Registros protobuf
+// (proto/logz/v1/entry.proto) levam o tamanho em varint, como o
+// protodelim. Um registro protobuf nunca tem menos de 4 bytes, então o
+// primeiro byte do prefixo separa os dois casos (0x00-0x03: uint32) e o
+// Reader lê qualquer mistura.
+//
+// Esquema (chaves inteiras do mapa; chaves vazias são omitidas e chaves
+// desconhecidas são ignoradas na leitura, pra que versões futuras só
+// acrescentem):
+//
+//	 0 v            versão do esquema (1)
+//	 1 ts           timestamp, Unix em nanossegundos (UTC)
+//	 2 level        nível
+//	 3 msg          mensagem
+//	 4 ctx          contexto
+//	 5 src          source
+//	 6 trace_id
+//	 7 span_id
+//	 8 trace_flags
+//	 9 caller
+//	10 sev          severidade
+//	11 tags         mapa texto -> texto
+//	12 fields       mapa texto -> valor
+//	13 error        texto do erro
+//	14 format
+//	15 flags        bits: 1 show_color, 2 show_icon, 4 show_trace_id,
+//	                8 show_caller, 16 show_stack, 32 show_fields
+package binlog
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// Version é a versão do esquema gravada em cada registro.
+const Version = 1
+
+// Chaves do esquema.
+const (
+	keyVersion = iota
+	keyTimestamp
+	keyLevel
+	keyMessage
+	keyContext
+	keySource
+	keyTraceID
+	keySpanID
+	keyTraceFlags
+	keyCaller
+	keySeverity
+	keyTags
+	keyFields
+	keyError
+	keyFormat
+	keyFlags
+)
+
+// Bits de Record.Flags.
+const (
+	FlagShowColor uint64 = 1 << iota
+	FlagShowIcon
+	FlagShowTraceID
+	FlagShowCaller
+	FlagShowStack
+	FlagShowFields
+)
+
+// MaxRecordSize limita o tamanho de um registro na leitura, pra que um
+// arquivo corrompido não vire uma alocação gigante.
+const MaxRecordSize = 64 << 20
+
+// Record é a Entry no esquema binário.
+type Record struct {
+	Version    int
+	Time       time.Time
+	Level      kbx.Level
+	Message    string
+	Context    string
+	Source     string
+	TraceID    string
+	SpanID     string
+	TraceFlags string
+	Caller     string
+	Severity   int
+	Tags       map[string]string
+	Fields     map[string]any
+	Error      string
+	Format     string
+	Flags      uint64
+}
+
+// Codec serializa um Record num payload (sem o prefixo de tamanho).
+type Codec interface {
+	Name() string
+	Marshal(r *Record) ([]byte, error)
+	Unmarshal(p []byte) (*Record, error)
+}
+
+// Codecs embutidos.
+var (
+	MsgPack Codec = msgpackCodec{}
+	CBOR    Codec = cborCodec{}
+	Proto   Codec = protoCodec{}
+)
+
+// CodecFor reconhece o codec pelo primeiro byte do payload (um mapa em
+// MessagePack ou em CBOR).
+func CodecFor(p []byte) (Codec, error) {
+	if len(p) == 0 {
+		return nil, errors.New("binlog: empty record")
+	}
+	switch b := p[0]; {
+	case b >= 0x80 && b <= 0x8f, b == 0xde, b == 0xdf:
+		return MsgPack, nil
+	case b >= 0xa0 && b <= 0xbb, b == 0xbf:
+		return CBOR, nil
+	}
+	return nil, fmt.Errorf("binlog: unknown record encoding (first byte 0x%02x)", p[0])
+}
+
+// Frame põe o prefixo de tamanho (uint32) na frente do payload.
+func Frame(payload []byte) []byte {
+	out := make([]byte, 4, 4+len(payload))
+	binary.BigEndian.PutUint32(out, uint32(len(payload)))
+	return append(out, payload...)
+}
+
+// FrameDelimited põe o prefixo de tamanho em varint na frente do payload.
+func FrameDelimited(payload []byte) []byte {
+	out := make([]byte, 0, binary.MaxVarintLen64+len(payload))
+	out = binary.AppendUvarint(out, uint64(len(payload)))
+	return append(out, payload...)
+}
+
+// Encode serializa r com c, já com o enquadramento do codec.
+func Encode(c Codec, r *Record) ([]byte, error) {
+	p, err := c.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := c.(interface{ delimited() bool }); ok && d.delimited() {
+		return FrameDelimited(p), nil
+	}
+	return Frame(p), nil
+}
+
+// Reader lê registros enquadrados de um arquivo ou stream.
+type Reader struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+// NewReader cria um Reader sobre r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next devolve o próximo registro; io.EOF no fim limpo do stream e
+// io.ErrUnexpectedEOF num registro cortado.
+func (rd *Reader) Next() (*Record, error) {
+	first, err := rd.r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	delimited := first[0] > 0x03
+	var n uint64
+	if delimited {
+		n, err = binary.ReadUvarint(rd.r)
+	} else {
+		var head [4]byte
+		_, err = io.ReadFull(rd.r, head[:])
+		n = uint64(binary.BigEndian.Uint32(head[:]))
+	}
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if n > MaxRecordSize {
+		return nil, fmt.Errorf("binlog: record of %d bytes exceeds the %d limit", n, MaxRecordSize)
+	}
+	if cap(rd.buf) < int(n) {
+		rd.buf = make([]byte, n)
+	}
+	p := rd.buf[:n]
+	if _, err := io.ReadFull(rd.r, p); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if delimited {
+		return Proto.Unmarshal(p)
+	}
+	c, err := CodecFor(p)
+	if err != nil {
+		return nil, err
+	}
+	return c.Unmarshal(p)
+}
+
+// fields devolve o registro como mapa de chaves inteiras, na ordem do
+// esquema, omitindo o que está vazio.
+func (r *Record) fields() []kv {
+	out := []kv{{keyVersion, uint64(Version)}, {keyTimestamp, r.Time.UnixNano()}}
+	add := func(k int, s string) {
+		if s != "" {
+			out = append(out, kv{k, s})
+		}
+	}
+	add(keyLevel, string(r.Level))
+	add(keyMessage, r.Message)
+	add(keyContext, r.Context)
+	add(keySource, r.Source)
+	add(keyTraceID, r.TraceID)
+	add(keySpanID, r.SpanID)
+	add(keyTraceFlags, r.TraceFlags)
+	add(keyCaller, r.Caller)
+	if r.Severity != 0 {
+		out = append(out, kv{keySeverity, int64(r.Severity)})
+	}
+	if len(r.Tags) > 0 {
+		out = append(out, kv{keyTags, r.Tags})
+	}
+	if len(r.Fields) > 0 {
+		out = append(out, kv{keyFields, r.Fields})
+	}
+	add(keyError, r.Error)
+	add(keyFormat, r.Format)
+	if r.Flags != 0 {
+		out = append(out, kv{keyFlags, r.Flags})
+	}
+	return out
+}
+
+type kv struct {
+	key   int
+	value any
+}
+
+// set preenche o campo do esquema a partir de um valor decodificado.
+// Tipos inesperados numa chave conhecida são erro; chaves desconhecidas
+// são ignoradas.
+func (r *Record) set(key int64, v any) error {
+	str := func() (string, error) {
+		s, ok := v.(string)
+		if !ok {
+			return "", fmt.Errorf("binlog: key %d: want string, got %T", key, v)
+		}
+		return s, nil
+	}
+	var err error
+	switch key {
+	case keyVersion:
+		var n int64
+		n, err = toInt(key, v)
+		r.Version = int(n)
+	case keyTimestamp:
+		var n int64
+		n, err = toInt(key, v)
+		r.Time = time.Unix(0, n).UTC()
+	case keyLevel:
+		var s string
+		s, err = str()
+		r.Level = kbx.Level(s)
+	case keyMessage:
+		r.Message, err = str()
+	case keyContext:
+		r.Context, err = str()
+	case keySource:
+		r.Source, err = str()
+	case keyTraceID:
+		r.TraceID, err = str()
+	case keySpanID:
+		r.SpanID, err = str()
+	case keyTraceFlags:
+		r.TraceFlags, err = str()
+	case keyCaller:
+		r.Caller, err = str()
+	case keySeverity:
+		var n int64
+		n, err = toInt(key, v)
+		r.Severity = int(n)
+	case keyTags:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("binlog: tags: want map, got %T", v)
+		}
+		r.Tags = make(map[string]string, len(m))
+		for k, tv := range m {
+			r.Tags[k] = fmt.Sprint(tv)
+		}
+	case keyFields:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("binlog: fields: want map, got %T", v)
+		}
+		r.Fields = m
+	case keyError:
+		r.Error, err = str()
+	case keyFormat:
+		r.Format, err = str()
+	case keyFlags:
+		var n int64
+		n, err = toInt(key, v)
+		r.Flags = uint64(n)
+	}
+	return err
+}
+
+func toInt(key int64, v any) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case uint64:
+		return int64(n), nil
+	}
+	return 0, fmt.Errorf("binlog: key %d: want integer, got %T", key, v)
+}
+
+// fromPairs monta o Record a partir das chaves do mapa de topo.
+// Registros de versões mais novas são lidos no que este esquema conhece.
+func fromPairs(keys []int64, values []any) (*Record, error) {
+	r := &Record{}
+	for i, k := range keys {
+		if err := r.set(k, values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
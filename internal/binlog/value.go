@@ -0,0 +1,264 @@
+package binlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// encoder é o que cada codec sabe escrever; encodeValue percorre os
+// valores e chama estes métodos.
+type encoder interface {
+	writeNil()
+	writeBool(b bool)
+	writeInt(n int64)
+	writeUint(n uint64)
+	writeFloat(f float64)
+	writeString(s string)
+	writeBytes(b []byte)
+	writeTime(t time.Time)
+	writeArrayHead(n int)
+	writeMapHead(n int)
+}
+
+// decoder lê um valor genérico e o cabeçalho de um mapa.
+type decoder interface {
+	readValue(depth int) (any, error)
+	readMapHead() (int, error)
+	rest() int
+}
+
+// maxDepth limita o aninhamento, na escrita e na leitura.
+const maxDepth = 32
+
+// encodeRecord escreve o mapa de topo do esquema.
+func encodeRecord(enc encoder, r *Record) {
+	kvs := r.fields()
+	enc.writeMapHead(len(kvs))
+	for _, p := range kvs {
+		enc.writeUint(uint64(p.key))
+		encodeValue(enc, p.value, 0)
+	}
+}
+
+// decodeRecord lê o mapa de topo do esquema. Chaves que não são inteiras
+// são puladas.
+func decodeRecord(dec decoder) (*Record, error) {
+	n, err := dec.readMapHead()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]int64, 0, n)
+	values := make([]any, 0, n)
+	for i := 0; i < n; i++ {
+		k, err := dec.readValue(1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := dec.readValue(1)
+		if err != nil {
+			return nil, err
+		}
+		switch key := k.(type) {
+		case int64:
+			keys, values = append(keys, key), append(values, v)
+		case uint64:
+			if key <= math.MaxInt64 {
+				keys, values = append(keys, int64(key)), append(values, v)
+			}
+		}
+	}
+	if dec.rest() != 0 {
+		return nil, fmt.Errorf("binlog: %d trailing bytes after record", dec.rest())
+	}
+	return fromPairs(keys, values)
+}
+
+// encodeValue escreve v, reduzido aos tipos que os dois codecs têm em
+// comum: nil, bool, inteiros, float, texto, bytes, tempo, listas e mapas
+// de texto. time.Duration vira inteiro (ns); erros e fmt.Stringer viram
+// texto; structs passam por encoding/json (com os números preservados).
+func encodeValue(enc encoder, v any, depth int) {
+	if depth > maxDepth {
+		enc.writeString(fmt.Sprint(v))
+		return
+	}
+	switch x := v.(type) {
+	case nil:
+		enc.writeNil()
+	case bool:
+		enc.writeBool(x)
+	case string:
+		enc.writeString(x)
+	case []byte:
+		enc.writeBytes(x)
+	case int:
+		enc.writeInt(int64(x))
+	case int8:
+		enc.writeInt(int64(x))
+	case int16:
+		enc.writeInt(int64(x))
+	case int32:
+		enc.writeInt(int64(x))
+	case int64:
+		enc.writeInt(x)
+	case uint:
+		enc.writeUint(uint64(x))
+	case uint8:
+		enc.writeUint(uint64(x))
+	case uint16:
+		enc.writeUint(uint64(x))
+	case uint32:
+		enc.writeUint(uint64(x))
+	case uint64:
+		enc.writeUint(x)
+	case float32:
+		enc.writeFloat(float64(x))
+	case float64:
+		enc.writeFloat(x)
+	case json.Number:
+		if n, err := x.Int64(); err == nil {
+			enc.writeInt(n)
+		} else if f, err := x.Float64(); err == nil {
+			enc.writeFloat(f)
+		} else {
+			enc.writeString(x.String())
+		}
+	case time.Time:
+		enc.writeTime(x)
+	case time.Duration:
+		enc.writeInt(int64(x))
+	case error:
+		enc.writeString(x.Error())
+	case map[string]any:
+		keys := sortedKeys(x)
+		enc.writeMapHead(len(keys))
+		for _, k := range keys {
+			enc.writeString(k)
+			encodeValue(enc, x[k], depth+1)
+		}
+	case map[string]string:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		enc.writeMapHead(len(keys))
+		for _, k := range keys {
+			enc.writeString(k)
+			enc.writeString(x[k])
+		}
+	case []any:
+		enc.writeArrayHead(len(x))
+		for _, e := range x {
+			encodeValue(enc, e, depth+1)
+		}
+	case fmt.Stringer:
+		enc.writeString(x.String())
+	default:
+		encodeReflect(enc, v, depth)
+	}
+}
+
+func encodeReflect(enc encoder, v any, depth int) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			enc.writeNil()
+			return
+		}
+		encodeValue(enc, rv.Elem().Interface(), depth+1)
+	case reflect.Bool:
+		enc.writeBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		enc.writeInt(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		enc.writeUint(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		enc.writeFloat(rv.Float())
+	case reflect.String:
+		enc.writeString(rv.String())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			enc.writeNil()
+			return
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			enc.writeBytes(b)
+			return
+		}
+		enc.writeArrayHead(rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			encodeValue(enc, rv.Index(i).Interface(), depth+1)
+		}
+	case reflect.Map:
+		if rv.IsNil() {
+			enc.writeNil()
+			return
+		}
+		m := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = iter.Value().Interface()
+		}
+		encodeValue(enc, m, depth)
+	case reflect.Struct:
+		b, err := json.Marshal(v)
+		if err != nil {
+			enc.writeString(fmt.Sprintf("%+v", v))
+			return
+		}
+		// UseNumber: inteiros grandes não passam por float64.
+		var generic any
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err := dec.Decode(&generic); err != nil {
+			enc.writeString(string(b))
+			return
+		}
+		encodeValue(enc, generic, depth+1)
+	default:
+		// func, chan, complex...: só o texto.
+		enc.writeString(fmt.Sprintf("%v", v))
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normInt devolve inteiros como int64 sempre que cabem.
+func normInt(n uint64) any {
+	if n <= math.MaxInt64 {
+		return int64(n)
+	}
+	return n
+}
+
+// mapKey converte a chave de um mapa decodificado em texto.
+func mapKey(k any) string {
+	switch x := k.(type) {
+	case string:
+		return x
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case uint64:
+		return strconv.FormatUint(x, 10)
+	case []byte:
+		return string(x)
+	}
+	return fmt.Sprint(k)
+}
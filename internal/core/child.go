@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// boundContext guarda o que um logger filho acrescenta a cada entry.
+// É imutável depois de criado: With sempre devolve um novo.
+type boundContext struct {
+	fields map[string]any
+	trace  kbx.TraceContext
+}
+
+func (b *boundContext) apply(entry *Entry) {
+	if b == nil || entry == nil {
+		return
+	}
+	if len(b.fields) > 0 {
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]any, len(b.fields))
+		}
+		// o que veio na própria entry tem prioridade.
+		for k, v := range b.fields {
+			if _, ok := entry.Fields[k]; !ok {
+				entry.Fields[k] = v
+			}
+		}
+	}
+	if b.trace.IsValid() && entry.TraceID == "" {
+		entry.WithTraceContext(b.trace)
+	}
+}
+
+func (b *boundContext) with(fields map[string]any, tc *kbx.TraceContext) *boundContext {
+	nb := &boundContext{fields: make(map[string]any)}
+	if b != nil {
+		for k, v := range b.fields {
+			nb.fields[k] = v
+		}
+		nb.trace = b.trace
+	}
+	for k, v := range fields {
+		nb.fields[k] = v
+	}
+	if tc != nil {
+		nb.trace = *tc
+	}
+	return nb
+}
+
+// child cria um Logger que compartilha opções, destino e trava com l,
+// mas com seu próprio boundContext.
+func (l *Logger) child(b *boundContext) *Logger {
+	return &Logger{
+		mu:     l.mu,
+		opts:   l.opts,
+		Logger: l.Logger,
+		bound:  b,
+	}
+}
+
+// With devolve um logger filho que acrescenta fields a todas as entries.
+// O filho compartilha a configuração com o pai: mudar formatter, saída ou
+// nível em um vale para os dois.
+func (l *Logger) With(fields map[string]any) *Logger {
+	return l.child(l.bound.with(fields, nil))
+}
+
+// WithTrace devolve um logger filho que carimba tc nas entries sem trace.
+func (l *Logger) WithTrace(tc kbx.TraceContext) *Logger {
+	return l.child(l.bound.with(nil, &tc))
+}
+
+// With é a versão tipada de Logger.With.
+func (l *LoggerZ[T]) With(fields map[string]any) *LoggerZ[T] {
+	return l.wrap(l.Logger.With(fields))
+}
+
+// WithTrace é a versão tipada de Logger.WithTrace.
+func (l *LoggerZ[T]) WithTrace(tc kbx.TraceContext) *LoggerZ[T] {
+	return l.wrap(l.Logger.WithTrace(tc))
+}
+
+func (l *LoggerZ[T]) wrap(inner *Logger) *LoggerZ[T] {
+	return &LoggerZ[T]{
+		ID:     uuid.New(),
+		optsZ:  l.optsZ,
+		Logger: inner,
+	}
+}
+
+type loggerCtxKey struct{}
+
+// ContextWithLogger guarda o logger no contexto (ex: o logger filho de uma
+// requisição HTTP).
+func ContextWithLogger[T kbx.Entry](ctx context.Context, l *LoggerZ[T]) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// LoggerFromContext devolve o logger guardado com ContextWithLogger.
+func LoggerFromContext[T kbx.Entry](ctx context.Context) (*LoggerZ[T], bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	l, ok := ctx.Value(loggerCtxKey{}).(*LoggerZ[T])
+	return l, ok && l != nil
+}
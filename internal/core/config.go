@@ -9,8 +9,11 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/kubex-ecosystem/logz/interfaces"
+	"github.com/kubex-ecosystem/logz/internal/dedup"
 	"github.com/kubex-ecosystem/logz/internal/formatter"
 	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/internal/redact"
+	"github.com/kubex-ecosystem/logz/internal/sampling"
 	"github.com/kubex-ecosystem/logz/internal/writer"
 )
 
@@ -57,10 +60,15 @@ const (
 
 type LogzAdvancedOptions struct {
 	// Hooks
-	Formatter formatter.Formatter   `json:"formatter,omitempty" yaml:"formatter,omitempty" mapstructure:"formatter,omitempty"`
-	Hooks     []interfaces.Hook     `json:"hooks,omitempty" yaml:"hooks,omitempty" mapstructure:"hooks,omitempty"`
-	LHooks    interfaces.LHook[any] `json:"l_hooks,omitempty" yaml:"l_hooks,omitempty" mapstructure:"l_hooks,omitempty"`
-	Metadata  map[string]any        `json:"metadata,omitempty" yaml:"metadata,omitempty" mapstructure:"metadata,omitempty"`
+	Formatter formatter.Formatter      `json:"formatter,omitempty" yaml:"formatter,omitempty" mapstructure:"formatter,omitempty"`
+	Hooks     []interfaces.Hook        `json:"hooks,omitempty" yaml:"hooks,omitempty" mapstructure:"hooks,omitempty"`
+	LHooks    interfaces.LHook[any]    `json:"l_hooks,omitempty" yaml:"l_hooks,omitempty" mapstructure:"l_hooks,omitempty"`
+	Metadata  map[string]any           `json:"metadata,omitempty" yaml:"metadata,omitempty" mapstructure:"metadata,omitempty"`
+	Redactor  *redact.Redactor         `json:"-" yaml:"-" mapstructure:"-"`
+	Sampler   *sampling.Sampler        `json:"-" yaml:"-" mapstructure:"-"`
+	Collapser *dedup.Collapser         `json:"-" yaml:"-" mapstructure:"-"`
+	Exporters []interfaces.EntryWriter `json:"-" yaml:"-" mapstructure:"-"`
+	Clock     kbx.Clock                `json:"-" yaml:"-" mapstructure:"-"`
 }
 
 type LoggerConfig = kbx.InitArgs
@@ -75,6 +83,7 @@ func NewLoggerOptions(initArgs *kbx.InitArgs) *LoggerOptionsImpl {
 		initArgs.Level = kbx.ParseLevel(kbx.GetValueOrDefaultSimple(kbx.GetEnvOrDefaultWithType("LOGZ_LOG_LEVEL", initArgs.Level.String()), kbx.DefaultLogLevel))
 		initArgs.MinLevel = kbx.ParseLevel(kbx.GetValueOrDefaultSimple(kbx.GetEnvOrDefaultWithType("LOGZ_LOG_MIN_LEVEL", initArgs.MinLevel.String()), kbx.DefaultLogMinLevel))
 		initArgs.MaxLevel = kbx.ParseLevel(kbx.GetValueOrDefaultSimple(kbx.GetEnvOrDefaultWithType("LOGZ_LOG_MAX_LEVEL", initArgs.MaxLevel.String()), kbx.DefaultLogMaxLevel))
+		initArgs.Format = kbx.GetValueOrDefaultSimple(initArgs.Format, kbx.GetEnvOrDefault("LOGZ_LOG_FORMAT", ""))
 		initArgs.Output = kbx.GetValueOrDefaultSimple(initArgs.Output, io.Writer(writer.ParseWriter(kbx.GetEnvOrDefault("LOGZ_LOG_OUTPUT", kbx.DefaultLogOutput))))
 		initArgs.ShowColor = kbx.GetValueOrDefaultSimple(initArgs.ShowColor, kbx.BoolPtr(kbx.GetEnvOrDefaultWithType("LOGZ_LOG_SHOW_COLOR", kbx.DefaultShowColor)))
 		initArgs.ShowIcons = kbx.GetValueOrDefaultSimple(initArgs.ShowIcons, kbx.BoolPtr(kbx.GetEnvOrDefaultWithType("LOGZ_LOG_SHOW_ICONS", kbx.DefaultShowIcons)))
@@ -161,12 +170,21 @@ func (o *LoggerOptionsImpl) Clone() *LoggerOptionsImpl {
 			LogzOutputOptions:    o.LogzOutputOptions,
 			LogzRotatingOptions:  o.LogzRotatingOptions,
 			LogzBufferingOptions: o.LogzBufferingOptions,
+			Redact:               o.Redact,
+			Sampling:             o.Sampling,
+			Dedup:                o.LoggerConfig.Dedup,
+			OTLP:                 o.OTLP,
 		},
 		LogzAdvancedOptions: &LogzAdvancedOptions{
 			Formatter: o.Formatter,
 			Hooks:     o.Hooks,
 			LHooks:    o.LHooks,
 			Metadata:  o.LogzAdvancedOptions.Metadata,
+			Redactor:  o.Redactor,
+			Sampler:   o.Sampler,
+			Collapser: o.Collapser,
+			Exporters: o.Exporters,
+			Clock:     o.Clock,
 		},
 	}
 }
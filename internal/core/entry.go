@@ -2,6 +2,7 @@
 package core
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"gopkg.in/yaml.v3"
 )
 
 // Entry é a unidade básica de log do sistema.
@@ -26,16 +28,22 @@ type Entry struct {
 	ShowFields  bool   `json:"show_fields,omitempty" yaml:"show_fields,omitempty" xml:"show_fields,omitempty" mapstructure:"show_fields,omitempty"`         // Habilita campos adicionais na saída
 	Format      string `json:"format,omitempty" yaml:"format,omitempty" xml:"format,omitempty" mapstructure:"format,omitempty"`                             // json / text / xml / etc.
 
-	Context  string `json:"ctx,omitempty" yaml:"ctx,omitempty" xml:"ctx,omitempty" mapstructure:"ctx,omitempty"`             // ex: "auth", "db", "billing"
-	Source   string `json:"src,omitempty" yaml:"src,omitempty" xml:"src,omitempty" mapstructure:"src,omitempty"`             // componente/módulo/serviço
-	TraceID  string `json:"trace,omitempty" yaml:"trace,omitempty" xml:"trace,omitempty" mapstructure:"trace,omitempty"`     // correlação
-	Caller   string `json:"caller,omitempty" yaml:"caller,omitempty" xml:"caller,omitempty" mapstructure:"caller,omitempty"` // arquivo:linha função
-	Severity int    `json:"sev,omitempty" yaml:"sev,omitempty" xml:"sev,omitempty" mapstructure:"sev,omitempty"`             // cache do Level.Severity()
+	Context    string `json:"ctx,omitempty" yaml:"ctx,omitempty" xml:"ctx,omitempty" mapstructure:"ctx,omitempty"`                                 // ex: "auth", "db", "billing"
+	Source     string `json:"src,omitempty" yaml:"src,omitempty" xml:"src,omitempty" mapstructure:"src,omitempty"`                                 // componente/módulo/serviço
+	TraceID    string `json:"trace,omitempty" yaml:"trace,omitempty" xml:"trace,omitempty" mapstructure:"trace,omitempty"`                         // correlação (W3C: 32 hex)
+	SpanID     string `json:"span_id,omitempty" yaml:"span_id,omitempty" xml:"span_id,omitempty" mapstructure:"span_id,omitempty"`                 // span corrente (W3C: 16 hex)
+	TraceFlags string `json:"trace_flags,omitempty" yaml:"trace_flags,omitempty" xml:"trace_flags,omitempty" mapstructure:"trace_flags,omitempty"` // ex: "01" = sampled
+	Caller     string `json:"caller,omitempty" yaml:"caller,omitempty" xml:"caller,omitempty" mapstructure:"caller,omitempty"`                     // arquivo:linha função
+	Severity   int    `json:"sev,omitempty" yaml:"sev,omitempty" xml:"sev,omitempty" mapstructure:"sev,omitempty"`                                 // cache do Level.Severity()
 
 	Tags   map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" xml:"-" mapstructure:"tags,omitempty"`       // metadados arbitrários
 	Fields map[string]any    `json:"fields,omitempty" yaml:"fields,omitempty" xml:"-" mapstructure:"fields,omitempty"` // dados estruturados arbitrários
 
 	Error error `json:"error,omitempty"` // erro associado (se houver)
+
+	// tsSet marca timestamp vindo de fora (WithTimestamp): o Clock do
+	// logger não o sobrescreve.
+	tsSet bool
 }
 
 func NewKbxEntry(level kbx.Level) (kbx.LogzEntry, error) {
@@ -88,11 +96,37 @@ func (e *Entry) WithLevel(l kbx.Level) kbx.LogzEntry {
 	return e
 }
 
+// WithTimestamp fixa o instante do evento (ex: o horário registrado por
+// outro logger). Um timestamp fixado assim vale mesmo com um Clock no logger.
+func (e *Entry) WithTimestamp(t time.Time) kbx.LogzEntry {
+	e.Timestamp = t.UTC()
+	e.tsSet = true
+	return e
+}
+
 func (e *Entry) WithTraceID(id string) kbx.LogzEntry {
 	e.TraceID = id
 	return e
 }
 
+func (e *Entry) WithSpanID(id string) kbx.LogzEntry {
+	e.SpanID = id
+	return e
+}
+
+// WithTraceContext preenche trace_id/span_id/trace_flags de uma vez e liga
+// a exibição do trace nos formatters de texto.
+func (e *Entry) WithTraceContext(tc kbx.TraceContext) kbx.LogzEntry {
+	if !tc.IsValid() {
+		return e
+	}
+	e.TraceID = tc.TraceID
+	e.SpanID = tc.SpanID
+	e.TraceFlags = tc.Flags()
+	e.ShowTraceID = true
+	return e
+}
+
 func (e *Entry) WithColor(color bool) kbx.LogzEntry {
 	e.ShowColor = color
 	return e
@@ -272,6 +306,20 @@ func (e *Entry) GetTraceID() string {
 	return e.TraceID
 }
 
+func (e *Entry) GetSpanID() string {
+	if e == nil {
+		return ""
+	}
+	return e.SpanID
+}
+
+func (e *Entry) GetTraceFlags() string {
+	if e == nil {
+		return ""
+	}
+	return e.TraceFlags
+}
+
 func (e *Entry) GetShowCaller() bool {
 	if e == nil {
 		return false
@@ -300,6 +348,13 @@ func (e *Entry) GetShowTraceID() bool {
 	return e.ShowTraceID
 }
 
+func (e *Entry) GetSource() string {
+	if e == nil {
+		return ""
+	}
+	return e.Source
+}
+
 func (e *Entry) GetFormat() string {
 	if e == nil {
 		return ""
@@ -345,6 +400,13 @@ func (e *Entry) Clone() kbx.Entry {
 	return &clone
 }
 
+func (e *Entry) GetError() error {
+	if e == nil {
+		return nil
+	}
+	return e.Error
+}
+
 func (e *Entry) GetMessage() string {
 	if e == nil {
 		return ""
@@ -371,8 +433,10 @@ func (e *Entry) Validate() error {
 	if e == nil {
 		return errors.New("entry is nil")
 	}
-	if e.Timestamp.IsZero() || e.Timestamp.Location() != time.UTC {
+	if e.Timestamp.IsZero() {
 		e.Timestamp = time.Now().UTC()
+	} else if e.Timestamp.Location() != time.UTC {
+		e.Timestamp = e.Timestamp.UTC()
 	}
 	if len(strings.TrimSpace(string(e.Level))) == 0 {
 		return errors.New("level is required")
@@ -387,6 +451,53 @@ func (e *Entry) Validate() error {
 	return nil
 }
 
+//
+// ---------- Timestamp formatado na serialização ----------
+//
+
+// Timed implementa kbx.TimedEntry: o valor devolvido serializa a entry com
+// o "ts" no formato tf (número nos layouts unix*), em JSON e em YAML.
+func (e *Entry) Timed(tf kbx.TimeFormat) any {
+	return timedEntry{e: e, tf: tf}
+}
+
+type timedEntry struct {
+	e  *Entry
+	tf kbx.TimeFormat
+}
+
+// plainEntry tem os campos (e as tags) de Entry, sem os métodos: serve pra
+// serializar a entry por dentro dos marshalers abaixo sem recursão.
+type plainEntry Entry
+
+func (t timedEntry) MarshalJSON() ([]byte, error) {
+	// o "ts" de fora, mais raso, esconde o da entry embutida.
+	return json.Marshal(struct {
+		Timestamp json.RawMessage `json:"ts"`
+		*plainEntry
+	}{t.tf.JSON(t.e.Timestamp), (*plainEntry)(t.e)})
+}
+
+func (t timedEntry) MarshalYAML() (any, error) {
+	var n yaml.Node
+	if err := n.Encode((*plainEntry)(t.e)); err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value != "ts" {
+			continue
+		}
+		v := n.Content[i+1]
+		v.Kind, v.Value, v.Style = yaml.ScalarNode, t.tf.Format(t.e.Timestamp), yaml.DoubleQuotedStyle
+		v.Tag = "!!str"
+		if t.tf.IsNumeric() {
+			v.Tag, v.Style = "!!int", 0
+		}
+		break
+	}
+	return &n, nil
+}
+
 //
 // ---------- Debug-friendly String() ----------
 //
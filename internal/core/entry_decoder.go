@@ -1,27 +1,203 @@
 package core
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/kubex-ecosystem/logz/internal/module/kbx"
 )
 
 // DefaultEntryDecoder cria uma função Decode pra IOBridge[*Entry],
-// que transforma uma linha de texto em uma Entry simples.
+// que transforma uma linha de texto em uma Entry.
 //
-// É uma estratégia padrão: level fixo + mensagem = linha inteira.
+// O nível sai da própria linha quando dá pra reconhecer (ver DetectLevel);
+// senão vale defaultLevel. Linhas JSON (ex: de outro logger estruturado)
+// viram mensagem + fields. Níveis terminais (fatal/critical/panic), lidos
+// da linha ou passados como defaultLevel, saem como error com o original
+// no field "source_level": nada que passe pela ponte encerra o processo.
 func DefaultEntryDecoder(defaultLevel kbx.Level) func([]byte) (kbx.LogzEntry, error) {
 	if defaultLevel == "" {
 		defaultLevel = kbx.LevelInfo
 	}
 
 	return func(p []byte) (kbx.LogzEntry, error) {
-		msg := strings.TrimSpace(string(p))
-		if msg == "" {
+		line := strings.TrimSpace(string(p))
+		if line == "" {
 			return nil, nil
 		}
+		if strings.HasPrefix(line, "{") {
+			if e, ok := decodeJSONLine(line, defaultLevel); ok {
+				return e, nil
+			}
+		}
+
+		msg := stripStdlibTimestamp(line)
+		lvl, rest, ok := detectLevel(msg)
+		if !ok {
+			lvl = defaultLevel
+		} else if rest != "" {
+			msg = rest
+		}
+		e := NewLogzEntry(lvl).(*Entry)
+		demote(e)
+		return e.WithMessage(msg), nil
+	}
+}
 
-		return NewLogzEntry(defaultLevel).
-			WithMessage(msg), nil
+// demote rebaixa pra error uma entry de nível terminal, guardando o nível
+// original em "source_level".
+func demote(e *Entry) {
+	if !e.Level.IsTerminal() {
+		return
+	}
+	e.WithField("source_level", strings.ToLower(string(e.Level)))
+	e.WithLevel(kbx.LevelError)
+}
+
+// levelAliases mapeia as grafias comuns de nível pros níveis do logz.
+var levelAliases = map[string]kbx.Level{
+	"trace":    kbx.LevelTrace,
+	"trc":      kbx.LevelTrace,
+	"debug":    kbx.LevelDebug,
+	"dbg":      kbx.LevelDebug,
+	"info":     kbx.LevelInfo,
+	"inf":      kbx.LevelInfo,
+	"notice":   kbx.LevelNotice,
+	"warn":     kbx.LevelWarn,
+	"warning":  kbx.LevelWarn,
+	"wrn":      kbx.LevelWarn,
+	"error":    kbx.LevelError,
+	"err":      kbx.LevelError,
+	"erro":     kbx.LevelError,
+	"alert":    kbx.LevelAlert,
+	"crit":     kbx.LevelCritical,
+	"critical": kbx.LevelCritical,
+	"fatal":    kbx.LevelFatal,
+	"panic":    kbx.LevelPanic,
+}
+
+// DetectLevel reconhece o nível de uma linha de texto solto:
+//
+//	ERROR: disco cheio        -> error, "disco cheio"
+//	[WARN] retry 2/3          -> warn,  "retry 2/3"
+//	level=debug msg="cache"   -> debug, a linha inteira (logfmt)
+//
+// Níveis terminais vindos de texto (fatal, crit/critical, panic; ver
+// Level.IsTerminal) são rebaixados pra error: a linha é de outro processo
+// (ou do log stdlib) e não pode derrubar este.
+func DetectLevel(line string) (kbx.Level, string, bool) {
+	lvl, rest, ok := detectLevel(line)
+	if ok && lvl.IsTerminal() {
+		lvl = kbx.LevelError
+	}
+	return lvl, rest, ok
+}
+
+func detectLevel(line string) (kbx.Level, string, bool) {
+	// logfmt: level=... / lvl=... em qualquer posição.
+	for _, f := range strings.Fields(line) {
+		k, v, found := strings.Cut(f, "=")
+		if !found || (k != "level" && k != "lvl") {
+			continue
+		}
+		if lvl, ok := levelAliases[strings.ToLower(strings.Trim(v, `"'`))]; ok {
+			return lvl, line, true
+		}
+	}
+
+	// prefixo: "ERROR:", "ERROR", "[WARN]", "<info>", "W:" não (ambíguo demais).
+	word, rest, _ := strings.Cut(line, " ")
+	token := strings.TrimRight(word, ":")
+	if len(token) > 2 && (token[0] == '[' && token[len(token)-1] == ']' ||
+		token[0] == '<' && token[len(token)-1] == '>') {
+		token = token[1 : len(token)-1]
+	}
+	lvl, ok := levelAliases[strings.ToLower(token)]
+	if !ok {
+		return "", line, false
+	}
+	// "Error" no meio de uma frase ("Error handling is...") não conta: só
+	// maiúsculas, ou com ":"/colchetes marcando que é um rótulo.
+	if token != strings.ToUpper(token) && token == word {
+		return "", line, false
+	}
+	return lvl, strings.TrimSpace(rest), true
+}
+
+// stripStdlibTimestamp tira o "2006/01/02 15:04:05[.000000] " que o pacote
+// log da stdlib põe por padrão: o logz já carimba o próprio timestamp.
+func stripStdlibTimestamp(line string) string {
+	rest := line
+	if len(rest) >= 11 && rest[4] == '/' && rest[7] == '/' && rest[10] == ' ' {
+		if _, err := time.Parse("2006/01/02", rest[:10]); err == nil {
+			rest = rest[11:]
+		}
+	}
+	if len(rest) >= 9 && rest[2] == ':' && rest[5] == ':' {
+		end := 8
+		if len(rest) > 8 && rest[8] == '.' {
+			end = 9
+			for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+				end++
+			}
+		}
+		if end < len(rest) && rest[end] == ' ' {
+			if _, err := time.Parse("15:04:05", rest[:8]); err == nil {
+				rest = rest[end+1:]
+			}
+		}
+	}
+	if rest == "" {
+		return line
+	}
+	return rest
+}
+
+// decodeJSONLine aproveita uma linha JSON: msg/message/text/log/event,
+// level/lvl/severity e time/ts/timestamp vão pros campos da Entry; o resto
+// vira fields. Sem chave de mensagem, a linha inteira é a mensagem.
+func decodeJSONLine(line string, defaultLevel kbx.Level) (kbx.LogzEntry, bool) {
+	var m map[string]any
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil || dec.More() {
+		return nil, false
+	}
+
+	lvl := defaultLevel
+	for _, k := range []string{"level", "lvl", "severity"} {
+		if v, ok := m[k].(string); ok {
+			if l, ok := levelAliases[strings.ToLower(v)]; ok {
+				lvl = l
+				delete(m, k)
+				break
+			}
+		}
+	}
+
+	e := NewLogzEntry(lvl).(*Entry)
+	e.Message = line
+	for _, k := range []string{"msg", "message", "text", "log", "event"} {
+		if v, ok := m[k]; ok && v != nil && fmt.Sprint(v) != "" {
+			e.Message = fmt.Sprint(v)
+			delete(m, k)
+			break
+		}
+	}
+	for _, k := range []string{"time", "ts", "timestamp"} {
+		if v, ok := m[k].(string); ok {
+			if ts, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				e.WithTimestamp(ts)
+				delete(m, k)
+				break
+			}
+		}
+	}
+	if len(m) > 0 {
+		e.WithFields(m)
 	}
+	demote(e)
+	return e, true
 }
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/kubex-ecosystem/logz/internal/binlog"
+)
+
+// EntryFromRecord reconstrói a Entry a partir de um registro binário
+// (ver formatter.BinaryFormatter). O erro volta só como texto, e o
+// timestamp do registro é preservado.
+func EntryFromRecord(r *binlog.Record) *Entry {
+	e := &Entry{
+		Timestamp:   r.Time,
+		Level:       r.Level,
+		Message:     r.Message,
+		Context:     r.Context,
+		Source:      r.Source,
+		TraceID:     r.TraceID,
+		SpanID:      r.SpanID,
+		TraceFlags:  r.TraceFlags,
+		Caller:      r.Caller,
+		Severity:    r.Severity,
+		Tags:        r.Tags,
+		Fields:      r.Fields,
+		Format:      r.Format,
+		ShowColor:   r.Flags&binlog.FlagShowColor != 0,
+		ShowIcon:    r.Flags&binlog.FlagShowIcon != 0,
+		ShowTraceID: r.Flags&binlog.FlagShowTraceID != 0,
+		ShowCaller:  r.Flags&binlog.FlagShowCaller != 0,
+		ShowStack:   r.Flags&binlog.FlagShowStack != 0,
+		ShowFields:  r.Flags&binlog.FlagShowFields != 0,
+		tsSet:       true,
+	}
+	if e.Severity == 0 {
+		e.Severity = e.Level.Severity()
+	}
+	if e.Tags == nil {
+		e.Tags = make(map[string]string)
+	}
+	if e.Fields == nil {
+		e.Fields = make(map[string]any)
+	}
+	if r.Error != "" {
+		e.Error = errors.New(r.Error)
+	}
+	return e
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"gopkg.in/yaml.v3"
+)
+
+// O trace_id sai como "trace" no JSON/YAML, como antes do suporte a W3C;
+// span_id e trace_flags são chaves novas.
+func TestEntryTraceWireKeys(t *testing.T) {
+	e, _ := NewEntry(kbx.LevelInfo)
+	e.WithTraceContext(kbx.TraceContext{
+		TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:     "00f067aa0ba902b7",
+		TraceFlags: 1,
+	})
+
+	js, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(js, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc["trace"] != e.TraceID || doc["span_id"] != e.SpanID || doc["trace_flags"] != "01" {
+		t.Errorf("json = %s", js)
+	}
+	if _, ok := doc["trace_id"]; ok {
+		t.Errorf("json has trace_id: %s", js)
+	}
+
+	var back Entry
+	if err := json.Unmarshal([]byte(`{"level":"info","msg":"m","trace":"abc"}`), &back); err != nil || back.TraceID != "abc" {
+		t.Errorf("decode trace = %q, %v", back.TraceID, err)
+	}
+
+	ys, err := yaml.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(ys), "trace: "+e.TraceID) {
+		t.Errorf("yaml = %s", ys)
+	}
+}
@@ -0,0 +1,63 @@
+package core_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubex-ecosystem/logz"
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// SetFormatter ajusta uma cópia: o formatter de quem chamou pode estar
+// em uso em outro logger.
+func TestSetFormatterKeepsCallerFormatter(t *testing.T) {
+	shared := &formatter.TextFormatter{}
+	want := *shared
+
+	a, b := logz.NewLogger("a"), logz.NewLogger("b")
+	a.SetOutput(&bytes.Buffer{})
+	b.SetOutput(&bytes.Buffer{})
+	a.SetFormatter(shared)
+	b.SetFormatter(shared)
+
+	if !reflect.DeepEqual(*shared, want) {
+		t.Errorf("caller formatter changed: %+v, want %+v", *shared, want)
+	}
+	fa, fb := a.GetConfig().Formatter, b.GetConfig().Formatter
+	if fa == formatter.Formatter(shared) || fb == formatter.Formatter(shared) || fa == fb {
+		t.Error("loggers share the caller's formatter instead of a copy")
+	}
+}
+
+// Sem formatter configurado, getFormatter monta um pelo nome uma vez só:
+// o aviso de tema desconhecido não se repete a cada entry.
+func TestFallbackFormatterBuiltOnce(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logz.NewLogger("fallback")
+	logger.SetOutput(&buf)
+	cfg := logger.GetConfig()
+	if cfg.LogzFormatOptions == nil {
+		t.Fatal("logger without format options")
+	}
+	cfg.Format, cfg.Theme = "json", "nope"
+
+	for i := 0; i < 3; i++ {
+		_ = logger.Log(kbx.LevelInfo, C.NewLogzEntry(kbx.LevelInfo).WithMessage("tick"))
+	}
+	first := logger.GetConfig().Formatter
+	_ = logger.Log(kbx.LevelInfo, C.NewLogzEntry(kbx.LevelInfo).WithMessage("tock"))
+
+	if n := strings.Count(buf.String(), "unknown theme"); n != 1 {
+		t.Errorf("unknown theme warned %d times, want 1:\n%s", n, buf.String())
+	}
+	if first == nil || first.Name() != "json" {
+		t.Fatalf("cached formatter = %v, want json", first)
+	}
+	if logger.GetConfig().Formatter != first {
+		t.Error("fallback formatter rebuilt on a later entry")
+	}
+}
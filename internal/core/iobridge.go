@@ -1,11 +1,17 @@
 package core
 
 import (
-	"strings"
+	"bytes"
+	"errors"
+	"sync"
 
 	"github.com/kubex-ecosystem/logz/internal/module/kbx"
 )
 
+// defaultMaxLineBytes limita o buffer de uma linha sem "\n": passou disso,
+// o pedaço acumulado é logado como está.
+const defaultMaxLineBytes = 64 << 10
+
 // IOBridge é o adaptador que IMPLEMENTA io.Writer e empurra tudo para um Logger[T].
 //
 // É aqui que o "modo B" (byte-first) entra no modo C híbrido:
@@ -19,9 +25,18 @@ import (
 //	log.SetOutput(bridge)      // log stdlib
 //	cmd.Stdout = bridge        // exec.Command
 //	json.NewEncoder(bridge)...
+//
+// A escrita é quebrada em linhas: cada "\n" fecha uma entry, e o que sobra
+// sem "\n" espera a próxima escrita (ou Flush/Close). Assim um processo que
+// escreve meia linha por vez ainda gera uma entry por linha.
 type IOBridge[T kbx.Entry] struct {
 	Logger *LoggerZ[T]
 	Decode func([]byte) (T, error)
+	// MaxLineBytes: padrão 64KiB.
+	MaxLineBytes int
+
+	mu  sync.Mutex
+	buf []byte
 }
 
 // NewIOBridge cria a ponte genérica entre io.Writer e Logger[T].
@@ -34,10 +49,10 @@ func NewIOBridge[T kbx.Entry](logger *LoggerZ[T], decode func([]byte) (T, error)
 
 // Write implementa io.Writer.
 //
-// Estratégia básica (simples e eficaz):
-// - recebe chunk de bytes
-// - chama Decode pra produzir um T
-// - passa pro Logger.Log
+// Estratégia:
+// - acumula o chunk no buffer
+// - para cada linha completa, chama Decode pra produzir um T
+// - passa pro Logger.Log no nível que o Decode escolheu
 //
 // Se Logger ou Decode forem nil, a escrita é "dropada" mas não quebra a app.
 func (b *IOBridge[T]) Write(p []byte) (int, error) {
@@ -46,19 +61,81 @@ func (b *IOBridge[T]) Write(p []byte) (int, error) {
 		return len(p), nil
 	}
 
-	trimmed := strings.TrimSpace(string(p))
-	if trimmed == "" {
-		return len(p), nil
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// uma linha com erro não derruba as seguintes: todas são emitidas e os
+	// erros voltam juntos.
+	var errs []error
+	b.buf = append(b.buf, p...)
+	for {
+		i := bytes.IndexByte(b.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := b.buf[:i]
+		b.buf = b.buf[i+1:]
+		if err := b.emit(line); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	rec, err := b.Decode([]byte(trimmed))
-	if err != nil {
-		return 0, err
+	limit := b.MaxLineBytes
+	if limit <= 0 {
+		limit = defaultMaxLineBytes
+	}
+	if len(b.buf) >= limit {
+		line := b.buf
+		b.buf = nil
+		if err := b.emit(line); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(b.buf) == 0 {
+		// solta o array antigo em vez de crescer pra sempre.
+		b.buf = nil
+	}
+	return len(p), errors.Join(errs...)
+}
+
+// Flush loga o que ficou no buffer sem "\n" final.
+func (b *IOBridge[T]) Flush() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	line := b.buf
+	b.buf = nil
+	if b.Logger == nil || b.Decode == nil {
+		return nil
+	}
+	return b.emit(line)
+}
+
+// Close é Flush: permite usar a ponte como io.WriteCloser (ex: fechar
+// depois de cmd.Wait()).
+func (b *IOBridge[T]) Close() error {
+	return b.Flush()
+}
+
+func (b *IOBridge[T]) emit(line []byte) error {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
 	}
 
-	if err := b.Logger.Log(kbx.LevelInfo, rec); err != nil {
-		return 0, err
+	rec, err := b.Decode(line)
+	if err != nil {
+		return err
+	}
+	if !kbx.IsObjSafe(rec, false) {
+		return nil
 	}
 
-	return len(p), nil
+	lvl := rec.GetLevel()
+	if lvl == "" {
+		lvl = kbx.LevelInfo
+	}
+	return b.Logger.Log(lvl, rec)
 }
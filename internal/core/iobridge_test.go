@@ -0,0 +1,105 @@
+package core_test
+
+import (
+	"fmt"
+	"testing"
+
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/logztest"
+)
+
+func TestDetectLevel(t *testing.T) {
+	for _, tt := range []struct {
+		line string
+		lvl  kbx.Level
+		rest string
+		ok   bool
+	}{
+		{"ERROR: disk full", kbx.LevelError, "disk full", true},
+		{"[WARN] retry 2/3", kbx.LevelWarn, "retry 2/3", true},
+		{`level=debug msg="cache"`, kbx.LevelDebug, `level=debug msg="cache"`, true},
+		{"Error handling is hard", "", "Error handling is hard", false},
+		{"FATAL: gone", kbx.LevelError, "gone", true},
+		{"CRITICAL: disk almost full", kbx.LevelError, "disk almost full", true},
+		{"<crit> overheating", kbx.LevelError, "overheating", true},
+		{"level=panic boom", kbx.LevelError, "level=panic boom", true},
+	} {
+		lvl, rest, ok := C.DetectLevel(tt.line)
+		if lvl != tt.lvl || rest != tt.rest || ok != tt.ok {
+			t.Errorf("DetectLevel(%q) = %q, %q, %v", tt.line, lvl, rest, ok)
+		}
+	}
+}
+
+func TestDecoderDemotesTerminalLevels(t *testing.T) {
+	decode := C.DefaultEntryDecoder(kbx.LevelFatal)
+	for line, orig := range map[string]string{
+		"CRITICAL: disk almost full":       "critical",
+		`{"level":"crit","msg":"too hot"}`: "critical",
+		`{"severity":"FATAL","msg":"bye"}`: "fatal",
+		"no level here":                    "fatal", // defaultLevel também
+	} {
+		e, err := decode([]byte(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.GetLevel() != kbx.LevelError {
+			t.Errorf("%q: level = %s", line, e.GetLevel())
+		}
+		if got := e.(*C.Entry).Fields["source_level"]; got != orig {
+			t.Errorf("%q: source_level = %v, want %s", line, got, orig)
+		}
+	}
+}
+
+func TestDecoderJSONMessage(t *testing.T) {
+	decode := C.DefaultEntryDecoder("")
+	for line, want := range map[string]string{
+		`{"msg":"a","n":1}`:          "a",
+		`{"text":"b"}`:               "b",
+		`{"event":"c","msg":""}`:     "c",
+		`{"status":200,"path":"/x"}`: `{"status":200,"path":"/x"}`,
+	} {
+		e, err := decode([]byte(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.GetMessage() != want {
+			t.Errorf("%q: message = %q, want %q", line, e.GetMessage(), want)
+		}
+		if err := e.Validate(); err != nil {
+			t.Errorf("%q: %v", line, err)
+		}
+	}
+}
+
+// Nada escrito na ponte encerra o processo; e uma linha JSON sem mensagem
+// não derruba as seguintes.
+func TestIOBridgeWrite(t *testing.T) {
+	logger, obs := logztest.New(nil)
+
+	decode := C.DefaultEntryDecoder(kbx.LevelInfo)
+	bridge := C.NewIOBridge(logger, func(p []byte) (kbx.Entry, error) {
+		e, err := decode(p)
+		if err != nil || e == nil {
+			return nil, err
+		}
+		return e, nil
+	})
+	in := "CRITICAL: disk almost full\n{\"status\":200}\nFATAL: should not exit\nlast"
+	if _, err := fmt.Fprint(bridge, in); err != nil {
+		t.Fatal(err)
+	}
+	if err := bridge.Close(); err != nil {
+		t.Fatal(err)
+	}
+	logs := obs.Logs()
+	logztest.AssertCount(t, logs, 4)
+	logztest.AssertField(t, logztest.AssertLogged(t, logs, "disk almost full"), "source_level", "critical")
+	logztest.AssertLogged(t, logs, `{"status":200}`)
+	logztest.AssertLogged(t, logs, "last")
+	if n := logs.ByLevel(kbx.LevelError).Len(); n != 2 {
+		t.Errorf("%d error entries, want 2:\n%s", n, logs)
+	}
+}
@@ -0,0 +1,59 @@
+package core_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/logztest"
+)
+
+// Logar o mesmo mapa de novo reavalia os Lazy e não altera o mapa de quem
+// logou, nem nos níveis aninhados que o WithFields não copia.
+func TestLazyFieldsKeepCallerMaps(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	var calls atomic.Int32
+	lazy := kbx.Lazy(func() any { return calls.Add(1) })
+	nested := map[string]any{"n": lazy}
+	args := map[string]any{"top": lazy, "nested": nested}
+
+	for range 2 {
+		logger.Debug(args)
+		_ = logger.Log(kbx.LevelDebug, C.NewLogzEntry(kbx.LevelDebug).WithMessage("entry").WithFields(args))
+	}
+	// 2 chamadas × 2 caminhos × 2 Lazy.
+	if got := calls.Load(); got != 8 {
+		t.Errorf("Lazy evaluated %d times, want 8", got)
+	}
+	if _, ok := args["top"].(kbx.Lazy); !ok {
+		t.Errorf("caller map changed: %#v", args["top"])
+	}
+	if _, ok := nested["n"].(kbx.Lazy); !ok {
+		t.Errorf("nested caller map changed: %#v", nested["n"])
+	}
+	entries := obs.Logs().ByMessage("entry")
+	logztest.AssertCount(t, entries, 2)
+	// o que foi logado tem o valor resolvido.
+	if n, ok := entries[1].Fields["nested"].(map[string]any)["n"].(int32); !ok || n < 5 {
+		t.Errorf("logged nested.n = %#v", entries[1].Fields["nested"])
+	}
+}
+
+// Goroutines logando o mesmo mapa não disputam a escrita nele (-race).
+func TestLazyFieldsSharedMap(t *testing.T) {
+	logger, _ := logztest.New(nil)
+	shared := map[string]any{"m": map[string]any{"v": kbx.Lazy(func() any { return 1 })}}
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				logger.Debug(shared)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -1,18 +1,26 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/kubex-ecosystem/logz/interfaces"
+	"github.com/kubex-ecosystem/logz/internal/dedup"
+	"github.com/kubex-ecosystem/logz/internal/exporter"
 	"github.com/kubex-ecosystem/logz/internal/formatter"
+	control "github.com/kubex-ecosystem/logz/internal/manager/control"
 	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/internal/redact"
+	"github.com/kubex-ecosystem/logz/internal/sampling"
 
 	"log"
 )
@@ -24,11 +32,14 @@ import (
 // Não sabe nada de linha, arquivo, CLI, JSON, etc.
 // Isso é responsabilidade do Formatter + destino (io.Writer).
 type Logger struct {
-	mu      sync.RWMutex
+	mu      *sync.RWMutex
 	flushMu sync.Mutex
 	hooksMu sync.Mutex
 	opts    *LoggerOptionsImpl
 	*log.Logger
+
+	// fields/trace fixos de um logger filho (ver With); nil no raiz.
+	bound *boundContext
 }
 
 // LoggerZ é o núcleo do pipeline:
@@ -75,27 +86,30 @@ func NewLogger(prefix string, opts *LoggerOptionsImpl, withDefaults bool) *Logge
 	lgr := &Logger{
 		flushMu: sync.Mutex{},
 		hooksMu: sync.Mutex{},
-		mu:      sync.RWMutex{},
+		mu:      &sync.RWMutex{},
 		opts:    opts,
 		Logger:  logr,
 	}
 	// Reafirma configurações do log padrão
 	lgr.SetFlags(0) // desativa flags automáticas do log padrão
-	if kbx.DefaultFalse(opts.OutputTTY) {
-		// se for TTY, desativa escrita direta no output padrão
-		lgr.SetOutput(io.Discard) // evita escrita direta no output padrão
-	} else {
-		// se não for TTY, mantém escrita direta no output padrão
-		lgr.SetOutput(out)
-	}
+	// output_tty não desvia mais a saída: só diz aos formatters se o
+	// destino é um terminal (ver term).
+	lgr.SetOutput(out)
 	lgr.SetPrefix(prefix)
-	lgr.SetFormatter(kbx.GetValueOrDefaultSimple(
-		formatter.ParseFormatter(opts.Format, true),
-		formatter.NewMinimalFormatter(true)),
-	)
+	if opts.LogzAdvancedOptions != nil && opts.Formatter != nil {
+		lgr.SetFormatter(opts.Formatter)
+	} else if f, err := formatter.New(lgr.formatName(), true); err == nil {
+		lgr.SetFormatter(f)
+	}
+	// nome inválido: cada Log devolve o erro (ver getFormatter) em vez de
+	// cair no text em silêncio; o SetConfig abaixo avisa.
 	lgr.SetPrefix(lgr.opts.Prefix)
 	lgr.SetMinLevel(lgr.opts.MinLevel)
 	lgr.SetConfig(lgr.opts.LoggerConfig)
+	lgr.initRedactor()
+	lgr.initSampler()
+	lgr.initDedup()
+	lgr.initExporters()
 	metaData := make(map[string]any)
 	for k, v := range lgr.opts.LoggerConfig.Metadata {
 		metaData[k] = v
@@ -161,24 +175,24 @@ func NewLoggerZI(prefix string, opts *LoggerOptionsImpl, withDefaults bool) *Log
 	lgr := &Logger{
 		flushMu: sync.Mutex{},
 		hooksMu: sync.Mutex{},
-		mu:      sync.RWMutex{},
+		mu:      &sync.RWMutex{},
 		opts:    opts,
 		Logger:  logr,
 	}
 	// Reafirma configurações do log padrão
 	lgr.SetFlags(0) // desativa flags automáticas do log padrão
-	if kbx.DefaultFalse(opts.OutputTTY) {
-		// se for TTY, desativa escrita direta no output padrão
-		lgr.SetOutput(io.Discard) // evita escrita direta no output padrão
-	} else {
-		// se não for TTY, mantém escrita direta no output padrão
-		lgr.SetOutput(out)
-	}
+	// output_tty não desvia mais a saída: só diz aos formatters se o
+	// destino é um terminal (ver term).
+	lgr.SetOutput(out)
 	lgr.SetPrefix(prefix)
 	lgr.SetFormatter(lgr.opts.Formatter)
 	lgr.SetPrefix(lgr.opts.Prefix)
 	lgr.SetMinLevel(lgr.opts.MinLevel)
 	lgr.SetConfig(opts.LoggerConfig)
+	lgr.initRedactor()
+	lgr.initSampler()
+	lgr.initDedup()
+	lgr.initExporters()
 	metaData := make(map[string]any)
 	for k, v := range lgr.opts.LoggerConfig.Metadata {
 		metaData[k] = v
@@ -188,7 +202,172 @@ func NewLoggerZI(prefix string, opts *LoggerOptionsImpl, withDefaults bool) *Log
 	return lgr
 }
 
+// initSampler monta o Sampler a partir da seção "sampling" da config,
+// a menos que um já tenha sido injetado nas opções avançadas.
+func (l *Logger) initSampler() {
+	if l.opts.LogzAdvancedOptions != nil && l.opts.Sampler != nil {
+		// injetado: só falta ligar os resumos neste logger.
+		l.SetSampler(l.opts.Sampler)
+		return
+	}
+	cfg := l.opts.LoggerConfig
+	if cfg == nil || cfg.Sampling == nil || !kbx.DefaultTrue(cfg.Sampling.Enabled) {
+		return
+	}
+	s, err := sampling.New(cfg.Sampling)
+	if err != nil {
+		l.Printf("logz: invalid sampling config: %v", err)
+		return
+	}
+	l.SetSampler(s)
+}
+
+// initDedup monta o Collapser a partir da seção "dedup" da config,
+// a menos que um já tenha sido injetado nas opções avançadas.
+func (l *Logger) initDedup() {
+	if l.opts.LogzAdvancedOptions != nil && l.opts.Collapser != nil {
+		l.SetDedup(l.opts.Collapser)
+		return
+	}
+	cfg := l.opts.LoggerConfig
+	if cfg == nil || cfg.Dedup == nil || !kbx.DefaultTrue(cfg.Dedup.Enabled) {
+		return
+	}
+	c, err := dedup.New(cfg.Dedup)
+	if err != nil {
+		l.Printf("logz: invalid dedup config: %v", err)
+		return
+	}
+	l.SetDedup(c)
+}
+
+// initExporters liga os exportadores declarados na config (hoje, o OTLP).
+// Os metadados do logger entram como atributos do Resource.
+func (l *Logger) initExporters() {
+	cfg := l.opts.LoggerConfig
+	if cfg == nil || cfg.OTLP == nil || !kbx.DefaultTrue(cfg.OTLP.Enabled) {
+		return
+	}
+	otlpOpts := *cfg.OTLP
+	res := make(map[string]string, len(cfg.Metadata)+len(otlpOpts.Resource))
+	for k, v := range cfg.Metadata {
+		res[k] = v
+	}
+	for k, v := range otlpOpts.Resource {
+		res[k] = v
+	}
+	otlpOpts.Resource = res
+	x, err := exporter.NewOTLP(&otlpOpts)
+	if err != nil {
+		l.Printf("logz: invalid otlp config: %v", err)
+		return
+	}
+	x.OnError(func(err error) { l.Printf("logz: otlp export: %v", err) })
+	l.AddExporter(x)
+}
+
+// AddExporter pluga um destino estruturado: ele recebe cada entry (já
+// redigida) além da saída formatada normal.
+func (l *Logger) AddExporter(ew interfaces.EntryWriter) {
+	if ew == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.opts.LogzAdvancedOptions == nil {
+		l.opts.LogzAdvancedOptions = &LogzAdvancedOptions{}
+	}
+	l.opts.Exporters = append(l.opts.Exporters, ew)
+}
+
+// FlushExporters força o envio do que os exportadores têm em buffer.
+func (l *Logger) FlushExporters() error {
+	l.mu.RLock()
+	var exps []interfaces.EntryWriter
+	if l.opts.LogzAdvancedOptions != nil {
+		exps = append(exps, l.opts.Exporters...)
+	}
+	l.mu.RUnlock()
+	if ew, ok := l.Writer().(interfaces.EntryWriter); ok {
+		exps = append(exps, ew)
+	}
+	var errs []error
+	for _, ew := range exps {
+		if f, ok := ew.(interface{ Flush() error }); ok {
+			errs = append(errs, f.Flush())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Flush escreve o que está pendente (resumo da amostragem, linha
+// "repeated N times") e força o envio dos exportadores.
+func (l *Logger) Flush() error {
+	return errors.Join(l.FlushSampling(), l.FlushDedup(), l.FlushExporters())
+}
+
+// Close faz o Flush e fecha os exportadores do logger (os da config e os
+// de AddExporter), que enviam o último lote antes de encerrar. Depois do
+// Close o logger segue escrevendo na saída, só não exporta mais. A saída
+// não é fechada: ela pertence a quem a passou.
+func (l *Logger) Close() error {
+	errs := []error{l.FlushSampling(), l.FlushDedup()}
+	l.mu.Lock()
+	var exps []interfaces.EntryWriter
+	if l.opts.LogzAdvancedOptions != nil {
+		exps, l.opts.Exporters = l.opts.Exporters, nil
+	}
+	l.mu.Unlock()
+	for _, ew := range exps {
+		switch c := ew.(type) {
+		case io.Closer:
+			errs = append(errs, c.Close())
+		case interface{ Flush() error }:
+			errs = append(errs, c.Flush())
+		}
+	}
+	// sobra a saída, se ela for um exportador.
+	errs = append(errs, l.FlushExporters())
+	return errors.Join(errs...)
+}
+
+// export entrega a entry aos exportadores configurados.
+func (l *Logger) export(entry *Entry) error {
+	l.mu.RLock()
+	var exps []interfaces.EntryWriter
+	if l.opts.LogzAdvancedOptions != nil {
+		exps = l.opts.Exporters
+	}
+	l.mu.RUnlock()
+	var errs []error
+	for _, ew := range exps {
+		errs = append(errs, ew.WriteEntry(entry))
+	}
+	return errors.Join(errs...)
+}
+
+// initRedactor monta o Redactor a partir da seção "redact" da config,
+// a menos que um já tenha sido injetado nas opções avançadas.
+func (l *Logger) initRedactor() {
+	cfg := l.opts.LoggerConfig
+	if cfg == nil || cfg.Redact == nil || !kbx.DefaultTrue(cfg.Redact.Enabled) {
+		return
+	}
+	if l.opts.LogzAdvancedOptions != nil && l.opts.Redactor != nil {
+		return
+	}
+	r, err := redact.New(cfg.Redact)
+	if err != nil {
+		l.Printf("logz: invalid redact config: %v", err)
+		return
+	}
+	l.SetRedactor(r)
+}
+
 func (l *Logger) SetFormatter(f formatter.Formatter) {
+	if f == nil {
+		return
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	if l.opts.LogzFormatOptions == nil {
@@ -197,13 +376,146 @@ func (l *Logger) SetFormatter(f formatter.Formatter) {
 		}
 		l.opts.LogzFormatOptions = kbx.LoggerArgs.LogzFormatOptions
 	}
+	if l.opts.LogzAdvancedOptions == nil {
+		l.opts.LogzAdvancedOptions = &LogzAdvancedOptions{}
+	}
+	// ajusta uma cópia: o formatter de quem chamou pode estar em uso em
+	// outro logger ou goroutine.
+	f = l.applyFormatterOptions(formatter.Clone(f))
+	l.opts.Formatter = f
 	l.opts.Format = f.Name()
 }
 
+// applyFormatterOptions aplica no formatter o que vem da config e do
+// destino: flags de segurança, formato/fuso do timestamp, tema e
+// cores/ícones. Deve ser chamado com l.mu já adquirido.
+func (l *Logger) applyFormatterOptions(f formatter.Formatter) formatter.Formatter {
+	f, err := formatter.ApplyDynamicRules(f, l.dynamicRules(), true)
+	if err != nil {
+		l.Printf("%v", err)
+	}
+	f = formatter.ApplySecurity(f, l.secFlags())
+	f = formatter.ApplyTerm(f, l.term())
+	f = formatter.ApplyTheme(f, l.theme())
+	return formatter.ApplyTimeFormat(f, l.timeFormat())
+}
+
+// dynamicRules lê as regras do formatter "dynamic" (nível -> formato) da
+// config, com LOGZ_DYNAMIC_FORMAT ("error=json,info=text,default=minimal")
+// como fallback. Deve ser chamado com l.mu já adquirido.
+func (l *Logger) dynamicRules() map[string]string {
+	if l.opts.LogzFormatOptions != nil && len(l.opts.Dynamic) > 0 {
+		return l.opts.Dynamic
+	}
+	return formatter.ParseDynamicRules(kbx.GetEnvOrDefault("LOGZ_DYNAMIC_FORMAT", ""))
+}
+
+// formatName devolve o nome do formato da config, com LOGZ_LOG_FORMAT
+// como fallback. Deve ser chamado com l.mu já adquirido (ou antes do
+// logger ser publicado).
+func (l *Logger) formatName() string {
+	var name string
+	if l.opts.LogzFormatOptions != nil {
+		name = l.opts.Format
+	}
+	return kbx.GetValueOrDefaultSimple(name, kbx.GetEnvOrDefault("LOGZ_LOG_FORMAT", ""))
+}
+
+// theme resolve o tema pelo nome da config, com LOGZ_THEME como fallback.
+// Nome vazio ou desconhecido = tema zero (o formatter fica como está).
+// Deve ser chamado com l.mu já adquirido.
+func (l *Logger) theme() formatter.Theme {
+	var name string
+	if l.opts.LogzFormatOptions != nil {
+		name = l.opts.Theme
+	}
+	name = kbx.GetValueOrDefaultSimple(name, kbx.GetEnvOrDefault("LOGZ_THEME", ""))
+	if name == "" {
+		return formatter.Theme{}
+	}
+	t, ok := formatter.LookupTheme(name)
+	if !ok {
+		l.Printf("logz: unknown theme %q (available: %s)", name, strings.Join(formatter.ThemeNames(), ", "))
+	}
+	return t
+}
+
+// term detecta se a saída é um terminal e quantas cores ela aceita;
+// output_tty, quando definido, força a resposta. Deve ser chamado com l.mu
+// já adquirido.
+func (l *Logger) term() kbx.TermInfo {
+	var tty *bool
+	if l.opts.LogzOutputOptions != nil {
+		tty = l.opts.OutputTTY
+	}
+	return kbx.DetectTerm(l.opts.Output, tty)
+}
+
+// timeFormat lê time_format/time_zone da config, com LOGZ_TIME_FORMAT e
+// LOGZ_TIME_ZONE como fallback. Deve ser chamado com l.mu já adquirido.
+func (l *Logger) timeFormat() kbx.TimeFormat {
+	var layout, zone string
+	if l.opts.LogzFormatOptions != nil {
+		layout, zone = l.opts.TimeFormat, l.opts.TimeZone
+	}
+	layout = kbx.GetValueOrDefaultSimple(layout, kbx.GetEnvOrDefault("LOGZ_TIME_FORMAT", ""))
+	zone = kbx.GetValueOrDefaultSimple(zone, kbx.GetEnvOrDefault("LOGZ_TIME_ZONE", ""))
+	tf, err := kbx.ParseTimeFormat(layout, zone)
+	if err != nil {
+		l.Printf("%v", err)
+		return kbx.TimeFormat{}
+	}
+	return tf
+}
+
+// SetClock troca o relógio usado pra carimbar as entries (nil volta ao
+// time.Now). Entries com timestamp fixado via WithTimestamp não mudam.
+// O relógio é compartilhado com os loggers filhos.
+func (l *Logger) SetClock(c kbx.Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.opts.LogzAdvancedOptions == nil {
+		l.opts.LogzAdvancedOptions = &LogzAdvancedOptions{}
+	}
+	l.opts.Clock = c
+}
+
+func (l *Logger) clock() kbx.Clock {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.opts.LogzAdvancedOptions == nil {
+		return nil
+	}
+	return l.opts.Clock
+}
+
+// secFlags junta as flags de segurança da config ("security") com a
+// variável LOGZ_SANITIZE. Deve ser chamado com l.mu já adquirido.
+func (l *Logger) secFlags() control.SecFlag {
+	var flags control.SecFlag
+	if l.opts.LogzFormatOptions != nil {
+		flags = control.FromLegacyMap(l.opts.Security)
+	}
+	if kbx.GetEnvOrDefaultWithType("LOGZ_SANITIZE", false) {
+		flags = flags.With(control.SecSanitize)
+	}
+	return flags
+}
+
 func (l *Logger) SetOutput(w io.Writer) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.opts.Output = w
+	if w != nil {
+		// o destino real das escritas é o log.Logger embutido.
+		l.Logger.SetOutput(w)
+	}
+	// terminal x arquivo/pipe muda cores e ícones. O formatter atual pode
+	// estar formatando em outra goroutine (getFormatter o devolve fora do
+	// lock): ajusta uma cópia e troca o ponteiro.
+	if l.opts.LogzAdvancedOptions != nil && l.opts.Formatter != nil {
+		l.opts.Formatter = formatter.ApplyTerm(formatter.Clone(l.opts.Formatter), l.term())
+	}
 }
 
 func (l *Logger) SetMinLevel(min kbx.Level) {
@@ -323,6 +635,20 @@ func (l *Logger) SetConfig(opts *kbx.LogzConfig) {
 	l.opts.Output = opts.Output
 	l.opts.LoggerConfig.Metadata = opts.Metadata
 	l.opts.StackTrace = opts.StackTrace
+
+	// time_format/time_zone/security podem ter mudado; como no SetOutput,
+	// ajusta uma cópia do formatter em uso.
+	if l.opts.LogzAdvancedOptions != nil && l.opts.Formatter != nil {
+		l.opts.Formatter = l.applyFormatterOptions(formatter.Clone(l.opts.Formatter))
+	}
+
+	// formato desconhecido: getFormatter devolve o erro em cada Log; avisa
+	// uma vez aqui.
+	hasFormatter := l.opts.LogzAdvancedOptions != nil && l.opts.Formatter != nil &&
+		(l.opts.Format == "" || l.opts.Formatter.Name() == l.opts.Format)
+	if name := l.formatName(); !hasFormatter && name != "" && !formatter.Known(name) {
+		l.Printf("logz: unknown format %q (available: %s)", name, strings.Join(formatter.Names(), ", "))
+	}
 }
 
 type logParts struct {
@@ -330,6 +656,10 @@ type logParts struct {
 	others    []any
 	jobLevel  kbx.Level
 	timestamp time.Time
+
+	// trace vindo de um context.Context passado nos argumentos
+	trace    kbx.TraceContext
+	hasTrace bool
 }
 
 func (l *Logger) logEntryError(entry kbx.LogzEntry) error {
@@ -362,11 +692,14 @@ func (l *Logger) logEntryError(entry kbx.LogzEntry) error {
 	}
 	// Formata a Entry para ser logada
 	b, err := l.opts.Formatter.Format(entryInstanceErrorLog)
+	if errors.Is(err, formatter.ErrDropped) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 	// Garante newline pra saída de console / arquivos de texto.
-	if len(b) == 0 || b[len(b)-1] != '\n' {
+	if !formatter.IsBinary(l.opts.Formatter) && (len(b) == 0 || b[len(b)-1] != '\n') {
 		b = append(b, '\n')
 	}
 
@@ -382,16 +715,30 @@ func (l *Logger) logEntryError(entry kbx.LogzEntry) error {
 
 func (l *Logger) getFormatter() (formatter.Formatter, error) {
 	l.mu.RLock()
-	f := kbx.GetValueOrDefaultSimple(
-		formatter.ParseFormatter(kbx.GetValueOrDefaultSimple(l.opts, &LoggerOptionsImpl{
-			LogzAdvancedOptions: &LogzAdvancedOptions{
-				Formatter: formatter.ParseFormatter("minimal", true),
-			},
-		}).Format, true),
-		formatter.ParseFormatter(l.opts.Format, true),
-	)
-	out := l.opts.Output
+	f, out := l.configuredFormatter(), l.opts.Output
 	l.mu.RUnlock()
+	if f == nil {
+		// monta o formatter pelo nome uma vez só e guarda: aplicar as
+		// opções a cada entry repetiria também os avisos (tema inválido...).
+		l.mu.Lock()
+		if f = l.configuredFormatter(); f == nil {
+			var err error
+			if f, err = formatter.New(l.formatName(), true); err != nil {
+				l.mu.Unlock()
+				return nil, err
+			}
+			f = l.applyFormatterOptions(f)
+			if l.opts.LogzAdvancedOptions == nil {
+				l.opts.LogzAdvancedOptions = &LogzAdvancedOptions{}
+			}
+			l.opts.Formatter = f
+			if l.opts.LogzFormatOptions != nil {
+				l.opts.Format = f.Name()
+			}
+		}
+		out = l.opts.Output
+		l.mu.Unlock()
+	}
 	if f == nil || out == nil {
 		// logger não inicializado corretamente; falha silenciosa
 		return nil, fmt.Errorf("logger not properly initialized: formatter or output is nil")
@@ -399,7 +746,188 @@ func (l *Logger) getFormatter() (formatter.Formatter, error) {
 	return f, nil
 }
 
+// configuredFormatter devolve o formatter configurado, desde que não
+// tenha sido trocado por nome depois (SetConfig com outro Format, por
+// exemplo); nil quando é preciso montar outro. Deve ser chamado com l.mu
+// já adquirido.
+func (l *Logger) configuredFormatter() formatter.Formatter {
+	adv := l.opts.LogzAdvancedOptions
+	if adv == nil || adv.Formatter == nil {
+		return nil
+	}
+	if l.opts.LogzFormatOptions != nil && l.opts.Format != "" && adv.Formatter.Name() != l.opts.Format {
+		return nil
+	}
+	return adv.Formatter
+}
+
+// preFormat roda tudo que precisa enxergar (e pode alterar) a entry antes
+// da formatação: redação de segredos primeiro, depois os hooks do usuário,
+// pra que nenhum hook receba dado sensível.
+func (l *Logger) preFormat(entry *Entry) error {
+	l.mu.RLock()
+	adv := l.opts.LogzAdvancedOptions
+	l.mu.RUnlock()
+	if adv == nil {
+		return nil
+	}
+
+	if adv.Redactor != nil {
+		adv.Redactor.Apply(entry)
+	}
+	for _, h := range adv.Hooks {
+		if h == nil {
+			continue
+		}
+		if err := h(entry); err != nil {
+			return err
+		}
+	}
+	if adv.LHooks != nil {
+		if err := adv.LHooks.Fire(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetRedactor troca (ou remove, com nil) o motor de redação do logger.
+func (l *Logger) SetRedactor(r *redact.Redactor) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.opts.LogzAdvancedOptions == nil {
+		l.opts.LogzAdvancedOptions = &LogzAdvancedOptions{}
+	}
+	l.opts.Redactor = r
+}
+
+// SetSampler troca (ou remove, com nil) a política de amostragem do logger.
+// Os resumos do que foi suprimido são escritos pelo próprio logger.
+func (l *Logger) SetSampler(s *sampling.Sampler) {
+	l.mu.Lock()
+	if l.opts.LogzAdvancedOptions == nil {
+		l.opts.LogzAdvancedOptions = &LogzAdvancedOptions{}
+	}
+	l.opts.Sampler = s
+	l.mu.Unlock()
+	if s != nil {
+		s.OnSummary(l.logSamplingSummary)
+	}
+}
+
+// FlushSampling escreve imediatamente o resumo pendente da amostragem,
+// se houver. Chame no shutdown pra não perder a última janela.
+func (l *Logger) FlushSampling() error {
+	l.mu.RLock()
+	var s *sampling.Sampler
+	if l.opts.LogzAdvancedOptions != nil {
+		s = l.opts.Sampler
+	}
+	l.mu.RUnlock()
+	if sum, ok := s.Flush(); ok {
+		return l.writeSamplingSummary(sum)
+	}
+	return nil
+}
+
+func (l *Logger) logSamplingSummary(sum sampling.Summary) {
+	if err := l.writeSamplingSummary(sum); err != nil {
+		l.Printf("logz: writing sampling summary: %v", err)
+	}
+}
+
+func (l *Logger) writeSamplingSummary(sum sampling.Summary) error {
+	entry, err := NewEntry(kbx.LevelWarn)
+	if err != nil {
+		return err
+	}
+	entry.WithMessage(sum.Message()).
+		WithContext("logz.sampling").
+		WithFields(sum.Fields())
+	// o resumo não passa pelo sampler, senão poderia ser suprimido também.
+	return l.dispatch(entry, false)
+}
+
+// SetDedup troca (ou remove, com nil) o estágio de colapso de duplicadas.
+func (l *Logger) SetDedup(c *dedup.Collapser) {
+	l.mu.Lock()
+	if l.opts.LogzAdvancedOptions == nil {
+		l.opts.LogzAdvancedOptions = &LogzAdvancedOptions{}
+	}
+	l.opts.Collapser = c
+	l.mu.Unlock()
+	if c != nil {
+		c.OnFlush(l.logRepeat)
+	}
+}
+
+// FlushDedup escreve imediatamente a linha "repeated N times" pendente,
+// se houver.
+func (l *Logger) FlushDedup() error {
+	l.mu.RLock()
+	var c *dedup.Collapser
+	if l.opts.LogzAdvancedOptions != nil {
+		c = l.opts.Collapser
+	}
+	l.mu.RUnlock()
+	if rep, ok := c.Flush(); ok {
+		return l.writeRepeat(rep)
+	}
+	return nil
+}
+
+func (l *Logger) logRepeat(rep dedup.Repeat) {
+	if err := l.writeRepeat(rep); err != nil {
+		l.Printf("logz: writing repeated entry: %v", err)
+	}
+}
+
+func (l *Logger) writeRepeat(rep dedup.Repeat) error {
+	entry, ok := rep.Entry.(*Entry)
+	if !ok || entry == nil {
+		return nil
+	}
+	entry.WithTimestamp(rep.Last)
+	entry.WithMessage(rep.Message()).WithFields(rep.Fields())
+	return l.dispatch(entry, false)
+}
+
+// collapsed passa a entry pelo Collapser configurado (se houver),
+// escrevendo antes a linha de repetições que ela encerra.
+func (l *Logger) collapsed(entry *Entry) (bool, error) {
+	l.mu.RLock()
+	var c *dedup.Collapser
+	if l.opts.LogzAdvancedOptions != nil {
+		c = l.opts.Collapser
+	}
+	l.mu.RUnlock()
+	rep, flushed, allow := c.Observe(entry)
+	if flushed {
+		if err := l.writeRepeat(rep); err != nil {
+			return allow, err
+		}
+	}
+	return allow, nil
+}
+
+// sampled consulta o Sampler configurado (se houver).
+func (l *Logger) sampled(entry *Entry) bool {
+	l.mu.RLock()
+	var s *sampling.Sampler
+	if l.opts.LogzAdvancedOptions != nil {
+		s = l.opts.Sampler
+	}
+	l.mu.RUnlock()
+	return s.Allow(entry)
+}
+
 func (l *Logger) dispatchLogEntry(entry *Entry) error {
+	return l.dispatch(entry, true)
+}
+
+// dispatch escreve a entry. Com filter=false ela pula amostragem e dedup
+// (usado pelas linhas de resumo geradas pelo próprio logger).
+func (l *Logger) dispatch(entry *Entry, filter bool) error {
 	if l == nil || entry == nil {
 		return nil
 	}
@@ -422,6 +950,53 @@ func (l *Logger) dispatchLogEntry(entry *Entry) error {
 		return nil
 	}
 
+	if c := l.clock(); c != nil && !entry.tsSet {
+		entry.Timestamp = c.Now().UTC()
+	}
+
+	// fields e trace herdados de With/WithTrace
+	l.bound.apply(entry)
+
+	// amostragem / rate limit: descartar aqui evita até avaliar os Lazy.
+	if filter && !l.sampled(entry) {
+		return nil
+	}
+
+	// só agora, com a entry aprovada pelo nível, avaliamos os valores
+	// preguiçosos (kbx.Lazy / LogValuer) dos fields. O resultado é um mapa
+	// novo: os mapas de quem logou (aninhados nos Fields) ficam intactos.
+	entry.Fields = kbx.ResolveFields(entry.Fields)
+
+	// dedup depois dos Lazy: o hash precisa dos valores reais.
+	if filter {
+		allow, err := l.collapsed(entry)
+		if err != nil {
+			return err
+		}
+		if !allow {
+			return nil
+		}
+	}
+
+	// redação + hooks pré-formatação
+	if err := l.preFormat(entry); err != nil {
+		return err
+	}
+
+	if err := l.export(entry); err != nil {
+		l.Printf("logz: exporter: %v", err)
+	}
+
+	// destino estruturado como saída principal: dispensa o formatter.
+	if ew, ok := l.Writer().(interfaces.EntryWriter); ok {
+		if l.Enabled(entry.GetLevel()) {
+			if err := ew.WriteEntry(entry); err != nil {
+				return err
+			}
+		}
+		return l.exitOnFatal(entry)
+	}
+
 	// obtém o formatter
 
 	f, err := l.getFormatter()
@@ -432,27 +1007,20 @@ func (l *Logger) dispatchLogEntry(entry *Entry) error {
 	// formata a Entry
 
 	b, err := f.Format(entry)
+	if errors.Is(err, formatter.ErrDropped) {
+		// descartada por um filtro do formatter: nada a escrever.
+		return l.exitOnFatal(entry)
+	}
 	if err != nil {
 		return err
 	}
 
-	// garante newline pra saída de console / arquivos de texto.
-	if len(b) == 0 || b[len(b)-1] != '\n' {
+	// garante newline pra saída de console / arquivos de texto; registros
+	// binários já vêm enquadrados.
+	if !formatter.IsBinary(f) && (len(b) == 0 || b[len(b)-1] != '\n') {
 		b = append(b, '\n')
 	}
 
-	// dispara hooks pré-formatação
-	if l.GetConfig() != nil {
-		if l.GetConfig().LogzAdvancedOptions != nil {
-			if l.GetConfig().LogzAdvancedOptions.Hooks != nil {
-				err := l.GetConfig().LHooks.Fire(entry)
-				if err != nil {
-					return err
-				}
-			}
-		}
-	}
-
 	if l.Enabled(entry.GetLevel()) {
 		// escreve no destino final
 		_, err = l.Writer().Write(b)
@@ -461,18 +1029,26 @@ func (l *Logger) dispatchLogEntry(entry *Entry) error {
 		}
 	}
 
-	if entry.GetLevel() == kbx.LevelFatal ||
-		entry.GetLevel() == kbx.LevelPanic ||
-		entry.GetLevel() == kbx.LevelCritical {
+	// tudo ok
+	return l.exitOnFatal(entry)
+}
+
+// exitOnFatal encerra o processo nos níveis fatal/panic/critical, depois
+// de dar aos exportadores a chance de enviar o que têm em buffer.
+func (l *Logger) exitOnFatal(entry *Entry) error {
+	if entry.GetLevel().IsTerminal() {
+		_ = l.FlushExporters()
 		os.Exit(1)
 	}
-
-	// tudo ok
 	return nil
 }
 
 // Log é o caminho principal: recebe um Record pronto (T),
 // dispara hooks, formata e escreve em out.
+//
+// Um context.Context nos argumentos não vira mensagem: dele saem
+// trace_id/span_id/trace_flags (span do OpenTelemetry ou kbx.ContextWithTrace)
+// para as entries que ainda não têm trace.
 func (l *Logger) Log(lvl kbx.Level, rec ...any) error {
 	if !kbx.IsObjSafe(rec, false) {
 		// nada a fazer, mas não vamos quebrar ninguém
@@ -490,6 +1066,10 @@ func (l *Logger) Log(lvl kbx.Level, rec ...any) error {
 			if !kbx.IsObjSafe(r, false) {
 				continue
 			}
+			if ctx, ok := r.(context.Context); ok {
+				logParts.trace, logParts.hasTrace = kbx.TraceFromContext(ctx)
+				continue
+			}
 			if e, ok := r.(kbx.Entry); ok {
 				logParts.entries = append(logParts.entries, e)
 			} else {
@@ -507,6 +1087,9 @@ func (l *Logger) Log(lvl kbx.Level, rec ...any) error {
 		// garante que o nível do job seja respeitado
 		if l.Enabled(entry.GetLevel()) {
 			entry = entry.(*Entry).WithLevel(logParts.jobLevel)
+			if logParts.hasTrace && entry.GetTraceID() == "" {
+				entry = entry.(*Entry).WithTraceContext(logParts.trace)
+			}
 		} else {
 			continue
 		}
@@ -528,10 +1111,19 @@ func (l *Logger) Log(lvl kbx.Level, rec ...any) error {
 	/// Agora, TODOS OS OUTROS objetos que estavam na lista de argumentos
 	/////////////////////////////////////////////////////////////////////
 	if len(logParts.others) > 0 {
+		// nada de montar mensagem (nem avaliar Lazy) pra nível desabilitado.
+		if !l.Enabled(lvl) {
+			return nil
+		}
 		entry := NewLogzEntry(lvl)
+		if logParts.hasTrace {
+			entry = entry.(*Entry).WithTraceContext(logParts.trace)
+		}
 
 		var msgParts = make([]string, 0)
 		for _, other := range logParts.others {
+			// Lazy / LogValuer soltos nos args viram o valor concreto aqui.
+			other = kbx.ResolveValue(other)
 			if str, ok := other.(string); ok {
 				if str != "" {
 					msgParts = append(msgParts, str)
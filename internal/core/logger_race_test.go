@@ -0,0 +1,68 @@
+package core_test
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/kubex-ecosystem/logz"
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+type lockedBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (w *lockedBuffer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.Write(p)
+}
+
+// SetOutput não pode mexer no formatter que outra goroutine está usando:
+// ajusta uma cópia e troca o ponteiro (rode com -race).
+func TestSetOutputWhileLogging(t *testing.T) {
+	for _, name := range []string{"text", "pretty", "console", "dynamic"} {
+		t.Run(name, func(t *testing.T) {
+			f, err := formatter.New(name, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			logger := logz.NewLogger("race")
+			logger.SetFormatter(f)
+			a, b := &lockedBuffer{}, &lockedBuffer{}
+			logger.SetOutput(a)
+			before := logger.GetConfig().Formatter
+
+			var wg sync.WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 50; j++ {
+						_ = logger.Log(kbx.LevelWarn, C.NewLogzEntry(kbx.LevelWarn).WithMessage("tick"))
+					}
+				}()
+			}
+			for j := 0; j < 50; j++ {
+				if j%2 == 0 {
+					logger.SetOutput(b)
+				} else {
+					logger.SetOutput(io.Writer(a))
+				}
+			}
+			wg.Wait()
+
+			if logger.GetConfig().Formatter == before {
+				t.Error("formatter was changed in place instead of swapped")
+			}
+			if a.b.Len()+b.b.Len() == 0 {
+				t.Error("nothing was written")
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package core_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kubex-ecosystem/logz"
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// Um formatter com Sanitize ligado continua sanitizando num logger sem a
+// flag SecSanitize: a config só liga, nunca desliga.
+func TestSetFormatterKeepsSanitize(t *testing.T) {
+	t.Setenv("LOGZ_SANITIZE", "false")
+	var buf bytes.Buffer
+	logger := logz.NewLogger("sec")
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&formatter.TextFormatter{Sanitize: true})
+
+	msg := "user input\n[2026-01-01] [error] forged line"
+	if err := logger.Log(kbx.LevelInfo, C.NewLogzEntry(kbx.LevelInfo).WithMessage(msg)); err != nil {
+		t.Fatal(err)
+	}
+	out := strings.TrimRight(buf.String(), "\n")
+	if out == "" || strings.Contains(out, "\n") {
+		t.Errorf("forged line written raw: %q", out)
+	}
+	if !strings.Contains(out, `user input\n[2026-01-01]`) {
+		t.Errorf("message not escaped: %q", out)
+	}
+}
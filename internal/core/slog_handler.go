@@ -0,0 +1,144 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// SlogHandler é a ponte slog.Handler -> LoggerZ.
+//
+// Os valores dos atributos são guardados como slog.Value e só são
+// resolvidos (LogValuer, kbx.Lazy, grupos) quando a entry chega no
+// pipeline do logger, depois do filtro de nível.
+type SlogHandler[T kbx.Entry] struct {
+	logger *LoggerZ[T]
+	attrs  []slog.Attr
+	group  string
+}
+
+// NewSlogHandler cria um slog.Handler que escreve no logger informado.
+func NewSlogHandler[T kbx.Entry](logger *LoggerZ[T]) *SlogHandler[T] {
+	return &SlogHandler[T]{logger: logger}
+}
+
+// SlogLevel converte um slog.Level para o Level do logz.
+func SlogLevel(l slog.Level) kbx.Level {
+	switch {
+	case l >= slog.LevelError:
+		return kbx.LevelError
+	case l >= slog.LevelWarn:
+		return kbx.LevelWarn
+	case l >= slog.LevelInfo:
+		return kbx.LevelInfo
+	case l >= slog.LevelDebug:
+		return kbx.LevelDebug
+	default:
+		return kbx.LevelTrace
+	}
+}
+
+// Enabled implementa slog.Handler.
+func (h *SlogHandler[T]) Enabled(_ context.Context, l slog.Level) bool {
+	if h == nil || h.logger == nil {
+		return false
+	}
+	return h.logger.Enabled(SlogLevel(l))
+}
+
+// Handle implementa slog.Handler.
+func (h *SlogHandler[T]) Handle(ctx context.Context, r slog.Record) error {
+	if h == nil || h.logger == nil {
+		return nil
+	}
+	lvl := SlogLevel(r.Level)
+
+	entry, err := NewEntry(lvl)
+	if err != nil {
+		return err
+	}
+	if !r.Time.IsZero() {
+		entry.WithTimestamp(r.Time)
+	}
+	msg := r.Message
+	if strings.TrimSpace(msg) == "" {
+		msg = "<empty>"
+	}
+	entry.WithMessage(msg)
+	if tc, ok := kbx.TraceFromContext(ctx); ok {
+		entry.WithTraceContext(tc)
+	}
+
+	for _, a := range h.attrs {
+		h.addAttr(entry, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		h.addAttr(entry, h.group, a)
+		return true
+	})
+
+	return h.logger.Log(lvl, entry)
+}
+
+// WithAttrs implementa slog.Handler.
+func (h *SlogHandler[T]) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := h.clone()
+	for _, a := range attrs {
+		if h.group != "" {
+			a.Key = h.group + "." + a.Key
+		}
+		clone.attrs = append(clone.attrs, a)
+	}
+	return clone
+}
+
+// WithGroup implementa slog.Handler.
+func (h *SlogHandler[T]) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := h.clone()
+	if clone.group != "" {
+		clone.group += "." + name
+	} else {
+		clone.group = name
+	}
+	return clone
+}
+
+func (h *SlogHandler[T]) clone() *SlogHandler[T] {
+	return &SlogHandler[T]{
+		logger: h.logger,
+		attrs:  append([]slog.Attr(nil), h.attrs...),
+		group:  h.group,
+	}
+}
+
+func (h *SlogHandler[T]) addAttr(entry *Entry, prefix string, a slog.Attr) {
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	key := a.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		// grupo sem chave é "inline", igual aos handlers do stdlib
+		if a.Key == "" {
+			key = prefix
+		}
+		for _, ga := range a.Value.Group() {
+			h.addAttr(entry, key, ga)
+		}
+		return
+	}
+	if a.Value.Kind() == slog.KindAny {
+		if err, ok := a.Value.Any().(error); ok && entry.Error == nil {
+			entry.WithError(err)
+		}
+	}
+	// mantém o slog.Value cru: a resolução acontece no dispatch.
+	entry.WithField(key, a.Value)
+}
@@ -0,0 +1,46 @@
+package core_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/logztest"
+)
+
+func TestSlogHandler(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	sl := slog.New(C.NewSlogHandler(logger)).With("svc", "api").WithGroup("req")
+
+	sl.Warn("slow", "ms", 1200, slog.Group("user", "id", 7), "err", errors.New("timeout"))
+
+	e := logztest.AssertLogged(t, obs.Logs(), "slow")
+	if e.Level != kbx.LevelWarn || e.Error == nil {
+		t.Errorf("level=%s error=%v", e.Level, e.Error)
+	}
+	logztest.AssertField(t, e, "svc", "api")
+	logztest.AssertField(t, e, "req.ms", 1200)
+	logztest.AssertField(t, e, "req.user.id", 7)
+}
+
+// Valores preguiçosos só são avaliados para entries que passam do nível.
+func TestLazyFieldsOnlyWhenEnabled(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	logger.SetMinLevel(kbx.LevelInfo)
+	calls := 0
+	dump := kbx.Lazy(func() any { calls++; return "state" })
+
+	_ = logger.Log(kbx.LevelDebug, C.NewLogzEntry(kbx.LevelDebug).WithMessage("hidden").WithField("dump", dump))
+	slog.New(C.NewSlogHandler(logger)).Debug("hidden too", "dump", slog.AnyValue(dump))
+	if calls != 0 {
+		t.Fatalf("Lazy evaluated %d times for disabled entries", calls)
+	}
+
+	_ = logger.Log(kbx.LevelInfo, C.NewLogzEntry(kbx.LevelInfo).WithMessage("shown").WithField("dump", dump))
+	logztest.AssertField(t, logztest.AssertLogged(t, obs.Logs(), "shown"), "dump", "state")
+	if calls != 1 {
+		t.Errorf("Lazy evaluated %d times, want 1", calls)
+	}
+}
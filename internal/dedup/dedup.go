@@ -0,0 +1,184 @@
+// Package dedup colapsa entries consecutivas idênticas numa linha só,
+// no estilo do "last message repeated N times" do syslog.
+package dedup
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+const defaultWindow = 10 * time.Second
+
+// Repeat descreve uma sequência de repetições colapsadas.
+type Repeat struct {
+	// Entry é uma cópia da primeira ocorrência (a que foi escrita).
+	Entry kbx.Entry
+	// Count é quantas repetições foram suprimidas depois dela.
+	Count int
+	First time.Time
+	Last  time.Time
+}
+
+// Message devolve a mensagem original anotada com a contagem.
+func (r Repeat) Message() string {
+	msg := ""
+	if r.Entry != nil {
+		msg = r.Entry.GetMessage()
+	}
+	return fmt.Sprintf("%s (repeated %d times)", msg, r.Count)
+}
+
+// Fields devolve os campos que anotam a linha colapsada.
+func (r Repeat) Fields() map[string]any {
+	return map[string]any{
+		"repeated":   r.Count,
+		"first_seen": r.First,
+		"last_seen":  r.Last,
+	}
+}
+
+// Collapser acompanha a última entry escrita. Enquanto chegarem entries
+// idênticas (mesmo nível, contexto, mensagem e hash dos fields) dentro de
+// Window desde a primeira, elas são suprimidas; quando chega uma diferente
+// ou a janela expira, sai uma linha "repeated N times".
+type Collapser struct {
+	mu sync.Mutex
+
+	Window time.Duration
+
+	onFlush func(Repeat)
+
+	has   bool
+	key   uint64
+	entry kbx.Entry
+	first time.Time
+	last  time.Time
+	count int
+	timer *time.Timer
+}
+
+// New cria um Collapser a partir das opções da config.
+func New(opts *kbx.LogzDedupOptions) (*Collapser, error) {
+	c := &Collapser{Window: defaultWindow}
+	if opts == nil || strings.TrimSpace(opts.Window) == "" {
+		return c, nil
+	}
+	w, err := time.ParseDuration(opts.Window)
+	if err != nil {
+		return nil, fmt.Errorf("logz: dedup window: %w", err)
+	}
+	if w <= 0 {
+		return nil, fmt.Errorf("logz: dedup window must be positive, got %s", w)
+	}
+	c.Window = w
+	return c, nil
+}
+
+// OnFlush registra quem escreve as linhas colapsadas quando a janela
+// expira sem que outra entry tenha chegado.
+func (c *Collapser) OnFlush(fn func(Repeat)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onFlush = fn
+}
+
+// Observe registra a entry. allow indica se ela deve ser escrita; quando
+// flushed é true, rep precisa ser escrito ANTES dela.
+func (c *Collapser) Observe(e kbx.Entry) (rep Repeat, flushed, allow bool) {
+	if c == nil || e == nil {
+		return Repeat{}, false, true
+	}
+	if e.GetLevel().IsTerminal() {
+		// o processo vai encerrar: escreve a entry e o que estiver pendente.
+		rep, flushed = c.Flush()
+		return rep, flushed, true
+	}
+
+	ts := e.GetTimestamp()
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	key := Key(e)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.has && key == c.key && ts.Sub(c.first) < c.Window {
+		c.count++
+		c.last = ts
+		if c.timer == nil && c.onFlush != nil {
+			c.timer = time.AfterFunc(c.first.Add(c.Window).Sub(ts), c.expire)
+		}
+		return Repeat{}, false, false
+	}
+
+	rep, flushed = c.take()
+	c.has = true
+	c.key = key
+	c.entry = e.Clone()
+	c.first, c.last = ts, ts
+	return rep, flushed, true
+}
+
+// Flush devolve (e zera) a sequência pendente, se houver repetições.
+func (c *Collapser) Flush() (Repeat, bool) {
+	if c == nil {
+		return Repeat{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rep, ok := c.take()
+	c.has = false
+	c.entry = nil
+	return rep, ok
+}
+
+func (c *Collapser) expire() {
+	rep, ok := c.Flush()
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	fn := c.onFlush
+	c.mu.Unlock()
+	if fn != nil {
+		fn(rep)
+	}
+}
+
+// take deve ser chamado com mu travado.
+func (c *Collapser) take() (Repeat, bool) {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if !c.has || c.count == 0 {
+		return Repeat{}, false
+	}
+	rep := Repeat{Entry: c.entry, Count: c.count, First: c.first, Last: c.last}
+	c.count = 0
+	return rep, true
+}
+
+// Key é o hash que identifica entries "iguais": nível, contexto, mensagem
+// e fields (ordenados por chave).
+func Key(e kbx.Entry) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\x00%s\x00%s", e.GetLevel(), e.GetContext(), e.GetMessage())
+	fields := e.GetFields()
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "\x00%s=%v", k, fields[k])
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,87 @@
+package dedup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// fakeEntry só responde o que o Collapser lê.
+type fakeEntry struct {
+	kbx.Entry
+	lvl    kbx.Level
+	msg    string
+	ts     time.Time
+	fields map[string]any
+}
+
+func (e fakeEntry) GetLevel() kbx.Level       { return e.lvl }
+func (e fakeEntry) GetMessage() string        { return e.msg }
+func (e fakeEntry) GetContext() string        { return "" }
+func (e fakeEntry) GetTimestamp() time.Time   { return e.ts }
+func (e fakeEntry) GetFields() map[string]any { return e.fields }
+func (e fakeEntry) Clone() kbx.Entry          { return e }
+
+func TestCollapse(t *testing.T) {
+	c, err := New(&kbx.LogzDedupOptions{Window: "1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Unix(100, 0)
+	e := func(msg string, sec int) kbx.Entry {
+		return fakeEntry{lvl: kbx.LevelWarn, msg: msg, ts: t0.Add(time.Duration(sec) * time.Second)}
+	}
+
+	if _, _, allow := c.Observe(e("disk", 0)); !allow {
+		t.Fatal("first entry must be written")
+	}
+	for i := 1; i <= 3; i++ {
+		if _, _, allow := c.Observe(e("disk", i)); allow {
+			t.Fatalf("repeat #%d was written", i)
+		}
+	}
+	rep, flushed, allow := c.Observe(e("net", 5))
+	if !allow || !flushed || rep.Count != 3 || rep.Message() != "disk (repeated 3 times)" {
+		t.Fatalf("rep=%+v flushed=%v allow=%v", rep, flushed, allow)
+	}
+	if !rep.First.Equal(t0) || !rep.Last.Equal(t0.Add(3*time.Second)) {
+		t.Errorf("first/last = %v/%v", rep.First, rep.Last)
+	}
+
+	// fora da janela não colapsa.
+	if _, _, allow := c.Observe(e("net", 70)); !allow {
+		t.Error("entry after the window was suppressed")
+	}
+}
+
+func TestFieldsChangeKey(t *testing.T) {
+	a := fakeEntry{lvl: kbx.LevelInfo, msg: "m", fields: map[string]any{"a": 1, "b": 2}}
+	b := fakeEntry{lvl: kbx.LevelInfo, msg: "m", fields: map[string]any{"b": 2, "a": 1}}
+	c := fakeEntry{lvl: kbx.LevelInfo, msg: "m", fields: map[string]any{"a": 2, "b": 2}}
+	if Key(a) != Key(b) {
+		t.Error("key depends on map order")
+	}
+	if Key(a) == Key(c) {
+		t.Error("different fields share a key")
+	}
+}
+
+func TestTerminalLevelsNotCollapsed(t *testing.T) {
+	c, _ := New(nil)
+	for _, l := range []kbx.Level{kbx.LevelFatal, kbx.LevelCritical, kbx.LevelPanic} {
+		for i := 0; i < 2; i++ {
+			if _, _, allow := c.Observe(fakeEntry{lvl: l, msg: "down"}); !allow {
+				t.Errorf("%s entry #%d was collapsed", l, i)
+			}
+		}
+	}
+}
+
+func TestInvalidWindow(t *testing.T) {
+	for _, w := range []string{"soon", "-1s", "0s"} {
+		if _, err := New(&kbx.LogzDedupOptions{Window: w}); err == nil {
+			t.Errorf("window %q: expected error", w)
+		}
+	}
+}
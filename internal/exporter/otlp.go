@@ -0,0 +1,411 @@
+// Package exporter implementa destinos estruturados do logz, que recebem
+// a Entry em vez dos bytes formatados.
+package exporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+const (
+	DefaultOTLPEndpoint = "http://localhost:4318/v1/logs"
+
+	EncodingProtobuf = "protobuf"
+	EncodingJSON     = "json"
+
+	defaultBatchSize     = 512
+	defaultQueueSize     = 2048
+	defaultFlushInterval = time.Second
+	defaultTimeout       = 10 * time.Second
+	defaultMaxRetries    = 3
+	maxBackoff           = 5 * time.Second
+
+	scopeName = "github.com/kubex-ecosystem/logz"
+)
+
+// ErrClosed é devolvido por escritas depois do Close.
+var ErrClosed = errors.New("logz: otlp exporter closed")
+
+// OTLPExporter envia entries para um OpenTelemetry Collector via OTLP/HTTP
+// (protobuf ou JSON), em lotes, com retry e gzip opcional.
+//
+// As entries entram numa fila limitada; com a fila cheia elas são descartadas
+// (e contadas em Dropped) para nunca travar quem está logando.
+type OTLPExporter struct {
+	endpoint    string
+	encoding    string
+	gzip        bool
+	headers     map[string]string
+	resource    []keyValue
+	batchSize   int
+	interval    time.Duration
+	timeout     time.Duration
+	maxRetries  int
+	client      *http.Client
+	onError     atomic.Pointer[func(error)]
+	dropped     atomic.Int64
+	queue       chan logRecord
+	flushReq    chan chan error
+	done        chan struct{}
+	closeOnce   sync.Once
+	closeMu     sync.RWMutex
+	closed      bool
+	sleep       func(time.Duration)
+	retryStatus map[int]bool
+}
+
+// NewOTLP cria o exportador e já inicia o envio em background.
+func NewOTLP(opts *kbx.LogzOTLPOptions) (*OTLPExporter, error) {
+	if opts == nil {
+		opts = &kbx.LogzOTLPOptions{}
+	}
+	x := &OTLPExporter{
+		endpoint:   kbx.GetValueOrDefaultSimple(opts.Endpoint, DefaultOTLPEndpoint),
+		encoding:   strings.ToLower(kbx.GetValueOrDefaultSimple(opts.Encoding, EncodingProtobuf)),
+		headers:    opts.Headers,
+		batchSize:  opts.BatchSize,
+		maxRetries: opts.MaxRetries,
+		client:     &http.Client{},
+		flushReq:   make(chan chan error),
+		done:       make(chan struct{}),
+		sleep:      time.Sleep,
+		retryStatus: map[int]bool{
+			http.StatusTooManyRequests:    true,
+			http.StatusBadGateway:         true,
+			http.StatusServiceUnavailable: true,
+			http.StatusGatewayTimeout:     true,
+		},
+	}
+	switch x.encoding {
+	case EncodingProtobuf, EncodingJSON:
+	default:
+		return nil, fmt.Errorf("logz: otlp encoding %q (want protobuf or json)", opts.Encoding)
+	}
+	switch strings.ToLower(opts.Compression) {
+	case "gzip":
+		x.gzip = true
+	case "", "none":
+	default:
+		return nil, fmt.Errorf("logz: otlp compression %q (want gzip or none)", opts.Compression)
+	}
+
+	var err error
+	if x.interval, err = parseDuration(opts.FlushInterval, defaultFlushInterval); err != nil {
+		return nil, fmt.Errorf("logz: otlp flush_interval: %w", err)
+	}
+	if x.timeout, err = parseDuration(opts.Timeout, defaultTimeout); err != nil {
+		return nil, fmt.Errorf("logz: otlp timeout: %w", err)
+	}
+	if x.batchSize <= 0 {
+		x.batchSize = defaultBatchSize
+	}
+	if x.maxRetries < 0 {
+		x.maxRetries = 0
+	} else if x.maxRetries == 0 {
+		x.maxRetries = defaultMaxRetries
+	}
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	x.queue = make(chan logRecord, queueSize)
+	x.resource = resourceAttributes(opts.Resource)
+
+	go x.run()
+	return x, nil
+}
+
+// resourceAttributes monta os atributos do Resource. Sem service.name,
+// usa o padrão do OpenTelemetry: unknown_service:<executável>.
+func resourceAttributes(res map[string]string) []keyValue {
+	keys := make([]string, 0, len(res)+1)
+	for k := range res {
+		keys = append(keys, k)
+	}
+	if _, ok := res["service.name"]; !ok {
+		keys = append(keys, "service.name")
+	}
+	sort.Strings(keys)
+	out := make([]keyValue, 0, len(keys))
+	for _, k := range keys {
+		v, ok := res[k]
+		if !ok && k == "service.name" {
+			v = "unknown_service:" + filepath.Base(os.Args[0])
+		}
+		out = append(out, keyValue{Key: k, Value: v})
+	}
+	return out
+}
+
+// OnError registra quem recebe as falhas de envio (lote descartado depois
+// de esgotar os retries). Por padrão elas vão pro stderr.
+func (x *OTLPExporter) OnError(fn func(error)) {
+	x.onError.Store(&fn)
+}
+
+// Dropped devolve quantos registros foram descartados (fila cheia ou
+// falha definitiva no envio).
+func (x *OTLPExporter) Dropped() int64 {
+	return x.dropped.Load()
+}
+
+// WriteEntry implementa interfaces.EntryWriter.
+func (x *OTLPExporter) WriteEntry(e kbx.Entry) error {
+	if e == nil {
+		return nil
+	}
+	return x.enqueue(recordFromEntry(e))
+}
+
+// Write aceita bytes já formatados: o JSON do JSONFormatter é aproveitado
+// campo a campo; qualquer outra coisa vira o body do registro.
+func (x *OTLPExporter) Write(p []byte) (int, error) {
+	if len(bytes.TrimSpace(p)) == 0 {
+		return len(p), nil
+	}
+	if err := x.enqueue(recordFromLine(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (x *OTLPExporter) enqueue(rec logRecord) error {
+	x.closeMu.RLock()
+	defer x.closeMu.RUnlock()
+	if x.closed {
+		return ErrClosed
+	}
+	select {
+	case x.queue <- rec:
+	default:
+		x.dropped.Add(1)
+	}
+	return nil
+}
+
+// Flush envia imediatamente o que estiver na fila e espera o resultado.
+func (x *OTLPExporter) Flush() error {
+	x.closeMu.RLock()
+	closed := x.closed
+	x.closeMu.RUnlock()
+	if closed {
+		return nil
+	}
+	res := make(chan error, 1)
+	select {
+	case x.flushReq <- res:
+		return <-res
+	case <-x.done:
+		return nil
+	}
+}
+
+// Sync é um alias de Flush (mesma assinatura dos writers de arquivo).
+func (x *OTLPExporter) Sync() error {
+	return x.Flush()
+}
+
+// Close envia o que falta e encerra o exportador.
+func (x *OTLPExporter) Close() error {
+	x.closeOnce.Do(func() {
+		x.closeMu.Lock()
+		x.closed = true
+		close(x.queue)
+		x.closeMu.Unlock()
+		<-x.done
+	})
+	return nil
+}
+
+// String descreve o destino.
+func (x *OTLPExporter) String() string {
+	return "OTLPExporter(" + x.endpoint + ", " + x.encoding + ")"
+}
+
+func (x *OTLPExporter) run() {
+	defer close(x.done)
+	ticker := time.NewTicker(x.interval)
+	defer ticker.Stop()
+
+	batch := make([]logRecord, 0, x.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := x.send(batch)
+		batch = make([]logRecord, 0, x.batchSize)
+		return err
+	}
+
+	for {
+		select {
+		case rec, ok := <-x.queue:
+			if !ok {
+				x.report(flush())
+				return
+			}
+			batch = append(batch, rec)
+			if len(batch) >= x.batchSize {
+				x.report(flush())
+			}
+		case <-ticker.C:
+			x.report(flush())
+		case res := <-x.flushReq:
+			// drena o que já está na fila antes de responder.
+			for drained := false; !drained; {
+				select {
+				case rec, ok := <-x.queue:
+					if !ok {
+						drained = true
+						break
+					}
+					batch = append(batch, rec)
+					if len(batch) >= x.batchSize {
+						x.report(flush())
+					}
+				default:
+					drained = true
+				}
+			}
+			res <- flush()
+		}
+	}
+}
+
+func (x *OTLPExporter) report(err error) {
+	if err == nil {
+		return
+	}
+	if fn := x.onError.Load(); fn != nil && *fn != nil {
+		(*fn)(err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "logz: otlp export: %v\n", err)
+}
+
+func (x *OTLPExporter) send(batch []logRecord) error {
+	body, contentType, err := x.encode(batch)
+	if err != nil {
+		x.dropped.Add(int64(len(batch)))
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= x.maxRetries; attempt++ {
+		retryAfter, err := x.post(body, contentType)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		var perm *permanentError
+		if errors.As(err, &perm) || attempt == x.maxRetries {
+			break
+		}
+		x.sleep(backoff(attempt, retryAfter))
+	}
+	x.dropped.Add(int64(len(batch)))
+	return fmt.Errorf("dropping %d records: %w", len(batch), lastErr)
+}
+
+func (x *OTLPExporter) encode(batch []logRecord) ([]byte, string, error) {
+	var raw []byte
+	contentType := "application/x-protobuf"
+	if x.encoding == EncodingJSON {
+		var err error
+		if raw, err = encodeJSON(x.resource, scopeName, batch); err != nil {
+			return nil, "", err
+		}
+		contentType = "application/json"
+	} else {
+		raw = encodeProto(x.resource, scopeName, batch)
+	}
+	if !x.gzip {
+		return raw, contentType, nil
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, "", err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), contentType, nil
+}
+
+// permanentError marca respostas que não adianta repetir (4xx etc).
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func (x *OTLPExporter) post(body []byte, contentType string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), x.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, x.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, &permanentError{err}
+	}
+	req.Header.Set("Content-Type", contentType)
+	if x.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range x.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+	err = fmt.Errorf("collector answered %s", resp.Status)
+	if !x.retryStatus[resp.StatusCode] {
+		return 0, &permanentError{err}
+	}
+	var retryAfter time.Duration
+	if s, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && s > 0 {
+		retryAfter = time.Duration(s) * time.Second
+	}
+	return retryAfter, err
+}
+
+func backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, maxBackoff)
+	}
+	d := 500 * time.Millisecond << attempt
+	return min(d, maxBackoff)
+}
+
+func parseDuration(s string, def time.Duration) (time.Duration, error) {
+	if strings.TrimSpace(s) == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil && d <= 0 {
+		err = fmt.Errorf("must be positive, got %s", d)
+	}
+	return d, err
+}
@@ -0,0 +1,469 @@
+package exporter
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// logRecord é o LogRecord do OTLP, já desacoplado da Entry (que o logger
+// pode reaproveitar depois do Write).
+type logRecord struct {
+	Time           time.Time
+	Observed       time.Time
+	SeverityNumber int32
+	SeverityText   string
+	Body           string
+	Attributes     []keyValue
+	TraceID        []byte
+	SpanID         []byte
+	Flags          uint32
+}
+
+// keyValue.Value é sempre um dos tipos aceitos por AnyValue: string, bool,
+// int64, float64, []byte, []any ou []keyValue.
+type keyValue struct {
+	Key   string
+	Value any
+}
+
+// SeverityNumber mapeia os níveis do logz pra escala do OpenTelemetry
+// (TRACE=1, DEBUG=5, INFO=9, WARN=13, ERROR=17, FATAL=21).
+func SeverityNumber(l kbx.Level) int32 {
+	switch kbx.Level(strings.ToLower(string(l))) {
+	case kbx.LevelTrace:
+		return 1
+	case kbx.LevelDebug:
+		return 5
+	case kbx.LevelInfo, kbx.LevelAnswer, kbx.LevelPrintln, kbx.LevelSprintf, "printf":
+		return 9
+	case kbx.LevelNotice:
+		return 10
+	case kbx.LevelSuccess:
+		return 11
+	case kbx.LevelWarn:
+		return 13
+	case kbx.LevelAlert:
+		return 14
+	case kbx.LevelError:
+		return 17
+	case kbx.LevelCritical:
+		return 19
+	case kbx.LevelFatal:
+		return 21
+	case kbx.LevelBug:
+		return 22
+	case kbx.LevelPanic:
+		return 23
+	default:
+		return 0
+	}
+}
+
+func recordFromEntry(e kbx.Entry) logRecord {
+	rec := logRecord{
+		Time:           e.GetTimestamp(),
+		Observed:       time.Now(),
+		SeverityNumber: SeverityNumber(e.GetLevel()),
+		SeverityText:   strings.ToUpper(string(e.GetLevel())),
+		Body:           e.GetMessage(),
+	}
+
+	fields := e.GetFields()
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		rec.Attributes = append(rec.Attributes, keyValue{Key: k, Value: anyValue(fields[k], 0)})
+	}
+
+	tags := e.GetTags()
+	tkeys := make([]string, 0, len(tags))
+	for k := range tags {
+		tkeys = append(tkeys, k)
+	}
+	sort.Strings(tkeys)
+	for _, k := range tkeys {
+		rec.Attributes = append(rec.Attributes, keyValue{Key: k, Value: tags[k]})
+	}
+
+	if c := e.GetContext(); c != "" {
+		rec.Attributes = append(rec.Attributes, keyValue{Key: "logz.context", Value: c})
+	}
+	if c := e.GetCaller(); c != "" && e.GetShowCaller() {
+		rec.Attributes = append(rec.Attributes, keyValue{Key: "logz.caller", Value: c})
+	}
+	if ee, ok := e.(interface{ GetError() error }); ok && ee.GetError() != nil {
+		rec.Attributes = append(rec.Attributes,
+			keyValue{Key: "exception.message", Value: ee.GetError().Error()},
+			keyValue{Key: "exception.type", Value: fmt.Sprintf("%T", ee.GetError())},
+		)
+	}
+
+	rec.TraceID = decodeID(e.GetTraceID(), 16)
+	if se, ok := e.(interface {
+		GetSpanID() string
+		GetTraceFlags() string
+	}); ok {
+		rec.SpanID = decodeID(se.GetSpanID(), 8)
+		if f, err := strconv.ParseUint(se.GetTraceFlags(), 16, 8); err == nil {
+			rec.Flags = uint32(f)
+		}
+	}
+	return rec
+}
+
+// recordFromLine converte uma linha já formatada. Se for o JSON do
+// JSONFormatter, aproveita a estrutura; senão a linha vira o body.
+func recordFromLine(p []byte) logRecord {
+	line := strings.TrimRight(string(p), "\r\n")
+	rec := logRecord{Time: time.Now(), Observed: time.Now(), Body: line}
+
+	var doc map[string]any
+	if json.Unmarshal(p, &doc) != nil {
+		return rec
+	}
+	msg, ok := doc["msg"].(string)
+	if !ok {
+		return rec
+	}
+	rec.Body = msg
+	if lvl, ok := doc["level"].(string); ok {
+		rec.SeverityNumber = SeverityNumber(kbx.Level(lvl))
+		rec.SeverityText = strings.ToUpper(lvl)
+	}
+	if ts, ok := doc["ts"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			rec.Time = t
+		}
+	}
+	for _, section := range []string{"fields", "tags"} {
+		m, _ := doc[section].(map[string]any)
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			rec.Attributes = append(rec.Attributes, keyValue{Key: k, Value: anyValue(m[k], 0)})
+		}
+	}
+	if c, ok := doc["ctx"].(string); ok && c != "" {
+		rec.Attributes = append(rec.Attributes, keyValue{Key: "logz.context", Value: c})
+	}
+	tid, _ := doc["trace"].(string)
+	sid, _ := doc["span_id"].(string)
+	rec.TraceID = decodeID(tid, 16)
+	rec.SpanID = decodeID(sid, 8)
+	if fl, ok := doc["trace_flags"].(string); ok {
+		if f, err := strconv.ParseUint(fl, 16, 8); err == nil {
+			rec.Flags = uint32(f)
+		}
+	}
+	return rec
+}
+
+func decodeID(s string, n int) []byte {
+	if len(s) != n*2 {
+		return nil
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+const maxValueDepth = 8
+
+// anyValue normaliza um valor de field para os tipos de AnyValue.
+func anyValue(v any, depth int) any {
+	switch tv := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return tv
+	case bool:
+		return tv
+	case int:
+		return int64(tv)
+	case int8:
+		return int64(tv)
+	case int16:
+		return int64(tv)
+	case int32:
+		return int64(tv)
+	case int64:
+		return tv
+	case uint8:
+		return int64(tv)
+	case uint16:
+		return int64(tv)
+	case uint32:
+		return int64(tv)
+	case uint:
+		if uint64(tv) <= math.MaxInt64 {
+			return int64(tv)
+		}
+		return strconv.FormatUint(uint64(tv), 10)
+	case uint64:
+		if tv <= math.MaxInt64 {
+			return int64(tv)
+		}
+		return strconv.FormatUint(tv, 10)
+	case float32:
+		return anyValue(float64(tv), depth)
+	case float64:
+		// NaN/Inf não existem no JSON do OTLP.
+		if math.IsNaN(tv) || math.IsInf(tv, 0) {
+			return strconv.FormatFloat(tv, 'g', -1, 64)
+		}
+		return tv
+	case []byte:
+		return tv
+	case time.Time:
+		return tv.Format(time.RFC3339Nano)
+	case time.Duration:
+		return tv.String()
+	case error:
+		return tv.Error()
+	case fmt.Stringer:
+		return tv.String()
+	}
+	if depth >= maxValueDepth {
+		return fmt.Sprint(v)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = anyValue(rv.Index(i).Interface(), depth+1)
+		}
+		return out
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Sprint(v)
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		out := make([]keyValue, 0, len(keys))
+		for _, k := range keys {
+			mv := rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()))
+			out = append(out, keyValue{Key: k, Value: anyValue(mv.Interface(), depth+1)})
+		}
+		return out
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return ""
+		}
+		return anyValue(rv.Elem().Interface(), depth+1)
+	}
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+//
+// ---------- protobuf (ExportLogsServiceRequest) ----------
+//
+
+func encodeProto(resource []keyValue, scope string, recs []logRecord) []byte {
+	var scopeLogs []byte
+	scopeLogs = appendMessage(scopeLogs, 1, protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), scope))
+	for _, r := range recs {
+		scopeLogs = appendMessage(scopeLogs, 2, encodeRecord(r))
+	}
+
+	var res []byte
+	for _, kv := range resource {
+		res = appendMessage(res, 1, encodeKeyValue(kv))
+	}
+
+	var rl []byte
+	rl = appendMessage(rl, 1, res)
+	rl = appendMessage(rl, 2, scopeLogs)
+
+	return appendMessage(nil, 1, rl)
+}
+
+func encodeRecord(r logRecord) []byte {
+	var b []byte
+	if !r.Time.IsZero() {
+		b = protowire.AppendTag(b, 1, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, uint64(r.Time.UnixNano()))
+	}
+	if r.SeverityNumber != 0 {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(r.SeverityNumber))
+	}
+	if r.SeverityText != "" {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendString(b, r.SeverityText)
+	}
+	b = appendMessage(b, 5, encodeAnyValue(r.Body))
+	for _, kv := range r.Attributes {
+		b = appendMessage(b, 6, encodeKeyValue(kv))
+	}
+	if r.Flags != 0 {
+		b = protowire.AppendTag(b, 8, protowire.Fixed32Type)
+		b = protowire.AppendFixed32(b, r.Flags)
+	}
+	if len(r.TraceID) > 0 {
+		b = protowire.AppendTag(b, 9, protowire.BytesType)
+		b = protowire.AppendBytes(b, r.TraceID)
+	}
+	if len(r.SpanID) > 0 {
+		b = protowire.AppendTag(b, 10, protowire.BytesType)
+		b = protowire.AppendBytes(b, r.SpanID)
+	}
+	if !r.Observed.IsZero() {
+		b = protowire.AppendTag(b, 11, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, uint64(r.Observed.UnixNano()))
+	}
+	return b
+}
+
+func encodeKeyValue(kv keyValue) []byte {
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	b = protowire.AppendString(b, kv.Key)
+	return appendMessage(b, 2, encodeAnyValue(kv.Value))
+}
+
+func encodeAnyValue(v any) []byte {
+	var b []byte
+	switch tv := v.(type) {
+	case string:
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, tv)
+	case bool:
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(tv))
+	case int64:
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(tv))
+	case float64:
+		b = protowire.AppendTag(b, 4, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(tv))
+	case []any:
+		var arr []byte
+		for _, item := range tv {
+			arr = appendMessage(arr, 1, encodeAnyValue(item))
+		}
+		b = appendMessage(b, 5, arr)
+	case []keyValue:
+		var kvl []byte
+		for _, kv := range tv {
+			kvl = appendMessage(kvl, 1, encodeKeyValue(kv))
+		}
+		b = appendMessage(b, 6, kvl)
+	case []byte:
+		b = protowire.AppendTag(b, 7, protowire.BytesType)
+		b = protowire.AppendBytes(b, tv)
+	}
+	return b
+}
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+//
+// ---------- JSON (mapeamento protobuf -> JSON do OTLP) ----------
+//
+
+func encodeJSON(resource []keyValue, scope string, recs []logRecord) ([]byte, error) {
+	records := make([]map[string]any, 0, len(recs))
+	for _, r := range recs {
+		m := map[string]any{
+			"body":       jsonAnyValue(r.Body),
+			"attributes": jsonKeyValues(r.Attributes),
+		}
+		if !r.Time.IsZero() {
+			m["timeUnixNano"] = strconv.FormatInt(r.Time.UnixNano(), 10)
+		}
+		if !r.Observed.IsZero() {
+			m["observedTimeUnixNano"] = strconv.FormatInt(r.Observed.UnixNano(), 10)
+		}
+		if r.SeverityNumber != 0 {
+			m["severityNumber"] = r.SeverityNumber
+		}
+		if r.SeverityText != "" {
+			m["severityText"] = r.SeverityText
+		}
+		if r.Flags != 0 {
+			m["flags"] = r.Flags
+		}
+		// no JSON do OTLP, trace/span ids vão em hex (não base64).
+		if len(r.TraceID) > 0 {
+			m["traceId"] = hex.EncodeToString(r.TraceID)
+		}
+		if len(r.SpanID) > 0 {
+			m["spanId"] = hex.EncodeToString(r.SpanID)
+		}
+		records = append(records, m)
+	}
+	return json.Marshal(map[string]any{
+		"resourceLogs": []any{
+			map[string]any{
+				"resource": map[string]any{"attributes": jsonKeyValues(resource)},
+				"scopeLogs": []any{
+					map[string]any{
+						"scope":      map[string]any{"name": scope},
+						"logRecords": records,
+					},
+				},
+			},
+		},
+	})
+}
+
+func jsonKeyValues(kvs []keyValue) []any {
+	out := make([]any, 0, len(kvs))
+	for _, kv := range kvs {
+		out = append(out, map[string]any{"key": kv.Key, "value": jsonAnyValue(kv.Value)})
+	}
+	return out
+}
+
+func jsonAnyValue(v any) map[string]any {
+	switch tv := v.(type) {
+	case string:
+		return map[string]any{"stringValue": tv}
+	case bool:
+		return map[string]any{"boolValue": tv}
+	case int64:
+		return map[string]any{"intValue": strconv.FormatInt(tv, 10)}
+	case float64:
+		return map[string]any{"doubleValue": tv}
+	case []any:
+		vals := make([]any, 0, len(tv))
+		for _, item := range tv {
+			vals = append(vals, jsonAnyValue(item))
+		}
+		return map[string]any{"arrayValue": map[string]any{"values": vals}}
+	case []keyValue:
+		return map[string]any{"kvlistValue": map[string]any{"values": jsonKeyValues(tv)}}
+	case []byte:
+		return map[string]any{"bytesValue": tv}
+	}
+	return map[string]any{"stringValue": fmt.Sprint(v)}
+}
@@ -0,0 +1,359 @@
+package exporter_test
+
+import (
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/exporter"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+const (
+	traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	spanID  = "00f067aa0ba902b7"
+)
+
+// collector é um OTLP/HTTP de mentira: guarda os corpos (já sem gzip) e
+// responde com os status de statuses, na ordem (200 depois que acabam).
+type collector struct {
+	*httptest.Server
+	mu       sync.Mutex
+	bodies   [][]byte
+	headers  []http.Header
+	statuses []int
+}
+
+func newCollector(t *testing.T, statuses ...int) *collector {
+	c := &collector{statuses: statuses}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("gzip: %v", err)
+				return
+			}
+			body = zr
+		}
+		b, _ := io.ReadAll(body)
+		c.mu.Lock()
+		c.bodies = append(c.bodies, b)
+		c.headers = append(c.headers, r.Header.Clone())
+		status := http.StatusOK
+		if len(c.statuses) > 0 {
+			status, c.statuses = c.statuses[0], c.statuses[1:]
+		}
+		c.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func (c *collector) requests() ([][]byte, []http.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]byte(nil), c.bodies...), append([]http.Header(nil), c.headers...)
+}
+
+func testEntry(t *testing.T) *core.Entry {
+	t.Helper()
+	e, err := core.NewEntry(kbx.LevelError)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.WithMessage("payment failed").
+		WithFields(map[string]any{"order": 42, "retry": true})
+	e.WithTraceContext(kbx.TraceContext{TraceID: traceID, SpanID: spanID, TraceFlags: 1})
+	e.Timestamp = time.Unix(1700000000, 5)
+	return e
+}
+
+// msg é uma mensagem protobuf decodificada: número do campo -> valores
+// (uint64 pra varint/fixed, []byte pra length-delimited).
+type msg map[protowire.Number][]any
+
+func decodeProto(t *testing.T, b []byte) msg {
+	t.Helper()
+	m := msg{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("bad tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		var v any
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			var x uint32
+			x, n = protowire.ConsumeFixed32(b)
+			v = uint64(x)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		default:
+			t.Fatalf("unexpected wire type %v", typ)
+		}
+		if n < 0 {
+			t.Fatalf("bad field %d: %v", num, protowire.ParseError(n))
+		}
+		m[num] = append(m[num], v)
+		b = b[n:]
+	}
+	return m
+}
+
+func (m msg) sub(t *testing.T, num protowire.Number) msg {
+	t.Helper()
+	if len(m[num]) == 0 {
+		t.Fatalf("field %d missing", num)
+	}
+	return decodeProto(t, m[num][0].([]byte))
+}
+
+func (m msg) str(num protowire.Number) string {
+	if len(m[num]) == 0 {
+		return ""
+	}
+	return string(m[num][0].([]byte))
+}
+
+// attrs decodifica KeyValues com valor string/bool/int.
+func attrs(t *testing.T, raw []any) map[string]any {
+	out := map[string]any{}
+	for _, r := range raw {
+		kv := decodeProto(t, r.([]byte))
+		val := kv.sub(t, 2)
+		switch {
+		case len(val[1]) > 0:
+			out[kv.str(1)] = val.str(1)
+		case len(val[2]) > 0:
+			out[kv.str(1)] = val[2][0].(uint64) == 1
+		case len(val[3]) > 0:
+			out[kv.str(1)] = int64(val[3][0].(uint64))
+		}
+	}
+	return out
+}
+
+func TestOTLPProtobuf(t *testing.T) {
+	c := newCollector(t)
+	x, err := exporter.NewOTLP(&kbx.LogzOTLPOptions{
+		Endpoint:    c.URL,
+		Compression: "gzip",
+		Headers:     map[string]string{"X-Tenant": "acme"},
+		Resource:    map[string]string{"service.name": "billing", "env": "prod"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := x.WriteEntry(testEntry(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := x.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bodies, headers := c.requests()
+	if len(bodies) != 1 {
+		t.Fatalf("got %d requests", len(bodies))
+	}
+	if ct := headers[0].Get("Content-Type"); ct != "application/x-protobuf" {
+		t.Errorf("content-type = %q", ct)
+	}
+	if headers[0].Get("X-Tenant") != "acme" {
+		t.Error("custom header missing")
+	}
+
+	rl := decodeProto(t, bodies[0]).sub(t, 1)
+	res := attrs(t, rl.sub(t, 1)[1])
+	if res["service.name"] != "billing" || res["env"] != "prod" {
+		t.Errorf("resource = %v", res)
+	}
+	sl := rl.sub(t, 2)
+	if name := sl.sub(t, 1).str(1); name != "github.com/kubex-ecosystem/logz" {
+		t.Errorf("scope = %q", name)
+	}
+	if len(sl[2]) != 1 {
+		t.Fatalf("got %d log records", len(sl[2]))
+	}
+	rec := decodeProto(t, sl[2][0].([]byte))
+	if rec[1][0].(uint64) != uint64(time.Unix(1700000000, 5).UnixNano()) {
+		t.Errorf("time = %v", rec[1][0])
+	}
+	if rec[2][0].(uint64) != 17 || rec.str(3) != "ERROR" {
+		t.Errorf("severity = %v %q", rec[2][0], rec.str(3))
+	}
+	if body := rec.sub(t, 5).str(1); body != "payment failed" {
+		t.Errorf("body = %q", body)
+	}
+	at := attrs(t, rec[6])
+	if at["order"] != int64(42) || at["retry"] != true {
+		t.Errorf("attributes = %v", at)
+	}
+	if hex.EncodeToString(rec[9][0].([]byte)) != traceID || hex.EncodeToString(rec[10][0].([]byte)) != spanID {
+		t.Errorf("trace/span = %x/%x", rec[9][0], rec[10][0])
+	}
+	if rec[8][0].(uint64) != 1 {
+		t.Errorf("flags = %v", rec[8][0])
+	}
+}
+
+func TestOTLPJSON(t *testing.T) {
+	c := newCollector(t)
+	x, err := exporter.NewOTLP(&kbx.LogzOTLPOptions{Endpoint: c.URL, Encoding: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = x.WriteEntry(testEntry(t))
+	// bytes do JSONFormatter também viram registro estruturado.
+	line, _ := json.Marshal(map[string]any{"level": "warn", "msg": "slow", "trace": traceID, "span_id": spanID})
+	if _, err := x.Write(append(line, '\n')); err != nil {
+		t.Fatal(err)
+	}
+	if err := x.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	x.Close()
+
+	bodies, headers := c.requests()
+	if len(bodies) != 1 || headers[0].Get("Content-Type") != "application/json" {
+		t.Fatalf("requests = %d, %v", len(bodies), headers)
+	}
+	var req struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string
+					Value map[string]any
+				}
+			}
+			ScopeLogs []struct {
+				Scope      struct{ Name string }
+				LogRecords []struct {
+					TimeUnixNano   string
+					SeverityNumber int
+					SeverityText   string
+					Body           map[string]any
+					TraceID        string `json:"traceId"`
+					SpanID         string `json:"spanId"`
+					Flags          int
+					Attributes     []struct {
+						Key   string
+						Value map[string]any
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(bodies[0], &req); err != nil {
+		t.Fatalf("%v: %s", err, bodies[0])
+	}
+	rl := req.ResourceLogs[0]
+	if a := rl.Resource.Attributes; len(a) != 1 || a[0].Key != "service.name" {
+		t.Errorf("resource = %+v", a)
+	}
+	sl := rl.ScopeLogs[0]
+	if sl.Scope.Name != "github.com/kubex-ecosystem/logz" || len(sl.LogRecords) != 2 {
+		t.Fatalf("scope logs = %+v", sl)
+	}
+	r := sl.LogRecords[0]
+	if r.SeverityNumber != 17 || r.Body["stringValue"] != "payment failed" || r.Flags != 1 {
+		t.Errorf("record = %+v", r)
+	}
+	if r.TraceID != traceID || r.SpanID != spanID {
+		t.Errorf("trace/span = %s/%s", r.TraceID, r.SpanID)
+	}
+	if r.TimeUnixNano != "1700000000000000005" {
+		t.Errorf("time = %s", r.TimeUnixNano)
+	}
+	if r.Attributes[0].Key != "order" || r.Attributes[0].Value["intValue"] != "42" {
+		t.Errorf("attributes = %+v", r.Attributes)
+	}
+	w := sl.LogRecords[1]
+	if w.SeverityText != "WARN" || w.Body["stringValue"] != "slow" || w.TraceID != traceID || w.SpanID != spanID {
+		t.Errorf("line record = %+v", w)
+	}
+}
+
+func TestOTLPRetryAndPermanentError(t *testing.T) {
+	c := newCollector(t, http.StatusServiceUnavailable, http.StatusBadRequest)
+	x, err := exporter.NewOTLP(&kbx.LogzOTLPOptions{Endpoint: c.URL, MaxRetries: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	x.OnError(func(err error) { mu.Lock(); errs = append(errs, err); mu.Unlock() })
+
+	// 503 é repetido; o 400 seguinte é definitivo: o lote é descartado.
+	_ = x.WriteEntry(testEntry(t))
+	if err := x.Flush(); err == nil {
+		t.Fatal("Flush should report the 400")
+	}
+	if n, _ := c.requests(); len(n) != 2 {
+		t.Errorf("got %d requests, want 2", len(n))
+	}
+	if x.Dropped() != 1 {
+		t.Errorf("Dropped = %d", x.Dropped())
+	}
+
+	// o próximo lote passa.
+	_ = x.WriteEntry(testEntry(t))
+	if err := x.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	x.Close()
+	if err := x.WriteEntry(testEntry(t)); !errors.Is(err, exporter.ErrClosed) {
+		t.Errorf("write after Close = %v", err)
+	}
+}
+
+func TestLoggerCloseSendsLastBatch(t *testing.T) {
+	c := newCollector(t)
+	x, err := exporter.NewOTLP(&kbx.LogzOTLPOptions{Endpoint: c.URL, FlushInterval: "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := core.NewLoggerOptions(nil)
+	opts.Output = io.Discard
+	l := core.NewLogger("", opts, false)
+	l.AddExporter(x)
+
+	if err := l.Log(kbx.LevelInfo, core.NewLogzEntry(kbx.LevelInfo).WithMessage("last words")); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := c.requests(); len(n) != 0 {
+		t.Fatalf("sent before Close: %d", len(n))
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	bodies, _ := c.requests()
+	if len(bodies) != 1 {
+		t.Fatalf("got %d requests after Close", len(bodies))
+	}
+	rec := decodeProto(t, decodeProto(t, decodeProto(t, bodies[0]).sub(t, 1)[2][0].([]byte))[2][0].([]byte))
+	if body := rec.sub(t, 5).str(1); body != "last words" {
+		t.Errorf("body = %q", body)
+	}
+	if err := x.WriteEntry(testEntry(t)); !errors.Is(err, exporter.ErrClosed) {
+		t.Errorf("exporter still open after logger Close: %v", err)
+	}
+}
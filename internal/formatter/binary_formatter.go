@@ -0,0 +1,103 @@
+package formatter
+
+import (
+	"github.com/kubex-ecosystem/logz/internal/binlog"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// BinaryFormatter grava cada entry como um registro binário enquadrado
+// (ver internal/binlog): prefixo de tamanho + mapa MessagePack ou CBOR,
+// ou mensagem protobuf delimitada por varint.
+// É lossless como o JSON, só que menor e mais barato de gerar; o
+// "logz logger read" lê os arquivos de volta.
+type BinaryFormatter struct {
+	Codec binlog.Codec
+}
+
+// Binary é implementado pelos formatters cuja saída não é texto: o logger
+// não acrescenta a quebra de linha no fim de cada registro.
+type Binary interface {
+	Binary() bool
+}
+
+// IsBinary diz se f produz saída binária.
+func IsBinary(f Formatter) bool {
+	b, ok := f.(Binary)
+	return ok && b.Binary()
+}
+
+func NewMsgpackFormatter(pretty bool) Formatter {
+	return &BinaryFormatter{Codec: binlog.MsgPack}
+}
+
+func NewCBORFormatter(pretty bool) Formatter {
+	return &BinaryFormatter{Codec: binlog.CBOR}
+}
+
+func NewProtobufFormatter(pretty bool) Formatter {
+	return &BinaryFormatter{Codec: binlog.Proto}
+}
+
+func (f *BinaryFormatter) Name() string {
+	return f.codec().Name()
+}
+
+// Binary implementa Binary.
+func (f *BinaryFormatter) Binary() bool { return true }
+
+func (f *BinaryFormatter) Format(e kbx.Entry) ([]byte, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return binlog.Encode(f.codec(), RecordOf(e))
+}
+
+func (f *BinaryFormatter) codec() binlog.Codec {
+	if f.Codec == nil {
+		return binlog.MsgPack
+	}
+	return f.Codec
+}
+
+// RecordOf converte a entry no Record do esquema binário.
+func RecordOf(e kbx.Entry) *binlog.Record {
+	fields := kbx.ResolveFields(e.GetFields())
+	spanID, traceFlags := spanOf(e)
+	r := &binlog.Record{
+		Version:    binlog.Version,
+		Time:       e.GetTimestamp(),
+		Level:      e.GetLevel(),
+		Message:    e.GetMessage(),
+		Context:    e.GetContext(),
+		TraceID:    e.GetTraceID(),
+		SpanID:     spanID,
+		TraceFlags: traceFlags,
+		Caller:     e.GetCaller(),
+		Severity:   e.GetLevel().Severity(),
+		Tags:       e.GetTags(),
+		Fields:     fields,
+		Format:     e.GetFormat(),
+	}
+	if se, ok := e.(interface{ GetSource() string }); ok {
+		r.Source = se.GetSource()
+	}
+	if ee, ok := e.(interface{ GetError() error }); ok && ee.GetError() != nil {
+		r.Error = ee.GetError().Error()
+	}
+	for _, fl := range []struct {
+		on  bool
+		bit uint64
+	}{
+		{e.GetShowColor(), binlog.FlagShowColor},
+		{e.GetShowIcon(), binlog.FlagShowIcon},
+		{e.GetShowTraceID(), binlog.FlagShowTraceID},
+		{e.GetShowCaller(), binlog.FlagShowCaller},
+		{e.GetShowStack(), binlog.FlagShowStack},
+		{e.GetShowFields(), binlog.FlagShowFields},
+	} {
+		if fl.on {
+			r.Flags |= fl.bit
+		}
+	}
+	return r
+}
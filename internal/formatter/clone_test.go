@@ -0,0 +1,36 @@
+package formatter_test
+
+import (
+	"testing"
+
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	control "github.com/kubex-ecosystem/logz/internal/manager/control"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// Ajustar o clone não pode alcançar o original, nem os formatters das
+// regras do dynamic.
+func TestCloneIsIndependent(t *testing.T) {
+	for _, name := range formatter.Names() {
+		f, err := formatter.New(name, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := formatter.Clone(f)
+		if _, ok := f.(formatter.Cloner); ok && c == f {
+			t.Errorf("%s: Clone returned the same formatter", name)
+		}
+	}
+
+	text := formatter.NewTextFormatter(true).(*formatter.TextFormatter)
+	text.Term = kbx.TermInfo{Color: kbx.ColorBasic}
+	d := formatter.NewDynamicFormatter(true).(*formatter.DynamicFormatter)
+	d.WhenLevel(kbx.LevelWarn, text)
+
+	c := formatter.Clone(d)
+	formatter.ApplyTerm(c, kbx.TermInfo{TTY: true, Color: kbx.ColorTrueColor})
+	formatter.ApplySecurity(c, control.SecSanitize)
+	if text.Sanitize || text.Term != (kbx.TermInfo{Color: kbx.ColorBasic}) {
+		t.Errorf("original rule formatter changed: %+v", text)
+	}
+}
@@ -0,0 +1,536 @@
+package formatter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// ConsoleFormatter é o renderizador pra desenvolvimento local: colunas
+// alinhadas (hora, tempo desde a entry anterior, nível, contexto),
+// fields numa árvore indentada com os tipos coloridos, erros com a cadeia
+// de causas, stacks e trechos do código-fonte, e mensagens quebradas na
+// largura do terminal.
+//
+// Não é pensado pra produção: guarda estado (o tempo relativo) e lê
+// arquivos-fonte do disco pra montar os trechos.
+type ConsoleFormatter struct {
+	// TimeLayout e o fuso local são o padrão; Time, quando configurado,
+	// tem prioridade.
+	TimeLayout string
+	// Relative mostra, ao lado da hora, quanto tempo passou desde a
+	// entry anterior (+12ms, +1.5s).
+	Relative bool
+	// ContextWidth é a largura da coluna de contexto (0 = 12); contextos
+	// mais longos são cortados.
+	ContextWidth int
+	// Snippets mostra o código-fonte em volta do caller (ou do primeiro
+	// frame da stack) nas entries de nível error pra cima.
+	Snippets bool
+	// SnippetLines é quantas linhas antes/depois entram no trecho (0 = 2).
+	SnippetLines int
+	// Width é a largura pra quebrar mensagens: 0 = a do terminal (Term),
+	// negativo = não quebra.
+	Width int
+
+	Sanitize bool
+	Time     kbx.TimeFormat
+	Term     kbx.TermInfo
+	// Theme dá cor, ícone e rótulo de cada nível; zero = tema "default".
+	Theme Theme
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewConsoleFormatter cria o ConsoleFormatter; pretty liga cores e ícones
+// (que ainda dependem do destino ser um terminal, ver SetTerm).
+func NewConsoleFormatter(pretty bool) Formatter {
+	f := &ConsoleFormatter{
+		TimeLayout: "15:04:05.000",
+		Relative:   true,
+		Snippets:   true,
+		Term:       kbx.DetectTerm(os.Stdout, nil),
+	}
+	if !pretty {
+		f.Term.Color = kbx.ColorNone
+		f.Term.TTY = false
+	}
+	return f
+}
+
+func (f *ConsoleFormatter) Name() string {
+	return "console"
+}
+
+// Clone implementa Cloner. A cópia começa sem entry anterior: o tempo
+// relativo recomeça nela.
+func (f *ConsoleFormatter) Clone() Formatter {
+	return &ConsoleFormatter{
+		TimeLayout:   f.TimeLayout,
+		Relative:     f.Relative,
+		ContextWidth: f.ContextWidth,
+		Snippets:     f.Snippets,
+		SnippetLines: f.SnippetLines,
+		Width:        f.Width,
+		Sanitize:     f.Sanitize,
+		Time:         f.Time,
+		Term:         f.Term,
+		Theme:        f.Theme,
+	}
+}
+
+// SetSanitize implementa Sanitizer.
+func (f *ConsoleFormatter) SetSanitize(enabled bool) {
+	f.Sanitize = enabled
+}
+
+// SetTerm implementa TermFormatter.
+func (f *ConsoleFormatter) SetTerm(t kbx.TermInfo) {
+	f.Term = t
+}
+
+// SetTheme implementa ThemedFormatter.
+func (f *ConsoleFormatter) SetTheme(t Theme) {
+	f.Theme = t
+}
+
+// SetTimeFormat implementa TimeFormatter.
+func (f *ConsoleFormatter) SetTimeFormat(tf kbx.TimeFormat) {
+	f.Time = tf
+}
+
+// Cores fixas dos detalhes (os níveis vêm do tema).
+var (
+	consoleDim    = LevelColor{Basic: "2", Ansi256: 244, RGB: "#808080"}
+	consoleString = LevelColor{Basic: "32", Ansi256: 114, RGB: "#98c379"}
+	consoleNumber = LevelColor{Basic: "36", Ansi256: 80, RGB: "#56b6c2"}
+	consoleBool   = LevelColor{Basic: "33", Ansi256: 179, RGB: "#e5c07b"}
+	consoleTime   = LevelColor{Basic: "35", Ansi256: 176, RGB: "#c678dd"}
+	consoleError  = LevelColor{Basic: "31", Ansi256: 203, RGB: "#e06c75", Bold: true}
+	consoleKey    = LevelColor{Bold: true}
+
+	// contextos ganham uma cor estável, escolhida pelo hash do nome.
+	consoleContexts = []LevelColor{
+		{Basic: "36", Ansi256: 44, RGB: "#00d7d7", Bold: true},
+		{Basic: "35", Ansi256: 170, RGB: "#d75fd7", Bold: true},
+		{Basic: "34", Ansi256: 75, RGB: "#5fafff", Bold: true},
+		{Basic: "33", Ansi256: 178, RGB: "#d7af00", Bold: true},
+		{Basic: "32", Ansi256: 78, RGB: "#5fd787", Bold: true},
+		{Basic: "96", Ansi256: 123, RGB: "#87ffff", Bold: true},
+		{Basic: "95", Ansi256: 213, RGB: "#ff87ff", Bold: true},
+		{Basic: "94", Ansi256: 111, RGB: "#87afff", Bold: true},
+	}
+)
+
+const (
+	consoleIndent   = "    "
+	consoleMaxDepth = 6
+	consoleMaxStack = 12
+)
+
+// stackFields são os fields que, sendo texto, são tratados como stack.
+var stackFields = []string{"stack", "stacktrace"}
+
+func (f *ConsoleFormatter) Format(e kbx.Entry) ([]byte, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	e = resolved(e)
+
+	theme := f.Theme.orDefault()
+	lvl := e.GetLevel()
+	ts := e.GetTimestamp()
+	var b strings.Builder
+
+	// --- colunas -------------------------------------------------------
+	layout := kbx.GetValueOrDefaultSimple(f.TimeLayout, "15:04:05.000")
+	clock := f.Time.Or(kbx.TimeFormat{Layout: layout, Location: time.Local}).Format(ts)
+	prefixW := utf8.RuneCountInString(clock) + 1
+	b.WriteString(f.paint(consoleDim, clock))
+	b.WriteByte(' ')
+
+	if f.Relative {
+		rel := padLeft(f.delta(ts), 8)
+		b.WriteString(f.paint(consoleDim, rel))
+		b.WriteByte(' ')
+		prefixW += 9
+	}
+
+	label := theme.Label(lvl)
+	levelW := theme.Width
+	if levelW < 8 {
+		levelW = 8
+	}
+	if e.GetShowColor() {
+		b.WriteString(theme.Paint(f.Term.Color, lvl, label))
+	} else {
+		b.WriteString(label)
+	}
+	b.WriteString(padRight("", levelW-utf8.RuneCountInString(label)) + " ")
+	prefixW += max(levelW, utf8.RuneCountInString(label)) + 1
+
+	ctxW := f.ContextWidth
+	if ctxW <= 0 {
+		ctxW = 12
+	}
+	ctx := truncate(f.clean(e.GetContext()), ctxW)
+	if ctx != "" {
+		b.WriteString(f.paint(contextColor(ctx), ctx))
+	}
+	b.WriteString(padRight("", ctxW-utf8.RuneCountInString(ctx)) + " ")
+	prefixW += ctxW + 1
+
+	// --- mensagem ------------------------------------------------------
+	msg := f.clean(strings.TrimSpace(e.GetMessage()))
+	if ic := theme.Icon(lvl); ic != "" && f.Term.TTY && e.GetShowIcon() {
+		msg = ic + " " + msg
+	}
+	for i, line := range wrap(msg, f.wrapWidth()-prefixW) {
+		if i > 0 {
+			b.WriteString("\n" + strings.Repeat(" ", prefixW))
+		}
+		b.WriteString(line)
+	}
+	b.WriteByte('\n')
+
+	// --- detalhes ------------------------------------------------------
+	if t := tracePairs(e); t != "" {
+		b.WriteString(consoleIndent + f.paint(consoleDim, "trace "+f.clean(t)) + "\n")
+	}
+	if tags := e.GetTags(); len(tags) > 0 {
+		keys := make([]string, 0, len(tags))
+		for k := range tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = f.clean(k) + "=" + f.clean(tags[k])
+		}
+		b.WriteString(consoleIndent + f.paint(consoleDim, "tags "+strings.Join(pairs, " ")) + "\n")
+	}
+
+	fields, stack := splitStack(e.GetFields())
+	if len(fields) > 0 {
+		f.writeTree(&b, consoleIndent, fields, 0)
+	}
+
+	if ee, ok := e.(interface{ GetError() error }); ok && ee.GetError() != nil {
+		f.writeError(&b, ee.GetError())
+	}
+
+	severe := lvl.Severity() >= kbx.LevelError.Severity()
+	frames := parseStack(stack)
+	switch {
+	case len(frames) > 0:
+		f.writeStack(&b, frames, severe)
+	case e.GetCaller() != "" && (severe || e.GetShowCaller()):
+		fr := parseCaller(e.GetCaller())
+		b.WriteString(consoleIndent + f.paint(consoleDim, "at "+f.clean(e.GetCaller())) + "\n")
+		if severe && f.Snippets && fr.file != "" && userFrame(fr) {
+			f.writeSnippet(&b, fr)
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+// delta devolve o tempo desde a entry anterior ("" na primeira).
+func (f *ConsoleFormatter) delta(ts time.Time) string {
+	f.mu.Lock()
+	last := f.last
+	if ts.After(f.last) {
+		f.last = ts
+	}
+	f.mu.Unlock()
+	if last.IsZero() || ts.Before(last) {
+		return ""
+	}
+	return "+" + shortDuration(ts.Sub(last))
+}
+
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return strconv.FormatInt(d.Microseconds(), 10) + "µs"
+	case d < time.Second:
+		return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+	case d < time.Minute:
+		return strconv.FormatFloat(d.Seconds(), 'f', 1, 64) + "s"
+	}
+	return d.Round(time.Second).String()
+}
+
+func (f *ConsoleFormatter) wrapWidth() int {
+	switch {
+	case f.Width < 0:
+		return 0
+	case f.Width > 0:
+		return f.Width
+	}
+	return f.Term.Width
+}
+
+func (f *ConsoleFormatter) paint(c LevelColor, s string) string {
+	seq := c.Sequence(f.Term.Color)
+	if seq == "" {
+		return s
+	}
+	return seq + s + reset
+}
+
+func (f *ConsoleFormatter) clean(s string) string {
+	if f.Sanitize {
+		return Sanitize(s)
+	}
+	return s
+}
+
+func contextColor(ctx string) LevelColor {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(ctx))
+	return consoleContexts[h.Sum32()%uint32(len(consoleContexts))]
+}
+
+// --- árvore de fields -------------------------------------------------------
+
+func (f *ConsoleFormatter) writeTree(b *strings.Builder, indent string, m map[string]any, depth int) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		f.writeNode(b, indent, f.clean(k), m[k], i == len(keys)-1, depth)
+	}
+}
+
+func (f *ConsoleFormatter) writeNode(b *strings.Builder, indent, key string, v any, last bool, depth int) {
+	branch, next := "├─ ", "│  "
+	if last {
+		branch, next = "└─ ", "   "
+	}
+	b.WriteString(indent + f.paint(consoleDim, branch) + f.paint(consoleKey, key))
+
+	children := treeChildren(v)
+	if children == nil || depth >= consoleMaxDepth {
+		b.WriteString(": " + f.value(v) + "\n")
+		return
+	}
+	if len(children) == 0 {
+		b.WriteString(": " + f.paint(consoleDim, "(empty)") + "\n")
+		return
+	}
+	b.WriteByte('\n')
+	for i, c := range children {
+		f.writeNode(b, indent+f.paint(consoleDim, next), f.clean(c.key), c.value, i == len(children)-1, depth+1)
+	}
+}
+
+type treeChild struct {
+	key   string
+	value any
+}
+
+// treeChildren abre mapas e listas em nós filhos; nil pros valores folha.
+func treeChildren(v any) []treeChild {
+	if v == nil {
+		return nil
+	}
+	if _, ok := v.(error); ok {
+		return nil
+	}
+	if _, ok := v.(fmt.Stringer); ok {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		out := make([]treeChild, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out = append(out, treeChild{fmt.Sprint(iter.Key().Interface()), iter.Value().Interface()})
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].key < out[j].key })
+		return out
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return nil // []byte: folha
+		}
+		out := make([]treeChild, rv.Len())
+		for i := range out {
+			out[i] = treeChild{"[" + strconv.Itoa(i) + "]", rv.Index(i).Interface()}
+		}
+		return out
+	}
+	return nil
+}
+
+// value escreve um valor folha com a cor do tipo.
+func (f *ConsoleFormatter) value(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return f.paint(consoleDim, "nil")
+	case string:
+		return f.paint(consoleString, strconv.Quote(x))
+	case bool:
+		return f.paint(consoleBool, strconv.FormatBool(x))
+	case json.Number:
+		return f.paint(consoleNumber, x.String())
+	case time.Duration:
+		return f.paint(consoleTime, x.String())
+	case time.Time:
+		return f.paint(consoleTime, x.Format(time.RFC3339Nano))
+	case error:
+		return f.paint(consoleError, f.clean(x.Error()))
+	case []byte:
+		return f.paint(consoleString, strconv.Quote(string(x)))
+	case fmt.Stringer:
+		return f.clean(x.String())
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return f.paint(consoleNumber, fmt.Sprint(v))
+	case reflect.Bool:
+		return f.paint(consoleBool, fmt.Sprint(v))
+	case reflect.String:
+		return f.paint(consoleString, strconv.Quote(fmt.Sprint(v)))
+	}
+	return f.clean(fmt.Sprintf("%+v", v))
+}
+
+// --- erros ------------------------------------------------------------------
+
+// writeError mostra o erro e, embaixo, cada causa da cadeia (Unwrap),
+// com o tipo concreto de cada uma.
+func (f *ConsoleFormatter) writeError(b *strings.Builder, err error) {
+	f.writeErrorLine(b, consoleIndent, "error: ", consoleError, consoleError, err)
+	indent := consoleIndent + "  "
+	for i, cause := range causes(err) {
+		if i >= 10 {
+			b.WriteString(indent + f.paint(consoleDim, "…") + "\n")
+			break
+		}
+		f.writeErrorLine(b, indent, "↳ ", consoleDim, LevelColor{}, cause)
+	}
+}
+
+// writeErrorLine escreve o texto do erro depois do marcador (as linhas
+// seguintes, como as do errors.Join, ficam alinhadas a ele) e o tipo no fim.
+func (f *ConsoleFormatter) writeErrorLine(b *strings.Builder, indent, marker string, mc, c LevelColor, err error) {
+	cont := "\n" + indent + strings.Repeat(" ", utf8.RuneCountInString(marker))
+	b.WriteString(indent + f.paint(mc, marker))
+	for i, line := range strings.Split(f.clean(err.Error()), "\n") {
+		if i > 0 {
+			b.WriteString(cont)
+		}
+		b.WriteString(f.paint(c, line))
+	}
+	b.WriteString(" " + f.paint(consoleDim, fmt.Sprintf("(%T)", err)) + "\n")
+}
+
+// causes percorre a cadeia de Unwrap (incluindo errors.Join) em
+// profundidade, sem o próprio err.
+func causes(err error) []error {
+	var out []error
+	var walk func(error, int)
+	walk = func(e error, depth int) {
+		if depth > 16 {
+			return
+		}
+		var next []error
+		switch u := e.(type) {
+		case interface{ Unwrap() []error }:
+			next = u.Unwrap()
+		default:
+			if n := errors.Unwrap(e); n != nil {
+				next = []error{n}
+			}
+		}
+		for _, n := range next {
+			if n == nil {
+				continue
+			}
+			out = append(out, n)
+			walk(n, depth+1)
+		}
+	}
+	walk(err, 0)
+	return out
+}
+
+// --- texto ------------------------------------------------------------------
+
+// wrap quebra s em linhas de até width runas, nos espaços quando dá.
+// width < 20 (ou 0) não quebra, só respeita as quebras que já existem.
+func wrap(s string, width int) []string {
+	var out []string
+	for _, para := range strings.Split(s, "\n") {
+		if width < 20 || utf8.RuneCountInString(para) <= width {
+			out = append(out, para)
+			continue
+		}
+		var line []rune
+		for _, word := range strings.Fields(para) {
+			w := []rune(word)
+			for len(w) > width { // palavra maior que a linha: corta
+				if len(line) > 0 {
+					out = append(out, string(line))
+					line = nil
+				}
+				out = append(out, string(w[:width]))
+				w = w[width:]
+			}
+			switch {
+			case len(line) == 0:
+				line = w
+			case len(line)+1+len(w) <= width:
+				line = append(append(line, ' '), w...)
+			default:
+				out = append(out, string(line))
+				line = w
+			}
+		}
+		out = append(out, string(line))
+	}
+	return out
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(r[:width])
+	}
+	return string(r[:width-1]) + "…"
+}
+
+func padRight(s string, n int) string {
+	if n <= 0 {
+		return s
+	}
+	return s + strings.Repeat(" ", n)
+}
+
+func padLeft(s string, width int) string {
+	if n := width - utf8.RuneCountInString(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
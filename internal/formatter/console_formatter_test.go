@@ -0,0 +1,116 @@
+package formatter_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+var consoleTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// consoleEntry monta uma entry sem caller, pra saída não depender do
+// caminho do checkout.
+func consoleEntry(lvl kbx.Level, msg string, at time.Time) *core.Entry {
+	e := core.NewLogzEntry(lvl).WithMessage(msg).(*core.Entry)
+	e.WithTimestamp(at)
+	e.Caller = ""
+	return e
+}
+
+func newConsole() *formatter.ConsoleFormatter {
+	return &formatter.ConsoleFormatter{
+		Time:         kbx.TimeFormat{Layout: "15:04:05", Location: time.UTC},
+		ContextWidth: 8,
+		Width:        -1,
+	}
+}
+
+// Colunas, árvore de fields e cadeia de erros, sem cor.
+func TestConsoleLayout(t *testing.T) {
+	e := consoleEntry(kbx.LevelError, "charge failed", consoleTime)
+	e.WithContext("payments-api").
+		WithFields(map[string]any{
+			"amount": 10,
+			"empty":  map[string]any{},
+			"user":   map[string]any{"id": "u1", "roles": []string{"a"}},
+		}).
+		WithError(fmt.Errorf("charge: %w", errors.Join(errors.New("card declined"), errors.New("retry later"))))
+
+	b, err := newConsole().Format(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"12:00:00 error    payment… charge failed",
+		"    ├─ amount: 10",
+		"    ├─ empty: (empty)",
+		"    └─ user",
+		`       ├─ id: "u1"`,
+		"       └─ roles",
+		`          └─ [0]: "a"`,
+		"    error: charge: card declined",
+		"           retry later (*fmt.wrapError)",
+		"      ↳ card declined",
+		"        retry later (*errors.joinError)",
+		"      ↳ card declined (*errors.errorString)",
+		"      ↳ retry later (*errors.errorString)",
+		"",
+	}, "\n")
+	if string(b) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b, want)
+	}
+}
+
+// Relative mostra o tempo desde a entry anterior; Width quebra a
+// mensagem alinhada à coluna dela.
+func TestConsoleRelativeAndWrap(t *testing.T) {
+	f := newConsole()
+	f.Relative = true
+	f.Width = 60
+
+	first, _ := f.Format(consoleEntry(kbx.LevelInfo, "start", consoleTime))
+	second, _ := f.Format(consoleEntry(kbx.LevelInfo, "one two three four five six seven eight", consoleTime.Add(1500*time.Millisecond)))
+	if want := "12:00:00          info              start\n"; string(first) != want {
+		t.Errorf("first: %q, want %q", first, want)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(second), "\n"), "\n")
+	if len(lines) < 2 || !strings.Contains(lines[0], "   +1.5s ") {
+		t.Fatalf("second: %q", second)
+	}
+	col := strings.Index(lines[0], "one")
+	for _, l := range lines[1:] {
+		if strings.TrimSpace(l[:col]) != "" || l[col] == ' ' {
+			t.Errorf("continuation not aligned at %d: %q", col, l)
+		}
+	}
+	for _, l := range lines {
+		if n := len([]rune(l)); n > 60 {
+			t.Errorf("line longer than Width (%d): %q", n, l)
+		}
+	}
+}
+
+// O tema dá o rótulo; com cor e TTY entram as sequências e o ícone.
+func TestConsoleThemeAndColor(t *testing.T) {
+	f := newConsole()
+	ascii, _ := formatter.LookupTheme("ascii")
+	formatter.ApplyTheme(f, ascii)
+	b, _ := f.Format(consoleEntry(kbx.LevelWarn, "careful", consoleTime))
+	if !strings.Contains(string(b), " WARN     ") || strings.Contains(string(b), "\x1b[") {
+		t.Errorf("ascii without color: %q", b)
+	}
+
+	formatter.ApplyTerm(f, kbx.TermInfo{TTY: true, Color: kbx.ColorBasic})
+	e := consoleEntry(kbx.LevelWarn, "careful", consoleTime)
+	e.WithColor(true).WithIcon(true)
+	b, _ = f.Format(e)
+	if !strings.Contains(string(b), "\x1b[") || !strings.Contains(string(b), ascii.Icon(kbx.LevelWarn)+" careful") {
+		t.Errorf("colored: %q", b)
+	}
+}
@@ -0,0 +1,195 @@
+package formatter
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// frame é uma posição no código: função, arquivo e linha.
+type frame struct {
+	fn   string
+	file string
+	line int
+}
+
+// splitStack separa dos fields a stack em texto (debug.Stack(), zap,
+// "stack"/"stacktrace"), que o ConsoleFormatter desenha à parte.
+func splitStack(fields map[string]any) (map[string]any, string) {
+	var stack string
+	for _, k := range stackFields {
+		if s, ok := fields[k].(string); ok && strings.Contains(s, "\n") {
+			stack = s
+			rest := make(map[string]any, len(fields)-1)
+			for fk, fv := range fields {
+				if fk != k {
+					rest[fk] = fv
+				}
+			}
+			return rest, stack
+		}
+	}
+	return fields, ""
+}
+
+// parseStack entende o formato do runtime (debug.Stack, panics) e o do
+// zap: uma linha com a função, seguida de "\tarquivo:linha [+0x..]".
+func parseStack(s string) []frame {
+	if s == "" {
+		return nil
+	}
+	var frames []frame
+	var fn string
+	for _, line := range strings.Split(s, "\n") {
+		if !strings.HasPrefix(line, "\t") {
+			fn = strings.TrimSpace(line)
+			continue
+		}
+		loc := strings.TrimSpace(line)
+		if i := strings.Index(loc, " +0x"); i > 0 {
+			loc = loc[:i]
+		}
+		fr := parseLocation(loc)
+		if fr.file == "" {
+			continue
+		}
+		fr.fn = frameFunc(fn)
+		frames = append(frames, fr)
+	}
+	return frames
+}
+
+// frameFunc limpa a linha de função de uma stack:
+// "main.(*T).Run(0xc000010000, ...)" -> "main.(*T).Run";
+// "created by main.start in goroutine 7" -> "created by main.start".
+func frameFunc(fn string) string {
+	if i := strings.Index(fn, " in goroutine "); i > 0 {
+		fn = fn[:i]
+	}
+	if strings.HasSuffix(fn, ")") {
+		if i := strings.LastIndex(fn, "("); i > 0 {
+			fn = fn[:i]
+		}
+	}
+	return fn
+}
+
+// parseCaller lê o formato do Entry.Caller: "arquivo:linha função".
+func parseCaller(c string) frame {
+	loc, fn, _ := strings.Cut(c, " ")
+	fr := parseLocation(loc)
+	fr.fn = fn
+	return fr
+}
+
+func parseLocation(loc string) frame {
+	i := strings.LastIndex(loc, ":")
+	if i <= 0 {
+		return frame{}
+	}
+	n, err := strconv.Atoi(loc[i+1:])
+	if err != nil {
+		return frame{}
+	}
+	return frame{file: loc[:i], line: n}
+}
+
+// userFrame diz se o frame é do código da aplicação (nem runtime, nem o
+// próprio logz), o que merece o trecho de código.
+func userFrame(fr frame) bool {
+	fn := strings.TrimPrefix(fr.fn, "created by ")
+	if strings.HasPrefix(fn, "runtime.") || strings.HasPrefix(fn, "runtime/debug.") ||
+		strings.HasPrefix(fn, "testing.") {
+		return false
+	}
+	return !strings.Contains(fn, "kubex-ecosystem/logz/internal/") &&
+		!strings.Contains(fr.file, "kubex-ecosystem/logz/internal/")
+}
+
+func (f *ConsoleFormatter) writeStack(b *strings.Builder, frames []frame, severe bool) {
+	b.WriteString(consoleIndent + f.paint(consoleDim, "stack:") + "\n")
+	snippet := severe && f.Snippets
+	for i, fr := range frames {
+		if i >= consoleMaxStack {
+			b.WriteString(consoleIndent + "  " + f.paint(consoleDim, "… "+strconv.Itoa(len(frames)-i)+" more") + "\n")
+			break
+		}
+		user := userFrame(fr)
+		fn := f.clean(fr.fn)
+		if user {
+			fn = f.paint(consoleKey, fn)
+		} else {
+			fn = f.paint(consoleDim, fn)
+		}
+		b.WriteString(consoleIndent + "  " + fn + "\n")
+		b.WriteString(consoleIndent + "    " + f.paint(consoleDim, f.clean(fr.file)+":"+strconv.Itoa(fr.line)) + "\n")
+		if snippet && user {
+			f.writeSnippet(b, fr)
+			snippet = false // só o primeiro frame da aplicação
+		}
+	}
+}
+
+// writeSnippet mostra as linhas em volta de fr, marcando a linha do frame.
+func (f *ConsoleFormatter) writeSnippet(b *strings.Builder, fr frame) {
+	n := f.SnippetLines
+	if n <= 0 {
+		n = 2
+	}
+	lines := sources.lines(fr.file)
+	if fr.line < 1 || fr.line > len(lines) {
+		return
+	}
+	from, to := max(fr.line-n, 1), min(fr.line+n, len(lines))
+	numW := len(strconv.Itoa(to))
+	for ln := from; ln <= to; ln++ {
+		num := padLeft(strconv.Itoa(ln), numW)
+		code := f.clean(strings.ReplaceAll(lines[ln-1], "\t", "    "))
+		if ln == fr.line {
+			b.WriteString(consoleIndent + "  " + f.paint(consoleError, "> "+num+" │ ") + code + "\n")
+			continue
+		}
+		b.WriteString(consoleIndent + "  " + f.paint(consoleDim, "  "+num+" │ ") + code + "\n")
+	}
+}
+
+// sourceCache guarda as linhas dos arquivos-fonte já lidos. É pequeno e
+// recomeça do zero quando enche: só serve o console de desenvolvimento.
+type sourceCache struct {
+	mu    sync.Mutex
+	files map[string][]string
+}
+
+const (
+	sourceCacheFiles = 64
+	sourceMaxBytes   = 1 << 20
+)
+
+var sources = &sourceCache{files: map[string][]string{}}
+
+func (c *sourceCache) lines(path string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if l, ok := c.files[path]; ok {
+		return l
+	}
+	if len(c.files) >= sourceCacheFiles {
+		c.files = map[string][]string{}
+	}
+	var out []string
+	if st, err := os.Stat(path); err == nil && st.Mode().IsRegular() && st.Size() <= sourceMaxBytes {
+		if fh, err := os.Open(path); err == nil {
+			sc := bufio.NewScanner(fh)
+			sc.Buffer(make([]byte, 0, 64*1024), sourceMaxBytes)
+			for sc.Scan() {
+				out = append(out, sc.Text())
+			}
+			_ = fh.Close()
+		}
+	}
+	// guarda mesmo vazio: não tenta de novo um arquivo que não existe.
+	c.files[path] = out
+	return out
+}
@@ -9,6 +9,7 @@ import (
 
 type CSVFormatter struct {
 	Pretty bool
+	Time   kbx.TimeFormat
 }
 
 func NewCSVFormatter(pretty bool) Formatter {
@@ -28,9 +29,11 @@ func (f *CSVFormatter) Format(e kbx.Entry) ([]byte, error) {
 	if err := e.Validate(); err != nil {
 		return nil, err
 	}
+	e = resolved(e)
+	spanID, flags := spanOf(e)
 	table := csvOutput{
-		Headers: []string{"ID", "Message", "Timestamp", "LogLevel", "AdditionalField"},
-		Entries: [][]string{{e.GetTraceID(), e.GetMessage(), e.GetTimestamp().Format("2006-01-02T15:04:05Z07:00"), string(e.GetLevel()), fieldsToString(e.GetFields())}},
+		Headers: []string{"ID", "Message", "Timestamp", "LogLevel", "AdditionalField", "SpanID", "TraceFlags"},
+		Entries: [][]string{{e.GetTraceID(), e.GetMessage(), f.Time.Or(csvTimeFormat).Format(e.GetTimestamp()), string(e.GetLevel()), fieldsToString(e.GetFields()), spanID, flags}},
 	}
 
 	if f.Pretty {
@@ -39,6 +42,17 @@ func (f *CSVFormatter) Format(e kbx.Entry) ([]byte, error) {
 	return marshalCSV(table)
 }
 
+// Clone implementa Cloner.
+func (f *CSVFormatter) Clone() Formatter {
+	c := *f
+	return &c
+}
+
+// SetTimeFormat implementa TimeFormatter.
+func (f *CSVFormatter) SetTimeFormat(tf kbx.TimeFormat) {
+	f.Time = tf
+}
+
 func marshalCSVPretty(data csvOutput) ([]byte, error) {
 	var b strings.Builder
 	b.WriteString("CSV Output:\n")
@@ -62,9 +76,9 @@ func marshalCSVPretty(data csvOutput) ([]byte, error) {
 
 func marshalCSV(data csvOutput) ([]byte, error) {
 	var b strings.Builder
-	b.WriteString("ID,Message\n")
+	b.WriteString("ID,SpanID,Message\n")
 	for _, entry := range data.Entries {
-		b.WriteString(entry[0] + "," + entry[1] + "\n")
+		b.WriteString(entry[0] + "," + entry[5] + "," + entry[1] + "\n")
 	}
 	return []byte(b.String()), nil
 }
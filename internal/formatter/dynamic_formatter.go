@@ -0,0 +1,293 @@
+package formatter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// ErrDropped é devolvido por Format quando um filtro descarta a entry; o
+// logger não escreve nada.
+var ErrDropped = errors.New("formatter: entry dropped by filter")
+
+// DynamicFormatter escolhe, entry por entry, qual formatter usar:
+//
+//  1. os enrichers rodam numa cópia da entry;
+//  2. qualquer filtro que recuse a entry a descarta (ErrDropped);
+//  3. o primeiro predicado (When) que aceitar a entry decide;
+//  4. senão vale o maior limiar de nível (WhenLevel) que a entry atinge;
+//  5. senão, o Default.
+//
+// Sem configuração: error e acima em JSON, info até warn em texto e
+// debug/trace no minimal.
+type DynamicFormatter struct {
+	mu        sync.RWMutex
+	preds     []dynamicRule
+	levels    []dynamicRule // ordenados do limiar mais alto pro mais baixo
+	fallback  Formatter
+	enrichers []func(kbx.Entry)
+	filters   []func(kbx.Entry) bool
+}
+
+type dynamicRule struct {
+	match func(kbx.Entry) bool
+	min   kbx.Level
+	f     Formatter
+}
+
+// NewDynamicFormatter cria o DynamicFormatter com as regras padrão.
+func NewDynamicFormatter(pretty bool) Formatter {
+	d := &DynamicFormatter{}
+	d.SetLevelFormats(map[string]string{
+		string(kbx.LevelError): "json",
+		string(kbx.LevelInfo):  "text",
+		"default":              "minimal",
+	}, pretty)
+	return d
+}
+
+func (f *DynamicFormatter) Name() string {
+	return "dynamic"
+}
+
+// When acrescenta uma regra por predicado; predicados são avaliados na
+// ordem em que foram acrescentados e antes dos limiares de nível.
+func (f *DynamicFormatter) When(match func(kbx.Entry) bool, to Formatter) *DynamicFormatter {
+	if match == nil || to == nil {
+		return f
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.preds = append(f.preds, dynamicRule{match: match, f: to})
+	return f
+}
+
+// WhenLevel usa to pras entries com severidade >= min (substituindo o
+// limiar igual, se já houver).
+func (f *DynamicFormatter) WhenLevel(min kbx.Level, to Formatter) *DynamicFormatter {
+	if to == nil {
+		return f
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLevel(min, to)
+	return f
+}
+
+func (f *DynamicFormatter) setLevel(min kbx.Level, to Formatter) {
+	for i, r := range f.levels {
+		if r.min.Severity() == min.Severity() {
+			f.levels[i].f = to
+			return
+		}
+	}
+	f.levels = append(f.levels, dynamicRule{min: min, f: to})
+	sort.SliceStable(f.levels, func(i, j int) bool {
+		return f.levels[i].min.Severity() > f.levels[j].min.Severity()
+	})
+}
+
+// Default troca o formatter usado quando nenhuma regra casa.
+func (f *DynamicFormatter) Default(to Formatter) *DynamicFormatter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fallback = to
+	return f
+}
+
+// Enrich acrescenta um enricher: recebe a cópia da entry que vai ser
+// formatada e pode alterá-la (ex: via kbx.LogzEntry.WithField).
+func (f *DynamicFormatter) Enrich(fn func(kbx.Entry)) *DynamicFormatter {
+	if fn == nil {
+		return f
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.enrichers = append(f.enrichers, fn)
+	return f
+}
+
+// Filter acrescenta um filtro: a entry só é escrita se todos aceitarem.
+func (f *DynamicFormatter) Filter(fn func(kbx.Entry) bool) *DynamicFormatter {
+	if fn == nil {
+		return f
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.filters = append(f.filters, fn)
+	return f
+}
+
+// SetLevelFormats acrescenta os limiares de rules: nível -> nome do
+// formatter (ver ParseFormatter); a chave "default" troca o Default. Os
+// limiares já configurados (WhenLevel) continuam valendo, exceto os de
+// mesma severidade, que rules substitui. Nomes de nível ou de formatter
+// inválidos são erro e não mudam nada.
+func (f *DynamicFormatter) SetLevelFormats(rules map[string]string, pretty bool) error {
+	var levels []dynamicRule
+	var fallback Formatter
+	keys := make([]string, 0, len(rules))
+	for k := range rules {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		name := strings.ToLower(strings.TrimSpace(rules[k]))
+		if name == "" || name == "dynamic" || !Known(name) {
+			return fmt.Errorf("logz: dynamic formatter: invalid format %q for %q", rules[k], k)
+		}
+		to := ParseFormatter(name, pretty)
+		if strings.EqualFold(k, "default") {
+			fallback = to
+			continue
+		}
+		lvl := kbx.Level(strings.ToLower(strings.TrimSpace(k)))
+		if !kbx.IsLevel(string(lvl)) {
+			return fmt.Errorf("logz: dynamic formatter: unknown level %q", k)
+		}
+		levels = append(levels, dynamicRule{min: lvl, f: to})
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range levels {
+		f.setLevel(r.min, r.f)
+	}
+	if fallback != nil {
+		f.fallback = fallback
+	}
+	return nil
+}
+
+// ParseDynamicRules lê regras no formato "error=json,info=text,default=minimal"
+// (o de LOGZ_DYNAMIC_FORMAT).
+func ParseDynamicRules(s string) map[string]string {
+	rules := map[string]string{}
+	for _, part := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if ok && strings.TrimSpace(k) != "" {
+			rules[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return rules
+}
+
+// ApplyDynamicRules configura os limiares de um DynamicFormatter; outros
+// formatters e rules vazias são ignorados.
+func ApplyDynamicRules(f Formatter, rules map[string]string, pretty bool) (Formatter, error) {
+	d, ok := f.(*DynamicFormatter)
+	if !ok || len(rules) == 0 {
+		return f, nil
+	}
+	return f, d.SetLevelFormats(rules, pretty)
+}
+
+func (f *DynamicFormatter) Format(e kbx.Entry) ([]byte, error) {
+	f.mu.RLock()
+	enrichers, filters := f.enrichers, f.filters
+	f.mu.RUnlock()
+
+	if len(enrichers) > 0 {
+		if c := e.Clone(); c != nil {
+			e = c
+		}
+		for _, enrich := range enrichers {
+			enrich(e)
+		}
+	}
+	for _, keep := range filters {
+		if !keep(e) {
+			return nil, ErrDropped
+		}
+	}
+	return f.pick(e).Format(e)
+}
+
+// pick escolhe o formatter da entry.
+func (f *DynamicFormatter) pick(e kbx.Entry) Formatter {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, r := range f.preds {
+		if r.match(e) {
+			return r.f
+		}
+	}
+	sev := e.GetLevel().Severity()
+	for _, r := range f.levels {
+		if sev >= r.min.Severity() {
+			return r.f
+		}
+	}
+	if f.fallback != nil {
+		return f.fallback
+	}
+	return defaultDynamicFallback
+}
+
+var defaultDynamicFallback = NewTextFormatter(false)
+
+// each aplica fn em todos os formatters das regras.
+func (f *DynamicFormatter) each(fn func(Formatter)) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, r := range f.preds {
+		fn(r.f)
+	}
+	for _, r := range f.levels {
+		fn(r.f)
+	}
+	if f.fallback != nil {
+		fn(f.fallback)
+	}
+}
+
+// Clone implementa Cloner: as regras são copiadas e os formatters delas
+// clonados, pra que os Set* na cópia não alcancem os da original.
+func (f *DynamicFormatter) Clone() Formatter {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	c := &DynamicFormatter{
+		enrichers: slices.Clone(f.enrichers),
+		filters:   slices.Clone(f.filters),
+	}
+	if f.fallback != nil {
+		c.fallback = Clone(f.fallback)
+	}
+	for _, r := range f.preds {
+		r.f = Clone(r.f)
+		c.preds = append(c.preds, r)
+	}
+	for _, r := range f.levels {
+		r.f = Clone(r.f)
+		c.levels = append(c.levels, r)
+	}
+	return c
+}
+
+// SetSanitize implementa Sanitizer repassando aos formatters das regras.
+func (f *DynamicFormatter) SetSanitize(enabled bool) {
+	f.each(func(c Formatter) {
+		if s, ok := c.(Sanitizer); ok {
+			s.SetSanitize(enabled)
+		}
+	})
+}
+
+// SetTerm implementa TermFormatter repassando aos formatters das regras.
+func (f *DynamicFormatter) SetTerm(t kbx.TermInfo) {
+	f.each(func(c Formatter) { ApplyTerm(c, t) })
+}
+
+// SetTheme implementa ThemedFormatter repassando aos formatters das regras.
+func (f *DynamicFormatter) SetTheme(t Theme) {
+	f.each(func(c Formatter) { ApplyTheme(c, t) })
+}
+
+// SetTimeFormat implementa TimeFormatter repassando aos formatters das regras.
+func (f *DynamicFormatter) SetTimeFormat(tf kbx.TimeFormat) {
+	f.each(func(c Formatter) { ApplyTimeFormat(c, tf) })
+}
@@ -0,0 +1,134 @@
+package formatter_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// named devolve um formatter que escreve só o próprio nome, pra saber qual
+// regra foi escolhida.
+func named(name string) formatter.Formatter {
+	return formatter.FormatterFunc(func(kbx.Entry) ([]byte, error) {
+		return []byte(name), nil
+	})
+}
+
+func dynEntry(lvl kbx.Level) kbx.Entry {
+	return core.NewLogzEntry(lvl).WithMessage("m")
+}
+
+func pick(t *testing.T, f formatter.Formatter, e kbx.Entry) string {
+	t.Helper()
+	b, err := f.Format(e)
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	return string(b)
+}
+
+// Predicados vêm antes dos limiares, e vale o maior limiar atingido.
+func TestDynamicRules(t *testing.T) {
+	d := &formatter.DynamicFormatter{}
+	d.WhenLevel(kbx.LevelWarn, named("warn")).
+		WhenLevel(kbx.LevelError, named("error")).
+		Default(named("default")).
+		When(func(e kbx.Entry) bool { return e.GetMessage() == "special" }, named("pred"))
+
+	for _, tc := range []struct {
+		lvl  kbx.Level
+		want string
+	}{
+		{kbx.LevelDebug, "default"},
+		{kbx.LevelInfo, "default"},
+		{kbx.LevelWarn, "warn"},
+		{kbx.LevelError, "error"},
+		{kbx.LevelFatal, "error"},
+	} {
+		if got := pick(t, d, dynEntry(tc.lvl)); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.lvl, got, tc.want)
+		}
+	}
+	if got := pick(t, d, core.NewLogzEntry(kbx.LevelDebug).WithMessage("special")); got != "pred" {
+		t.Errorf("predicate: got %q", got)
+	}
+}
+
+// Enrichers alteram uma cópia; filtros descartam com ErrDropped.
+func TestDynamicEnrichAndFilter(t *testing.T) {
+	d := &formatter.DynamicFormatter{}
+	d.Default(formatter.NewJSONFormatter(false)).
+		Enrich(func(e kbx.Entry) {
+			if le, ok := e.(interface {
+				WithField(string, any) kbx.LogzEntry
+			}); ok {
+				le.WithField("enriched", true)
+			}
+		}).
+		Filter(func(e kbx.Entry) bool { return e.GetMessage() != "drop" })
+
+	e := dynEntry(kbx.LevelInfo)
+	out := pick(t, d, e)
+	if !strings.Contains(out, `"enriched":true`) {
+		t.Errorf("enricher not applied: %s", out)
+	}
+	if _, ok := e.GetFields()["enriched"]; ok {
+		t.Error("enricher changed the original entry")
+	}
+
+	b, err := d.Format(core.NewLogzEntry(kbx.LevelError).WithMessage("drop"))
+	if !errors.Is(err, formatter.ErrDropped) || b != nil {
+		t.Errorf("filtered entry: %q, %v", b, err)
+	}
+}
+
+// As regras da config se somam às de WhenLevel; a de mesmo nível
+// substitui.
+func TestDynamicSetLevelFormatsKeepsRules(t *testing.T) {
+	d := &formatter.DynamicFormatter{}
+	d.WhenLevel(kbx.LevelWarn, named("warn")).
+		WhenLevel(kbx.LevelError, named("error"))
+
+	if err := d.SetLevelFormats(map[string]string{"error": "json", "default": "minimal"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := pick(t, d, dynEntry(kbx.LevelWarn)); got != "warn" {
+		t.Errorf("WhenLevel rule lost: got %q", got)
+	}
+	if got := pick(t, d, dynEntry(kbx.LevelError)); !strings.HasPrefix(got, "{") {
+		t.Errorf("error should be JSON now: %q", got)
+	}
+	if got := pick(t, d, dynEntry(kbx.LevelDebug)); got == "" || strings.HasPrefix(got, "{") {
+		t.Errorf("default should be minimal: %q", got)
+	}
+
+	for _, bad := range []map[string]string{
+		{"error": "nope"},
+		{"error": "dynamic"},
+		{"loud": "json"},
+	} {
+		if err := d.SetLevelFormats(bad, false); err == nil {
+			t.Errorf("%v: want error", bad)
+		}
+	}
+	if got := pick(t, d, dynEntry(kbx.LevelWarn)); got != "warn" {
+		t.Errorf("invalid rules changed the formatter: %q", got)
+	}
+}
+
+func TestParseDynamicRules(t *testing.T) {
+	got := formatter.ParseDynamicRules(" error = json, info=text ,default=minimal,junk,=x")
+	want := map[string]string{"error": "json", "info": "text", "default": "minimal"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v", got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %q, want %q", k, got[k], v)
+		}
+	}
+}
@@ -23,19 +23,79 @@ func (f FormatterFunc) Format(e kbx.Entry) ([]byte, error) {
 	return f(e)
 }
 
+// Cloner é implementado pelos formatters com configuração mutável (Set*):
+// Clone devolve uma cópia independente, que pode ser ajustada enquanto a
+// original segue formatando em outras goroutines.
+type Cloner interface {
+	Clone() Formatter
+}
+
+// Clone copia f quando ele implementa Cloner; senão devolve o próprio f.
+func Clone(f Formatter) Formatter {
+	if c, ok := f.(Cloner); ok {
+		return c.Clone()
+	}
+	return f
+}
+
+// resolved devolve e com os valores preguiçosos dos fields resolvidos
+// (kbx.ResolveFields). Sem nada a resolver, o caso comum depois do logger,
+// é o próprio e; senão, uma cópia: a entry e os mapas de quem logou não
+// são alterados.
+func resolved(e kbx.Entry) kbx.Entry {
+	fields := e.GetFields()
+	if !kbx.HasLazy(fields) {
+		return e
+	}
+	c := e.Clone()
+	if c == nil {
+		return e
+	}
+	// a cópia tem os próprios Fields (só o primeiro nível é copiado pelo
+	// Clone; os aninhados vêm novos do ResolveFields).
+	cf := c.GetFields()
+	for k, v := range kbx.ResolveFields(fields) {
+		cf[k] = v
+	}
+	return c
+}
+
+// ParseFormatter devolve o formatter registrado com esse nome, caindo no
+// text quando o nome é vazio ou desconhecido. Pra nomes vindos de config,
+// env ou flag use New, que devolve erro.
 func ParseFormatter(format string, pretty bool) Formatter {
-	switch format {
-	case "json":
-		return NewJSONFormatter(pretty)
-	case "text":
-		return NewTextFormatter(pretty)
-	case "yaml":
-		return NewYamlFormatter(pretty)
-	case "csv":
-		return NewCSVFormatter(pretty)
-	case "xml":
-		return NewXMLFormatter(pretty)
-	default:
-		return NewTextFormatter(pretty)
+	if f, err := New(format, pretty); err == nil {
+		return f
+	}
+	return NewTextFormatter(pretty)
+}
+
+// spanOf devolve span_id e trace_flags das entries que os carregam
+// (core.Entry); as demais só têm o trace_id.
+func spanOf(e kbx.Entry) (spanID, flags string) {
+	if se, ok := e.(interface {
+		GetSpanID() string
+		GetTraceFlags() string
+	}); ok {
+		return se.GetSpanID(), se.GetTraceFlags()
+	}
+	return "", ""
+}
+
+// tracePairs monta "trace_id=... span_id=... trace_flags=..." com o que
+// estiver presente. Vazio quando a entry não tem trace.
+func tracePairs(e kbx.Entry) string {
+	traceID := e.GetTraceID()
+	if traceID == "" {
+		return ""
+	}
+	spanID, flags := spanOf(e)
+	s := "trace_id=" + traceID
+	if spanID != "" {
+		s += " span_id=" + spanID
+	}
+	if flags != "" {
+		s += " trace_flags=" + flags
 	}
+	return s
 }
@@ -8,6 +8,9 @@ import (
 
 type JSONFormatter struct {
 	Pretty bool
+	// Time escreve o "ts" (RFC3339Nano por padrão) no formato pedido; vale
+	// pras entries que implementam kbx.TimedEntry.
+	Time kbx.TimeFormat
 }
 
 func NewJSONFormatter(pretty bool) Formatter {
@@ -22,8 +25,21 @@ func (f *JSONFormatter) Format(e kbx.Entry) ([]byte, error) {
 	if err := e.Validate(); err != nil {
 		return nil, err
 	}
+	e = resolved(e)
+	v := timed(e, f.Time)
 	if f.Pretty {
-		return json.MarshalIndent(e, "", "  ")
+		return json.MarshalIndent(v, "", "  ")
 	}
-	return json.Marshal(e)
+	return json.Marshal(v)
+}
+
+// Clone implementa Cloner.
+func (f *JSONFormatter) Clone() Formatter {
+	c := *f
+	return &c
+}
+
+// SetTimeFormat implementa TimeFormatter.
+func (f *JSONFormatter) SetTimeFormat(tf kbx.TimeFormat) {
+	f.Time = tf
 }
@@ -0,0 +1,130 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// LogfmtFormatter: ts=... level=info msg="..." k=v\n
+//
+// Uma entry vira SEMPRE uma linha: quebras de linha e caracteres de controle
+// nos valores são escapados. Com Sanitize ligado, sequências ANSI também são
+// removidas.
+type LogfmtFormatter struct {
+	Sanitize bool
+	Time     kbx.TimeFormat
+}
+
+func NewLogfmtFormatter(pretty bool) Formatter {
+	return &LogfmtFormatter{}
+}
+
+func (f *LogfmtFormatter) Name() string {
+	return "logfmt"
+}
+
+// Clone implementa Cloner.
+func (f *LogfmtFormatter) Clone() Formatter {
+	c := *f
+	return &c
+}
+
+// SetSanitize implementa Sanitizer.
+func (f *LogfmtFormatter) SetSanitize(enabled bool) {
+	f.Sanitize = enabled
+}
+
+// SetTimeFormat implementa TimeFormatter.
+func (f *LogfmtFormatter) SetTimeFormat(tf kbx.TimeFormat) {
+	f.Time = tf
+}
+
+func (f *LogfmtFormatter) Format(e kbx.Entry) ([]byte, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	e = resolved(e)
+
+	var b strings.Builder
+	f.writePair(&b, "ts", f.Time.Or(logfmtTimeFormat).Format(e.GetTimestamp()))
+	f.writePair(&b, "level", string(e.GetLevel()))
+	f.writePair(&b, "msg", e.GetMessage())
+	if c := e.GetContext(); c != "" {
+		f.writePair(&b, "ctx", c)
+	}
+	if t := e.GetTraceID(); t != "" {
+		f.writePair(&b, "trace_id", t)
+		spanID, flags := spanOf(e)
+		if spanID != "" {
+			f.writePair(&b, "span_id", spanID)
+		}
+		if flags != "" {
+			f.writePair(&b, "trace_flags", flags)
+		}
+	}
+	if e.GetShowCaller() && e.GetCaller() != "" {
+		f.writePair(&b, "caller", e.GetCaller())
+	}
+
+	tags := e.GetTags()
+	tkeys := make([]string, 0, len(tags))
+	for k := range tags {
+		tkeys = append(tkeys, k)
+	}
+	sort.Strings(tkeys)
+	for _, k := range tkeys {
+		f.writePair(&b, "tag."+k, tags[k])
+	}
+
+	fields := e.GetFields()
+	fkeys := make([]string, 0, len(fields))
+	for k := range fields {
+		fkeys = append(fkeys, k)
+	}
+	sort.Strings(fkeys)
+	for _, k := range fkeys {
+		f.writePair(&b, k, fmt.Sprintf("%v", fields[k]))
+	}
+
+	if ee, ok := e.(interface{ GetError() error }); ok && ee.GetError() != nil {
+		f.writePair(&b, "error", ee.GetError().Error())
+	}
+
+	b.WriteByte('\n')
+	return []byte(b.String()), nil
+}
+
+func (f *LogfmtFormatter) writePair(b *strings.Builder, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(logfmtKey(key))
+	b.WriteByte('=')
+	if f.Sanitize {
+		// o Quote abaixo já escapa quebras de linha e controles.
+		value = StripANSI(value)
+	}
+	if isPrintableASCIIWord(value) {
+		b.WriteString(value)
+		return
+	}
+	// strconv.Quote escapa \n, controles e aspas: a linha nunca quebra.
+	b.WriteString(strconv.Quote(value))
+}
+
+func logfmtKey(k string) string {
+	k = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, k)
+	if k == "" {
+		return "_"
+	}
+	return k
+}
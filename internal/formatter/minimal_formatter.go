@@ -9,7 +9,8 @@ import (
 // MinimalFormatter: LEVEL message\n
 
 type MinimalFormatter struct {
-	pretty bool
+	pretty   bool
+	sanitize bool
 }
 
 func NewMinimalFormatter(pretty bool) Formatter {
@@ -20,10 +21,30 @@ func (f *MinimalFormatter) Name() string {
 	return "minimal"
 }
 
+// Clone implementa Cloner.
+func (f *MinimalFormatter) Clone() Formatter {
+	c := *f
+	return &c
+}
+
+// SetSanitize implementa Sanitizer.
+func (f *MinimalFormatter) SetSanitize(enabled bool) {
+	f.sanitize = enabled
+}
+
 func (f *MinimalFormatter) Format(e kbx.Entry) ([]byte, error) {
 	if err := e.Validate(); err != nil {
 		return nil, err
 	}
-	line := fmt.Sprintf("%s %s\n", e.GetLevel(), e.GetMessage())
+	e = resolved(e)
+	msg := e.GetMessage()
+	if f.sanitize {
+		msg = Sanitize(msg)
+	}
+	line := fmt.Sprintf("%s %s", e.GetLevel(), msg)
+	if t := tracePairs(e); t != "" {
+		line += " " + t
+	}
+	line += "\n"
 	return []byte(line), nil
 }
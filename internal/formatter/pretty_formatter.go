@@ -4,6 +4,7 @@ package formatter
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"sort"
 	"time"
 
@@ -11,14 +12,23 @@ import (
 )
 
 type PrettyFormatter struct {
+	// TimeLayout e o fuso local são o padrão; Time, quando configurado,
+	// tem prioridade.
 	TimeLayout string
 	WithColors bool
+	Sanitize   bool
+	Time       kbx.TimeFormat
+	// Term diz quantas cores o destino aceita (ColorNone = sem cor).
+	Term kbx.TermInfo
+	// Theme dá cor e rótulo de cada nível; zero = tema "default".
+	Theme Theme
 }
 
 func NewPrettyFormatter(pretty bool) Formatter {
 	return &PrettyFormatter{
 		TimeLayout: "15:04:05.000",
 		WithColors: pretty,
+		Term:       kbx.DetectTerm(os.Stdout, nil),
 	}
 }
 
@@ -26,28 +36,68 @@ func (f *PrettyFormatter) Name() string {
 	return "pretty"
 }
 
+// Clone implementa Cloner.
+func (f *PrettyFormatter) Clone() Formatter {
+	c := *f
+	return &c
+}
+
+// SetSanitize implementa Sanitizer.
+func (f *PrettyFormatter) SetSanitize(enabled bool) {
+	f.Sanitize = enabled
+}
+
+func (f *PrettyFormatter) clean(s string) string {
+	if f.Sanitize {
+		return Sanitize(s)
+	}
+	return s
+}
+
+// SetTerm implementa TermFormatter.
+func (f *PrettyFormatter) SetTerm(t kbx.TermInfo) {
+	f.Term = t
+}
+
+// SetTheme implementa ThemedFormatter.
+func (f *PrettyFormatter) SetTheme(t Theme) {
+	f.Theme = t
+}
+
+// SetTimeFormat implementa TimeFormatter.
+func (f *PrettyFormatter) SetTimeFormat(tf kbx.TimeFormat) {
+	f.Time = tf
+}
+
 func (f *PrettyFormatter) Format(e kbx.Entry) ([]byte, error) {
 	if err := e.Validate(); err != nil {
 		return nil, err
 	}
+	e = resolved(e)
 
 	var buf bytes.Buffer
 
-	ts := e.GetTimestamp().In(time.Local).Format(f.TimeLayout)
-	level := string(e.GetLevel())
-	msg := e.GetMessage()
+	ts := f.Time.Or(kbx.TimeFormat{Layout: f.TimeLayout, Location: time.Local}).Format(e.GetTimestamp())
+	msg := f.clean(e.GetMessage())
 
-	levelStr := level
-	if f.WithColors || e.GetShowColor() {
-		levelStr = colorForLevel(e.GetLevel(), level)
+	theme := f.Theme.orDefault()
+	label := theme.Label(e.GetLevel())
+	levelStr := label
+	if f.WithColors && e.GetShowColor() {
+		levelStr = theme.Paint(f.Term.Color, e.GetLevel(), label)
 	}
+	levelStr += theme.Pad(label)
 
 	fmt.Fprintf(&buf, "%s  %s  %s", ts, levelStr, msg)
 	if e.GetContext() != "" {
-		fmt.Fprintf(&buf, "  (%s)", e.GetContext())
+		fmt.Fprintf(&buf, "  (%s)", f.clean(e.GetContext()))
 	}
 	buf.WriteByte('\n')
 
+	if t := tracePairs(e); t != "" {
+		fmt.Fprintf(&buf, "  trace: %s\n", f.clean(t))
+	}
+
 	// tags e fields em linhas subsequentes
 	if e.GetShowStack() || e.GetShowCaller() || e.GetShowFields() {
 		if len(e.GetTags()) > 0 || len(e.GetFields()) > 0 || e.GetCaller() != "" {
@@ -63,7 +113,7 @@ func (f *PrettyFormatter) Format(e kbx.Entry) ([]byte, error) {
 					if i > 0 {
 						buf.WriteString(" ")
 					}
-					fmt.Fprintf(&buf, "%s=%s", k, e.GetTags()[k])
+					fmt.Fprintf(&buf, "%s=%s", f.clean(k), f.clean(e.GetTags()[k]))
 				}
 				buf.WriteByte('\n')
 			}
@@ -80,58 +130,16 @@ func (f *PrettyFormatter) Format(e kbx.Entry) ([]byte, error) {
 					if i > 0 {
 						buf.WriteString(" ")
 					}
-					fmt.Fprintf(&buf, "%s=%v", k, e.GetFields()[k])
+					fmt.Fprintf(&buf, "%s=%s", f.clean(k), f.clean(fmt.Sprintf("%v", e.GetFields()[k])))
 				}
 				buf.WriteByte('\n')
 			}
 
 			if e.GetCaller() != "" {
-				fmt.Fprintf(&buf, "  caller: %s\n", e.GetCaller())
+				fmt.Fprintf(&buf, "  caller: %s\n", f.clean(e.GetCaller()))
 			}
 		}
 	}
 
 	return buf.Bytes(), nil
 }
-
-func colorForLevel(l kbx.Level, s string) string {
-	const (
-		reset   = "\x1b[0m"
-		gray    = "\x1b[90m"
-		green   = "\x1b[32m"
-		yellow  = "\x1b[33m"
-		red     = "\x1b[31m"
-		magenta = "\x1b[35m"
-		cyan    = "\x1b[36m"
-		white   = "\x1b[37m"
-		blue    = "\x1b[34m"
-		purple  = "\x1b[35m"
-		redBG   = "\x1b[41m"
-	)
-	switch l {
-	case kbx.LevelAlert:
-		return redBG + blue + s + reset
-	case kbx.LevelAnswer:
-		return blue + s + reset
-	case kbx.LevelBug:
-		return red + s + reset
-	case kbx.LevelDebug:
-		return gray + s + reset
-	case kbx.LevelInfo:
-		return green + s + reset
-	case kbx.LevelWarn:
-		return yellow + s + reset
-	case kbx.LevelError:
-		return red + s + reset
-	case kbx.LevelFatal:
-		return magenta + s + reset
-	case kbx.LevelPanic:
-		return purple + s + reset
-	case kbx.LevelTrace:
-		return cyan + s + reset
-	case kbx.LevelNotice:
-		return white + s + reset
-	default:
-		return s
-	}
-}
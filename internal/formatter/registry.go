@@ -0,0 +1,107 @@
+package formatter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// Factory constrói um formatter; pretty liga cores/indentação nos
+// formatters que têm essa opção.
+type Factory func(pretty bool) Formatter
+
+// ErrUnknownFormat é devolvido (embrulhado) por New quando nenhum
+// formatter foi registrado com o nome pedido.
+var ErrUnknownFormat = errors.New("logz: unknown format")
+
+var (
+	formatsMu sync.RWMutex
+	// formats mapeia os nomes aceitos em "format" (config, LOGZ_LOG_FORMAT,
+	// --format) pras factories. Preenchido no init: o DynamicFormatter
+	// resolve os filhos por nome.
+	formats map[string]Factory
+)
+
+func init() {
+	formats = map[string]Factory{
+		"json":        NewJSONFormatter,
+		"text":        NewTextFormatter,
+		"yaml":        NewYamlFormatter,
+		"csv":         NewCSVFormatter,
+		"xml":         NewXMLFormatter,
+		"logfmt":      NewLogfmtFormatter,
+		"pretty":      NewPrettyFormatter,
+		"console":     NewConsoleFormatter,
+		"dev":         NewConsoleFormatter,
+		"minimal":     NewMinimalFormatter,
+		"msgpack":     NewMsgpackFormatter,
+		"messagepack": NewMsgpackFormatter,
+		"cbor":        NewCBORFormatter,
+		"protobuf":    NewProtobufFormatter,
+		"proto":       NewProtobufFormatter,
+		"dynamic":     NewDynamicFormatter,
+	}
+}
+
+// Register registra (ou substitui) a factory de um formato. O nome não
+// diferencia maiúsculas e passa a valer na config, em LOGZ_LOG_FORMAT, no
+// --format da CLI e nas regras do DynamicFormatter.
+func Register(name string, factory Factory) error {
+	name = normFormat(name)
+	if name == "" {
+		return fmt.Errorf("logz: formatter without name")
+	}
+	if factory == nil {
+		return fmt.Errorf("logz: formatter %q has no factory", name)
+	}
+	formatsMu.Lock()
+	formats[name] = factory
+	formatsMu.Unlock()
+	return nil
+}
+
+// New cria o formatter registrado com esse nome; nome vazio é o formato
+// padrão (kbx.DefaultLogFormat). Nomes desconhecidos são erro
+// (ErrUnknownFormat), nunca o text em silêncio.
+func New(name string, pretty bool) (Formatter, error) {
+	name = kbx.GetValueOrDefaultSimple(normFormat(name), kbx.DefaultLogFormat)
+	formatsMu.RLock()
+	factory, ok := formats[name]
+	formatsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownFormat, name, strings.Join(Names(), ", "))
+	}
+	f := factory(pretty)
+	if f == nil {
+		return nil, fmt.Errorf("logz: formatter %q: factory returned nil", name)
+	}
+	return f, nil
+}
+
+// Known diz se há formatter registrado com esse nome.
+func Known(name string) bool {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	_, ok := formats[normFormat(name)]
+	return ok
+}
+
+// Names lista os formatos registrados, em ordem alfabética.
+func Names() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	names := make([]string, 0, len(formats))
+	for n := range formats {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func normFormat(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
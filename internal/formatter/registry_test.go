@@ -0,0 +1,104 @@
+package formatter_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// Todo nome listado constrói um formatter; nome vazio é o padrão.
+func TestRegistryBuiltins(t *testing.T) {
+	names := formatter.Names()
+	if len(names) == 0 {
+		t.Fatal("no formats registered")
+	}
+	for i, name := range names {
+		if i > 0 && names[i-1] >= name {
+			t.Errorf("Names not sorted: %v", names)
+		}
+		if !formatter.Known(name) {
+			t.Errorf("%s: not Known", name)
+		}
+		if f, err := formatter.New(name, false); err != nil || f == nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	for _, alias := range []string{" JSON ", "Dev", "MessagePack", "proto"} {
+		if !formatter.Known(alias) {
+			t.Errorf("%q: not Known", alias)
+		}
+	}
+
+	f, err := formatter.New("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, _ := formatter.New(kbx.DefaultLogFormat, false)
+	if f.Name() != def.Name() {
+		t.Errorf("empty name: got %s, want %s", f.Name(), def.Name())
+	}
+}
+
+// Nome desconhecido é erro em New e text em ParseFormatter.
+func TestRegistryUnknown(t *testing.T) {
+	if formatter.Known("nope") {
+		t.Error("nope: Known")
+	}
+	if _, err := formatter.New("nope", false); !errors.Is(err, formatter.ErrUnknownFormat) {
+		t.Errorf("got %v, want ErrUnknownFormat", err)
+	}
+	if f := formatter.ParseFormatter("nope", false); f.Name() != "text" {
+		t.Errorf("ParseFormatter fallback: got %s", f.Name())
+	}
+}
+
+// Um formato registrado vale pro New e pras regras do dynamic.
+func TestRegister(t *testing.T) {
+	if err := formatter.Register("", func(bool) formatter.Formatter { return named("x") }); err == nil {
+		t.Error("empty name: want error")
+	}
+	if err := formatter.Register("x", nil); err == nil {
+		t.Error("nil factory: want error")
+	}
+	if err := formatter.Register("niltest", func(bool) formatter.Formatter { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	// não há Unregister: troca por uma factory válida pros testes que
+	// percorrem Names().
+	t.Cleanup(func() {
+		_ = formatter.Register("niltest", func(bool) formatter.Formatter { return named("niltest") })
+	})
+	if _, err := formatter.New("niltest", false); err == nil {
+		t.Error("nil formatter: want error")
+	}
+
+	if err := formatter.Register(" RegTest ", func(bool) formatter.Formatter { return named("regtest") }); err != nil {
+		t.Fatal(err)
+	}
+	f, err := formatter.New("REGTEST", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pick(t, f, dynEntry(kbx.LevelInfo)); got != "regtest" {
+		t.Errorf("got %q", got)
+	}
+
+	d := &formatter.DynamicFormatter{}
+	if err := d.SetLevelFormats(map[string]string{"default": "regtest"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := pick(t, d, dynEntry(kbx.LevelInfo)); got != "regtest" {
+		t.Errorf("dynamic rule: got %q", got)
+	}
+
+	// registrar de novo substitui.
+	if err := formatter.Register("regtest", func(bool) formatter.Formatter { return named("again") }); err != nil {
+		t.Fatal(err)
+	}
+	f, _ = formatter.New("regtest", false)
+	if got := pick(t, f, dynEntry(kbx.LevelInfo)); got != "again" {
+		t.Errorf("replaced: got %q", got)
+	}
+}
@@ -0,0 +1,136 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	control "github.com/kubex-ecosystem/logz/internal/manager/control"
+)
+
+// Sanitizer é implementado pelos formatters de saída em texto que sabem
+// neutralizar quebras de linha, sequências ANSI e caracteres de controle.
+// Formatters estruturados (JSON, YAML...) não implementam: continuam lossless.
+type Sanitizer interface {
+	SetSanitize(enabled bool)
+}
+
+// ApplySecurity liga a sanitização no formatter quando flags contém
+// control.SecSanitize. Sem a flag nada muda: uma sanitização ligada por
+// quem criou o formatter nunca é desligada. Formatters que não implementam
+// Sanitizer são ignorados.
+func ApplySecurity(f Formatter, flags control.SecFlag) Formatter {
+	if s, ok := f.(Sanitizer); ok && flags.Has(control.SecSanitize) {
+		s.SetSanitize(true)
+	}
+	return f
+}
+
+// Sanitize prepara um texto vindo do usuário pra ir numa linha de log:
+//   - remove sequências de escape ANSI (CSI/OSC), que poderiam forjar cores
+//     ou mexer no terminal de quem lê o log;
+//   - escapa \n, \r e \t, pra que ninguém forje linhas novas;
+//   - escapa os demais caracteres de controle (C0, DEL, C1) e os separadores
+//     de linha Unicode como \xNN / \uNNNN.
+func Sanitize(s string) string {
+	if !needsSanitize(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b {
+			i += ansiLen(s[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == utf8.RuneError && size == 1:
+			b.WriteRune(utf8.RuneError)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case r == 0x2028 || r == 0x2029 || (r >= 0x80 && r <= 0x9f):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// StripANSI remove só as sequências de escape ANSI, sem escapar mais nada.
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b {
+			i += ansiLen(s[i:])
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+func needsSanitize(s string) bool {
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f || (r >= 0x80 && r <= 0x9f) ||
+			r == 0x2028 || r == 0x2029 || r == utf8.RuneError {
+			return true
+		}
+	}
+	return false
+}
+
+// ansiLen devolve o tamanho da sequência de escape que começa em s[0] (ESC).
+func ansiLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[': // CSI: ESC [ params... final(0x40-0x7e)
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']': // OSC: termina em BEL ou ESC \
+		for i := 2; i < len(s); i++ {
+			if s[i] == 0x07 {
+				return i + 1
+			}
+			if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	default: // ESC + um caractere
+		return 2
+	}
+}
+
+// isPrintableASCIIWord indica se s pode ir sem aspas numa saída key=value.
+func isPrintableASCIIWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
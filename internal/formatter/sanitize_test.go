@@ -0,0 +1,62 @@
+package formatter_test
+
+import (
+	"strings"
+	"testing"
+
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	control "github.com/kubex-ecosystem/logz/internal/manager/control"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+func TestSanitize(t *testing.T) {
+	for in, want := range map[string]string{
+		"plain text":                 "plain text",
+		"user\nlevel=error forged":   `user\nlevel=error forged`,
+		"a\r\tb":                     `a\r\tb`,
+		"\x1b[31mred\x1b[0m":         "red",
+		"\x1b]0;title\x07ok":         "ok",
+		"nul\x00del\x7f":             `nul\x00del\x7f`,
+		"line\u2028sep\u0085":        `line\u2028sep\u0085`,
+		"bad\xffutf8":                "bad�utf8",
+		"acentuação e emoji 🚀 ficam": "acentuação e emoji 🚀 ficam",
+	} {
+		if got := formatter.Sanitize(in); got != want {
+			t.Errorf("Sanitize(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := formatter.StripANSI("\x1b[1mbold\x1b[0m\n"); got != "bold\n" {
+		t.Errorf("StripANSI = %q", got)
+	}
+}
+
+// Com Sanitize ligado, uma mensagem com "\n" não forja uma entry nova.
+func TestTextFormattersKeepOneLine(t *testing.T) {
+	e := C.NewLogzEntry(kbx.LevelInfo).
+		WithMessage("login ok\nlevel=error msg=forged \x1b[31m").
+		WithField("user", "ana\r\nx")
+	for _, name := range []string{"text", "logfmt", "minimal", "pretty", "console"} {
+		f, err := formatter.New(name, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := f.(formatter.Sanitizer); !ok {
+			t.Fatalf("%s does not implement Sanitizer", name)
+		}
+		formatter.ApplySecurity(f, control.SecSanitize)
+		b, err := f.Format(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := strings.TrimRight(string(b), "\n")
+		if name == "console" {
+			// o console quebra a árvore de fields de propósito; a mensagem
+			// continua numa linha só.
+			out, _, _ = strings.Cut(out, "\n    ")
+		}
+		if strings.Contains(out, "\n") || strings.Contains(out, "\x1b[31m") {
+			t.Errorf("%s: %q", name, out)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"runtime"
 	"strings"
 
 	"github.com/kubex-ecosystem/logz/internal/module/kbx"
@@ -13,50 +12,24 @@ import (
 type TextFormatter struct {
 	DisableColor bool
 	DisableIcon  bool
+	Sanitize     bool
+	// Time: padrão kbx.DefaultTimestampFormat em kbx.DefaultLogTimezone.
+	Time kbx.TimeFormat
+	// Term diz se o destino é um terminal e quantas cores aceita; fora de
+	// um terminal não há ícones, e sem cor o nível sai puro.
+	Term kbx.TermInfo
+	// Theme dá cor, ícone e rótulo de cada nível; zero = tema "default".
+	Theme Theme
 }
 
-// --- ICONES E CORES ---------------------------------------------------------
-
-var icons = map[kbx.Level]string{
-	kbx.LevelAlert:    "🚨",
-	kbx.LevelAnswer:   "💡",
-	kbx.LevelNotice:   "📝",
-	kbx.LevelTrace:    "🔍",
-	kbx.LevelSuccess:  "✅",
-	kbx.LevelDebug:    "🐛",
-	kbx.LevelInfo:     "ℹ️",
-	kbx.LevelWarn:     "⚠️",
-	kbx.LevelError:    "❌",
-	kbx.LevelFatal:    "💀",
-	kbx.LevelPanic:    "🔥",
-	kbx.LevelBug:      "🐞",
-	kbx.LevelCritical: "❗",
-}
-
-var colors = map[kbx.Level]string{
-	kbx.LevelAlert:    "\033[31m",
-	kbx.LevelAnswer:   "\033[34m",
-	kbx.LevelNotice:   "\033[33m",
-	kbx.LevelTrace:    "\033[36m",
-	kbx.LevelSuccess:  "\033[32m",
-	kbx.LevelDebug:    "\033[34m",
-	kbx.LevelInfo:     "\033[32m",
-	kbx.LevelWarn:     "\033[33m",
-	kbx.LevelError:    "\033[31m",
-	kbx.LevelFatal:    "\033[35m",
-	kbx.LevelPanic:    "\033[31m",
-	kbx.LevelBug:      "\033[31m",
-	kbx.LevelCritical: "\033[31m",
-}
-
-const reset = "\033[0m"
-
 // --- CONSTRUCTOR ------------------------------------------------------------
 
 func NewTextFormatter(pretty bool) Formatter {
 	return &TextFormatter{
-		DisableColor: os.Getenv("LOGZ_NO_COLOR") != "" || runtime.GOOS == "windows" || !pretty,
+		DisableColor: !pretty,
 		DisableIcon:  os.Getenv("LOGZ_NO_ICON") != "" || !pretty,
+		// o logger troca pelo destino real (SetTerm); sozinho, vale o stdout.
+		Term: kbx.DetectTerm(os.Stdout, nil),
 	}
 }
 
@@ -66,25 +39,57 @@ func (f *TextFormatter) Name() string {
 	return "text"
 }
 
+// Clone implementa Cloner.
+func (f *TextFormatter) Clone() Formatter {
+	c := *f
+	return &c
+}
+
+// SetSanitize implementa Sanitizer.
+func (f *TextFormatter) SetSanitize(enabled bool) {
+	f.Sanitize = enabled
+}
+
+// SetTerm implementa TermFormatter.
+func (f *TextFormatter) SetTerm(t kbx.TermInfo) {
+	f.Term = t
+}
+
+// SetTheme implementa ThemedFormatter.
+func (f *TextFormatter) SetTheme(t Theme) {
+	f.Theme = t
+}
+
+// SetTimeFormat implementa TimeFormatter.
+func (f *TextFormatter) SetTimeFormat(tf kbx.TimeFormat) {
+	f.Time = tf
+}
+
 func (f *TextFormatter) Format(e kbx.Entry) ([]byte, error) {
 	if err := e.Validate(); err != nil {
 		return nil, err
 	}
+	e = resolved(e)
 
 	msg := strings.TrimSpace(e.GetMessage())
+	if f.Sanitize {
+		msg = Sanitize(msg)
+	}
+
+	theme := f.Theme.orDefault()
 
-	// Level string
-	levelStr := string(e.GetLevel())
+	// Level string: rótulo do tema, pintado, alinhado fora dos colchetes.
+	label := theme.Label(e.GetLevel())
+	levelStr := label
 	if !f.DisableColor && e.GetShowColor() {
-		if c, ok := colors[e.GetLevel()]; ok {
-			levelStr = c + levelStr + reset
-		}
+		levelStr = theme.Paint(f.Term.Color, e.GetLevel(), label)
 	}
+	levelStr = "[" + levelStr + "]" + theme.Pad(label)
 
 	// Icon
 	icon := ""
-	if !f.DisableIcon && e.GetShowIcon() {
-		if ic, ok := icons[e.GetLevel()]; ok {
+	if !f.DisableIcon && f.Term.TTY && e.GetShowIcon() {
+		if ic := theme.Icon(e.GetLevel()); ic != "" {
 			icon = ic + " "
 		}
 	}
@@ -92,13 +97,16 @@ func (f *TextFormatter) Format(e kbx.Entry) ([]byte, error) {
 	// Timestamp (opcional)
 	ts := ""
 	if e.GetTimestamp().Unix() != 0 {
-		ts = e.GetTimestamp().Format("2006-01-02 15:04:05")
+		ts = f.Time.Or(textTimeFormat).Format(e.GetTimestamp())
 		ts = "[" + ts + "] "
 	}
 
 	// Context
 	ctx := ""
 	if c := e.GetContext(); c != "" {
+		if f.Sanitize {
+			c = Sanitize(c)
+		}
 		ctx = "(" + c + ") "
 	}
 
@@ -117,16 +125,16 @@ func (f *TextFormatter) Format(e kbx.Entry) ([]byte, error) {
 
 	// TraceID (opcional)
 	if e.GetTraceID() != "" && e.GetShowTraceID() {
-		meta += fmt.Sprintf("\nTraceID: %s", e.GetTraceID())
+		meta += fmt.Sprintf("\nTrace: %s", f.clean(tracePairs(e)))
 	}
 
 	// Caller (opcional)
 	if e.GetShowCaller() || e.GetShowStack() {
-		meta += fmt.Sprintf("\nCaller: %s", e.GetCaller())
+		meta += fmt.Sprintf("\nCaller: %s", f.clean(e.GetCaller()))
 	}
 
 	// Line final → limpa, previsível, sem comer whitespace
-	line := fmt.Sprintf("%s[%s] %s%s %s%s",
+	line := fmt.Sprintf("%s%s %s%s %s%s",
 		ts,
 		levelStr,
 		ctx,
@@ -137,3 +145,10 @@ func (f *TextFormatter) Format(e kbx.Entry) ([]byte, error) {
 
 	return []byte(line), nil
 }
+
+func (f *TextFormatter) clean(s string) string {
+	if f.Sanitize {
+		return Sanitize(s)
+	}
+	return s
+}
@@ -0,0 +1,347 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// TermFormatter é implementado pelos formatters que mudam de cara conforme
+// o destino: cores e ícones num terminal, texto puro num arquivo ou pipe.
+type TermFormatter interface {
+	SetTerm(t kbx.TermInfo)
+}
+
+// ApplyTerm informa ao formatter como é o destino da saída.
+func ApplyTerm(f Formatter, t kbx.TermInfo) Formatter {
+	if tf, ok := f.(TermFormatter); ok {
+		tf.SetTerm(t)
+	}
+	return f
+}
+
+// ThemedFormatter é implementado pelos formatters que desenham o nível
+// com um Theme (text e pretty).
+type ThemedFormatter interface {
+	SetTheme(t Theme)
+}
+
+// ApplyTheme troca o tema do formatter. Um tema zero não mexe em nada.
+func ApplyTheme(f Formatter, t Theme) Formatter {
+	if t.IsZero() {
+		return f
+	}
+	if tf, ok := f.(ThemedFormatter); ok {
+		tf.SetTheme(t)
+	}
+	return f
+}
+
+// LevelColor é a cor de um nível nas três profundidades; o formatter usa a
+// mais rica que o destino aceita e cai pra próxima quando ela falta.
+type LevelColor struct {
+	Basic     string // parâmetros SGR de 16 cores/atributos: "31", "97;41", "1"
+	Ansi256   uint8  // índice na paleta xterm; 0 = não definido
+	RGB       string // "#rrggbb"; vazio = não definido
+	BgAnsi256 uint8  // fundo, idem
+	BgRGB     string
+	Bold      bool
+}
+
+// Sequence devolve o escape ANSI da cor no modo pedido ("" em ColorNone).
+func (c LevelColor) Sequence(mode kbx.ColorMode) string {
+	var params string
+	switch mode {
+	case kbx.ColorNone:
+		return ""
+	case kbx.ColorTrueColor:
+		if fg, ok := rgbParams("38", c.RGB); ok {
+			params = fg
+			if bg, ok := rgbParams("48", c.BgRGB); ok {
+				params += ";" + bg
+			}
+			break
+		}
+		fallthrough
+	case kbx.Color256:
+		if c.Ansi256 != 0 {
+			params = "38;5;" + strconv.Itoa(int(c.Ansi256))
+			if c.BgAnsi256 != 0 {
+				params += ";48;5;" + strconv.Itoa(int(c.BgAnsi256))
+			}
+			break
+		}
+		fallthrough
+	default:
+		params = c.Basic
+	}
+	if params == "" && !c.Bold {
+		return ""
+	}
+	if c.Bold {
+		params = strings.TrimSuffix("1;"+params, ";")
+	}
+	return "\x1b[" + params + "m"
+}
+
+func rgbParams(prefix, hex string) (string, bool) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return "", false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return "", false
+	}
+	return prefix + ";2;" + strconv.Itoa(int(v>>16&0xff)) + ";" +
+		strconv.Itoa(int(v>>8&0xff)) + ";" + strconv.Itoa(int(v&0xff)), true
+}
+
+// LevelStyle é como um nível aparece: cor, ícone e rótulo.
+type LevelStyle struct {
+	Color LevelColor
+	Icon  string // vazio = sem ícone
+	Label string // vazio = o nome do nível
+}
+
+// Theme reúne o estilo de cada nível. Níveis ausentes saem sem cor, sem
+// ícone e com o próprio nome.
+type Theme struct {
+	Name   string
+	Levels map[kbx.Level]LevelStyle
+	// Width alinha os rótulos completando com espaços até Width colunas
+	// (0 = sem alinhamento).
+	Width int
+}
+
+// IsZero indica um tema não configurado.
+func (t Theme) IsZero() bool {
+	return t.Levels == nil
+}
+
+// Clone copia o tema, pra ser ajustado sem mexer no original.
+func (t Theme) Clone() Theme {
+	levels := make(map[kbx.Level]LevelStyle, len(t.Levels))
+	for l, s := range t.Levels {
+		levels[l] = s
+	}
+	t.Levels = levels
+	return t
+}
+
+// Label devolve o rótulo do nível.
+func (t Theme) Label(l kbx.Level) string {
+	if s := t.Levels[l].Label; s != "" {
+		return s
+	}
+	return string(l)
+}
+
+// Icon devolve o ícone do nível ("" quando não há).
+func (t Theme) Icon(l kbx.Level) string {
+	return t.Levels[l].Icon
+}
+
+// Pad devolve os espaços que faltam pra label ocupar Width colunas.
+func (t Theme) Pad(label string) string {
+	if n := t.Width - utf8.RuneCountInString(label); n > 0 {
+		return strings.Repeat(" ", n)
+	}
+	return ""
+}
+
+// Paint pinta s com a cor do nível, no modo pedido.
+func (t Theme) Paint(mode kbx.ColorMode, l kbx.Level, s string) string {
+	seq := t.Levels[l].Color.Sequence(mode)
+	if seq == "" {
+		return s
+	}
+	return seq + s + reset
+}
+
+const reset = "\x1b[0m"
+
+// --- TEMAS EMBUTIDOS E REGISTRO ---------------------------------------------
+
+var (
+	themesMu sync.RWMutex
+	themes   = map[string]Theme{}
+
+	// apelidos aceitos na config/env.
+	themeAliases = map[string]string{
+		"no-emoji":     "ascii",
+		"noemoji":      "ascii",
+		"highcontrast": "high-contrast",
+		"contrast":     "high-contrast",
+		"mono":         "monochrome",
+	}
+)
+
+func init() {
+	for _, t := range []Theme{defaultTheme(), asciiTheme(), highContrastTheme(), monochromeTheme()} {
+		themes[t.Name] = t
+	}
+}
+
+// RegisterTheme registra (ou substitui) um tema pelo nome, pra ser
+// escolhido na config ("theme") ou em LOGZ_THEME.
+func RegisterTheme(t Theme) error {
+	name := strings.ToLower(strings.TrimSpace(t.Name))
+	if name == "" {
+		return fmt.Errorf("logz: theme without name")
+	}
+	if t.IsZero() {
+		return fmt.Errorf("logz: theme %q has no levels", name)
+	}
+	t = t.Clone()
+	t.Name = name
+	themesMu.Lock()
+	themes[name] = t
+	themesMu.Unlock()
+	return nil
+}
+
+// LookupTheme devolve uma cópia do tema registrado com esse nome.
+func LookupTheme(name string) (Theme, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := themeAliases[name]; ok {
+		name = alias
+	}
+	themesMu.RLock()
+	t, ok := themes[name]
+	themesMu.RUnlock()
+	if !ok {
+		return Theme{}, false
+	}
+	return t.Clone(), true
+}
+
+// ThemeNames lista os temas registrados, em ordem alfabética.
+func ThemeNames() []string {
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+	names := make([]string, 0, len(themes))
+	for n := range themes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultTheme é o tema usado quando nada foi configurado.
+func DefaultTheme() Theme {
+	t, _ := LookupTheme("default")
+	return t
+}
+
+// orDefault devolve t, ou o tema "default" registrado quando t é zero.
+// Só leitura: não copia.
+func (t Theme) orDefault() Theme {
+	if !t.IsZero() {
+		return t
+	}
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+	return themes["default"]
+}
+
+// defaultTheme: cores por nível e ícones emoji.
+func defaultTheme() Theme {
+	return Theme{Name: "default", Levels: map[kbx.Level]LevelStyle{
+		kbx.LevelAlert:    {Icon: "🚨", Color: LevelColor{Basic: "31", Ansi256: 196, RGB: "#ff3b30", Bold: true}},
+		kbx.LevelAnswer:   {Icon: "💡", Color: LevelColor{Basic: "34", Ansi256: 75, RGB: "#5fafff"}},
+		kbx.LevelNotice:   {Icon: "📝", Color: LevelColor{Basic: "33", Ansi256: 179, RGB: "#d7af5f"}},
+		kbx.LevelTrace:    {Icon: "🔍", Color: LevelColor{Basic: "36", Ansi256: 80, RGB: "#5fd7d7"}},
+		kbx.LevelSuccess:  {Icon: "✅", Color: LevelColor{Basic: "32", Ansi256: 78, RGB: "#5fd787"}},
+		kbx.LevelDebug:    {Icon: "🐛", Color: LevelColor{Basic: "34", Ansi256: 69, RGB: "#5f87ff"}},
+		kbx.LevelInfo:     {Icon: "ℹ️", Color: LevelColor{Basic: "32", Ansi256: 114, RGB: "#87d787"}},
+		kbx.LevelWarn:     {Icon: "⚠️", Color: LevelColor{Basic: "33", Ansi256: 214, RGB: "#ffaf00"}},
+		kbx.LevelError:    {Icon: "❌", Color: LevelColor{Basic: "31", Ansi256: 203, RGB: "#ff5f5f"}},
+		kbx.LevelFatal:    {Icon: "💀", Color: LevelColor{Basic: "35", Ansi256: 165, RGB: "#d700ff", Bold: true}},
+		kbx.LevelPanic:    {Icon: "🔥", Color: LevelColor{Basic: "31", Ansi256: 196, RGB: "#ff0000", Bold: true}},
+		kbx.LevelBug:      {Icon: "🐞", Color: LevelColor{Basic: "31", Ansi256: 167, RGB: "#d75f5f"}},
+		kbx.LevelCritical: {Icon: "❗", Color: LevelColor{Basic: "31", Ansi256: 160, RGB: "#d70000", Bold: true}},
+	}}
+}
+
+// asciiTheme: mesmas cores, sem emoji (terminais/fonte sem suporte, CI),
+// rótulos em maiúsculas e alinhados.
+func asciiTheme() Theme {
+	t := defaultTheme().Clone()
+	t.Name = "ascii"
+	t.Width = len("CRITICAL")
+	icons := map[kbx.Level]string{
+		kbx.LevelAlert:    "(!)",
+		kbx.LevelAnswer:   "(?)",
+		kbx.LevelNotice:   "(*)",
+		kbx.LevelTrace:    "(.)",
+		kbx.LevelSuccess:  "(+)",
+		kbx.LevelDebug:    "(~)",
+		kbx.LevelInfo:     "(i)",
+		kbx.LevelWarn:     "(!)",
+		kbx.LevelError:    "(x)",
+		kbx.LevelFatal:    "(X)",
+		kbx.LevelPanic:    "(X)",
+		kbx.LevelBug:      "(#)",
+		kbx.LevelCritical: "(X)",
+	}
+	for l, s := range t.Levels {
+		s.Icon = icons[l]
+		s.Label = strings.ToUpper(string(l))
+		t.Levels[l] = s
+	}
+	return t
+}
+
+// highContrastTheme: cores vivas em negrito e fundo nos níveis graves.
+func highContrastTheme() Theme {
+	t := defaultTheme().Clone()
+	t.Name = "high-contrast"
+	colors := map[kbx.Level]LevelColor{
+		kbx.LevelTrace:    {Basic: "96", Ansi256: 51, RGB: "#00ffff"},
+		kbx.LevelDebug:    {Basic: "94", Ansi256: 33, RGB: "#0087ff"},
+		kbx.LevelInfo:     {Basic: "92", Ansi256: 46, RGB: "#00ff00"},
+		kbx.LevelSuccess:  {Basic: "92", Ansi256: 46, RGB: "#00ff00"},
+		kbx.LevelNotice:   {Basic: "97", Ansi256: 231, RGB: "#ffffff"},
+		kbx.LevelAnswer:   {Basic: "95", Ansi256: 201, RGB: "#ff00ff"},
+		kbx.LevelWarn:     {Basic: "30;103", Ansi256: 16, BgAnsi256: 226, RGB: "#000000", BgRGB: "#ffff00"},
+		kbx.LevelError:    {Basic: "97;41", Ansi256: 231, BgAnsi256: 196, RGB: "#ffffff", BgRGB: "#d70000"},
+		kbx.LevelBug:      {Basic: "97;41", Ansi256: 231, BgAnsi256: 196, RGB: "#ffffff", BgRGB: "#d70000"},
+		kbx.LevelAlert:    {Basic: "97;41", Ansi256: 231, BgAnsi256: 196, RGB: "#ffffff", BgRGB: "#ff0000"},
+		kbx.LevelCritical: {Basic: "97;45", Ansi256: 231, BgAnsi256: 129, RGB: "#ffffff", BgRGB: "#af00ff"},
+		kbx.LevelFatal:    {Basic: "97;45", Ansi256: 231, BgAnsi256: 129, RGB: "#ffffff", BgRGB: "#af00ff"},
+		kbx.LevelPanic:    {Basic: "97;45", Ansi256: 231, BgAnsi256: 129, RGB: "#ffffff", BgRGB: "#af00ff"},
+	}
+	for l, s := range t.Levels {
+		s.Color = colors[l]
+		s.Color.Bold = true
+		t.Levels[l] = s
+	}
+	return t
+}
+
+// monochromeTheme: sem cor; só negrito/vídeo reverso pra destacar os
+// níveis graves.
+func monochromeTheme() Theme {
+	t := defaultTheme().Clone()
+	t.Name = "monochrome"
+	for l, s := range t.Levels {
+		switch l {
+		case kbx.LevelWarn, kbx.LevelError, kbx.LevelBug:
+			s.Color = LevelColor{Bold: true}
+		case kbx.LevelAlert, kbx.LevelCritical, kbx.LevelFatal, kbx.LevelPanic:
+			s.Color = LevelColor{Basic: "7", Bold: true}
+		case kbx.LevelTrace, kbx.LevelDebug:
+			s.Color = LevelColor{Basic: "2"}
+		default:
+			s.Color = LevelColor{}
+		}
+		t.Levels[l] = s
+	}
+	return t
+}
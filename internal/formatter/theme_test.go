@@ -0,0 +1,114 @@
+package formatter_test
+
+import (
+	"testing"
+
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// Cada modo usa a cor mais rica definida e cai pra próxima quando falta.
+func TestLevelColorSequence(t *testing.T) {
+	full := formatter.LevelColor{Basic: "31", Ansi256: 196, RGB: "#ff0000", BgRGB: "#000010", BgAnsi256: 16, Bold: true}
+	for _, tc := range []struct {
+		c    formatter.LevelColor
+		mode kbx.ColorMode
+		want string
+	}{
+		{full, kbx.ColorNone, ""},
+		{full, kbx.ColorBasic, "\x1b[1;31m"},
+		{full, kbx.Color256, "\x1b[1;38;5;196;48;5;16m"},
+		{full, kbx.ColorTrueColor, "\x1b[1;38;2;255;0;0;48;2;0;0;16m"},
+		{formatter.LevelColor{Basic: "32", Ansi256: 78}, kbx.ColorTrueColor, "\x1b[38;5;78m"},
+		{formatter.LevelColor{Basic: "32", RGB: "#bad"}, kbx.ColorTrueColor, "\x1b[32m"},
+		{formatter.LevelColor{Bold: true}, kbx.ColorBasic, "\x1b[1m"},
+		{formatter.LevelColor{}, kbx.ColorTrueColor, ""},
+	} {
+		if got := tc.c.Sequence(tc.mode); got != tc.want {
+			t.Errorf("%+v in %v: got %q, want %q", tc.c, tc.mode, got, tc.want)
+		}
+	}
+}
+
+func TestBuiltinThemes(t *testing.T) {
+	for _, name := range []string{"default", "ascii", "high-contrast", "monochrome"} {
+		th, ok := formatter.LookupTheme(name)
+		if !ok || th.IsZero() {
+			t.Fatalf("%s: not registered", name)
+		}
+		for _, l := range []kbx.Level{kbx.LevelDebug, kbx.LevelInfo, kbx.LevelWarn, kbx.LevelError, kbx.LevelFatal} {
+			if _, ok := th.Levels[l]; !ok {
+				t.Errorf("%s: no style for %s", name, l)
+			}
+		}
+	}
+	for alias, want := range map[string]string{"no-emoji": "ascii", " Mono ": "monochrome", "contrast": "high-contrast"} {
+		if th, ok := formatter.LookupTheme(alias); !ok || th.Name != want {
+			t.Errorf("%q: got %q", alias, th.Name)
+		}
+	}
+	if _, ok := formatter.LookupTheme("nope"); ok {
+		t.Error("nope: found")
+	}
+
+	// ascii: sem emoji, rótulos em maiúsculas e alinhados.
+	ascii, _ := formatter.LookupTheme("ascii")
+	for l := range ascii.Levels {
+		for _, r := range ascii.Icon(l) + ascii.Label(l) {
+			if r > 127 {
+				t.Errorf("ascii %s: non-ASCII %q", l, ascii.Icon(l)+ascii.Label(l))
+			}
+		}
+	}
+	if got := ascii.Label(kbx.LevelInfo) + ascii.Pad(ascii.Label(kbx.LevelInfo)); got != "INFO    " {
+		t.Errorf("padded label: %q", got)
+	}
+	if got := ascii.Paint(kbx.ColorNone, kbx.LevelError, "x"); got != "x" {
+		t.Errorf("Paint without color: %q", got)
+	}
+
+	// a cópia devolvida não alcança o registrado.
+	ascii.Levels[kbx.LevelInfo] = formatter.LevelStyle{Label: "changed"}
+	if again, _ := formatter.LookupTheme("ascii"); again.Label(kbx.LevelInfo) != "INFO" {
+		t.Error("LookupTheme returned the registered map")
+	}
+}
+
+func TestRegisterTheme(t *testing.T) {
+	if err := formatter.RegisterTheme(formatter.Theme{Levels: map[kbx.Level]formatter.LevelStyle{}}); err == nil {
+		t.Error("theme without name: want error")
+	}
+	if err := formatter.RegisterTheme(formatter.Theme{Name: "empty"}); err == nil {
+		t.Error("theme without levels: want error")
+	}
+
+	levels := map[kbx.Level]formatter.LevelStyle{
+		kbx.LevelInfo: {Label: "inf", Icon: ">", Color: formatter.LevelColor{Basic: "34"}},
+	}
+	if err := formatter.RegisterTheme(formatter.Theme{Name: " Custom ", Levels: levels, Width: 5}); err != nil {
+		t.Fatal(err)
+	}
+	levels[kbx.LevelInfo] = formatter.LevelStyle{Label: "later"}
+
+	th, ok := formatter.LookupTheme("CUSTOM")
+	if !ok {
+		t.Fatal("custom theme not found")
+	}
+	if th.Name != "custom" || th.Label(kbx.LevelInfo) != "inf" || th.Icon(kbx.LevelInfo) != ">" {
+		t.Errorf("got %+v", th)
+	}
+	// níveis fora do tema saem com o próprio nome e sem cor.
+	if th.Label(kbx.LevelWarn) != "warn" || th.Paint(kbx.ColorBasic, kbx.LevelWarn, "w") != "w" {
+		t.Errorf("missing level: %q", th.Label(kbx.LevelWarn))
+	}
+	if got := th.Paint(kbx.ColorBasic, kbx.LevelInfo, "i"); got != "\x1b[34mi\x1b[0m" {
+		t.Errorf("Paint: %q", got)
+	}
+	found := false
+	for _, n := range formatter.ThemeNames() {
+		found = found || n == "custom"
+	}
+	if !found {
+		t.Errorf("ThemeNames: %v", formatter.ThemeNames())
+	}
+}
@@ -0,0 +1,53 @@
+package formatter
+
+import (
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// TimeFormatter é implementado pelos formatters que escrevem timestamp e
+// aceitam layout/fuso configuráveis.
+type TimeFormatter interface {
+	SetTimeFormat(tf kbx.TimeFormat)
+}
+
+// ApplyTimeFormat configura o timestamp do formatter. Um tf zero não mexe
+// em nada: cada formatter segue com o seu padrão.
+func ApplyTimeFormat(f Formatter, tf kbx.TimeFormat) Formatter {
+	if tf.IsZero() {
+		return f
+	}
+	if t, ok := f.(TimeFormatter); ok {
+		t.SetTimeFormat(tf)
+	}
+	return f
+}
+
+// Padrões de cada formatter quando nada foi configurado.
+var (
+	textTimeFormat   = kbx.TimeFormat{Layout: kbx.DefaultTimestampFormat, Location: defaultLocation()}
+	logfmtTimeFormat = kbx.TimeFormat{Layout: time.RFC3339Nano, Location: time.UTC}
+	csvTimeFormat    = kbx.TimeFormat{Layout: time.RFC3339, Location: time.UTC}
+)
+
+func defaultLocation() *time.Location {
+	tf, err := kbx.ParseTimeFormat("", kbx.DefaultLogTimezone)
+	if err != nil || tf.Location == nil {
+		return time.UTC
+	}
+	return tf.Location
+}
+
+// timed devolve o que os formatters estruturados (JSON, YAML) passam pro
+// Marshal: com um TimeFormat configurado, a visão kbx.TimedEntry da entry,
+// que já serializa o "ts" no formato pedido; senão, a própria entry.
+func timed(e kbx.Entry, tf kbx.TimeFormat) any {
+	if tf.IsZero() {
+		return e
+	}
+	if te, ok := e.(kbx.TimedEntry); ok {
+		return te.Timed(tf.Or(logfmtTimeFormat))
+	}
+	return e
+}
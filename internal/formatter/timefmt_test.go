@@ -0,0 +1,94 @@
+package formatter_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+var when = time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+
+func timedEntry() *C.Entry {
+	e := C.NewLogzEntry(kbx.LevelInfo).WithMessage(`"ts": ` + when.Format(time.RFC3339Nano)).(*C.Entry)
+	e.WithTimestamp(when)
+	// um field com o mesmo instante não pode ser confundido com o "ts".
+	e.WithField("ts", when)
+	return e
+}
+
+func TestJSONTimeFormat(t *testing.T) {
+	saoPaulo, err := kbx.ParseTimeFormat("datetime", "America/Sao_Paulo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		tf   kbx.TimeFormat
+		want any
+	}{
+		{kbx.TimeFormat{}, when.Format(time.RFC3339Nano)},
+		{kbx.TimeFormat{Layout: kbx.TimeUnixMilli}, float64(when.UnixMilli())},
+		{saoPaulo, "2024-05-06 04:08:09"},
+	} {
+		for _, pretty := range []bool{false, true} {
+			f := formatter.NewJSONFormatter(pretty)
+			formatter.ApplyTimeFormat(f, tt.tf)
+			e := timedEntry()
+			b, err := f.Format(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc map[string]any
+			if err := json.Unmarshal(b, &doc); err != nil {
+				t.Fatalf("%s: %v", b, err)
+			}
+			if doc["ts"] != tt.want {
+				t.Errorf("%+v: ts = %#v, want %#v", tt.tf, doc["ts"], tt.want)
+			}
+			if doc["msg"] != e.Message || doc["fields"].(map[string]any)["ts"] != when.Format(time.RFC3339Nano) {
+				t.Errorf("%+v: msg/fields changed: %s", tt.tf, b)
+			}
+			if !e.Timestamp.Equal(when) {
+				t.Errorf("entry timestamp changed: %v", e.Timestamp)
+			}
+		}
+	}
+}
+
+func TestYAMLTimeFormat(t *testing.T) {
+	for _, tt := range []struct {
+		tf   kbx.TimeFormat
+		want string
+	}{
+		{kbx.TimeFormat{Layout: kbx.TimeUnix}, "ts: 1714979289"},
+		{kbx.TimeFormat{Layout: time.Kitchen, Location: time.UTC}, `ts: "7:08AM"`},
+	} {
+		f := formatter.NewYamlFormatter(false)
+		formatter.ApplyTimeFormat(f, tt.tf)
+		b, err := f.Format(timedEntry())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), "- "+tt.want+"\n") {
+			t.Errorf("%+v:\n%s", tt.tf, b)
+		}
+		var doc struct {
+			Entries []struct {
+				Msg    string         `yaml:"msg"`
+				Fields map[string]any `yaml:"fields"`
+			} `yaml:"entries"`
+		}
+		if err := yaml.Unmarshal(b, &doc); err != nil || len(doc.Entries) != 1 {
+			t.Fatalf("%v:\n%s", err, b)
+		}
+		if got, _ := doc.Entries[0].Fields["ts"].(time.Time); !got.Equal(when) {
+			t.Errorf("field ts = %#v", doc.Entries[0].Fields["ts"])
+		}
+	}
+}
@@ -1,45 +1,335 @@
 package formatter
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"encoding/xml"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kubex-ecosystem/logz/internal/module/kbx"
 )
 
+// XMLFormatter escreve cada entry como um elemento <entry>. Tags e fields
+// viram elementos aninhados com o nome num atributo (qualquer chave é
+// válida) e o tipo em "type":
+//
+//	<entry>
+//	  <ts>2024-05-01T12:00:00Z</ts>
+//	  <level>info</level>
+//	  <msg>login</msg>
+//	  <tags><tag name="env">prod</tag></tags>
+//	  <fields>
+//	    <field name="user" type="string">bob</field>
+//	    <field name="roles" type="list"><item type="string">admin</item></field>
+//	  </fields>
+//	</entry>
+//
+// Sozinho, cada registro é um fragmento. Com Document, o formatter devolve
+// também o prólogo e o elemento raiz (Header/Footer), que um
+// writer.DocumentWriter escreve na abertura e no Close/rotação, pra que o
+// arquivo seja XML válido.
 type XMLFormatter struct {
 	Pretty bool
+	Time   kbx.TimeFormat
+	// Document liga o modo documento: registros indentados sob Root.
+	Document bool
+	// Root é o elemento raiz no modo documento ("logs" se vazio).
+	Root string
+}
+
+// DocumentFormatter é implementado pelos formatters cuja saída só é
+// válida dentro de um documento: Header abre o documento e Footer o
+// fecha. Ver writer.DocumentWriter.
+type DocumentFormatter interface {
+	Header() []byte
+	Footer() []byte
 }
 
 func NewXMLFormatter(pretty bool) Formatter {
 	return &XMLFormatter{Pretty: pretty}
 }
 
+// NewXMLDocumentFormatter cria o XMLFormatter no modo documento.
+func NewXMLDocumentFormatter(pretty bool) Formatter {
+	return &XMLFormatter{Pretty: pretty, Document: true}
+}
+
 func (f *XMLFormatter) Name() string {
 	return "xml"
 }
 
+// Clone implementa Cloner.
+func (f *XMLFormatter) Clone() Formatter {
+	c := *f
+	return &c
+}
+
+// SetTimeFormat implementa TimeFormatter.
+func (f *XMLFormatter) SetTimeFormat(tf kbx.TimeFormat) {
+	f.Time = tf
+}
+
+// Header implementa DocumentFormatter; vazio fora do modo documento.
+func (f *XMLFormatter) Header() []byte {
+	if !f.Document {
+		return nil
+	}
+	return []byte(xml.Header + "<" + f.root() + ">\n")
+}
+
+// Footer implementa DocumentFormatter; vazio fora do modo documento.
+func (f *XMLFormatter) Footer() []byte {
+	if !f.Document {
+		return nil
+	}
+	return []byte("</" + f.root() + ">\n")
+}
+
+func (f *XMLFormatter) root() string {
+	if f.Root == "" {
+		return "logs"
+	}
+	return f.Root
+}
+
 func (f *XMLFormatter) Format(e kbx.Entry) ([]byte, error) {
 	if err := e.Validate(); err != nil {
 		return nil, err
 	}
+	e = resolved(e)
 
-	var xmlOut []byte
-	var err error
-	eTmp := e.Clone().(kbx.LogzEntry).
-		WithFields(nil).
-		WithData(nil)
-	if f.Pretty {
-		xmlOut, err = xml.MarshalIndent(eTmp, eTmp.GetPrefix(), "  ")
-		if err != nil {
-			return nil, err
+	w := &xmlWriter{pretty: f.Pretty}
+	if f.Document {
+		w.depth = 1
+	}
+	w.open("entry", nil)
+	w.leaf("ts", nil, f.Time.Or(logfmtTimeFormat).Format(e.GetTimestamp()))
+	w.leaf("level", nil, string(e.GetLevel()))
+	w.leaf("msg", nil, e.GetMessage())
+	spanID, traceFlags := spanOf(e)
+	var src string
+	if se, ok := e.(interface{ GetSource() string }); ok {
+		src = se.GetSource()
+	}
+	for _, el := range [][2]string{
+		{"ctx", e.GetContext()},
+		{"src", src},
+		{"trace", e.GetTraceID()},
+		{"span_id", spanID},
+		{"trace_flags", traceFlags},
+		{"caller", e.GetCaller()},
+	} {
+		if el[1] != "" {
+			w.leaf(el[0], nil, el[1])
 		}
-	} else {
-		xmlOut, err = xml.Marshal(e)
-		if err != nil {
-			return nil, err
+	}
+	if sev := e.GetLevel().Severity(); sev != 0 {
+		w.leaf("sev", nil, strconv.Itoa(sev))
+	}
+	if ee, ok := e.(interface{ GetError() error }); ok && ee.GetError() != nil {
+		w.leaf("error", [][2]string{{"type", fmt.Sprintf("%T", ee.GetError())}}, ee.GetError().Error())
+	}
+	if tags := e.GetTags(); len(tags) > 0 {
+		keys := make([]string, 0, len(tags))
+		for k := range tags {
+			keys = append(keys, k)
 		}
+		sort.Strings(keys)
+		w.open("tags", nil)
+		for _, k := range keys {
+			w.leaf("tag", [][2]string{{"name", k}}, tags[k])
+		}
+		w.close("tags")
+	}
+	if fields := e.GetFields(); len(fields) > 0 {
+		w.open("fields", nil)
+		for _, k := range sortedFieldKeys(fields) {
+			w.value("field", k, fields[k], 0)
+		}
+		w.close("fields")
+	}
+	w.close("entry")
+	return w.buf.Bytes(), nil
+}
+
+func sortedFieldKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// xmlMaxDepth limita o aninhamento de fields.
+const xmlMaxDepth = 16
+
+// xmlWriter monta o XML à mão: a ordem dos elementos é fixa e os
+// atributos (name/type) não cabem no encoding/xml sem structs por tipo.
+type xmlWriter struct {
+	buf    bytes.Buffer
+	pretty bool
+	depth  int
+}
+
+func (w *xmlWriter) indent() {
+	if w.pretty {
+		w.buf.WriteString(strings.Repeat("  ", w.depth))
+	}
+}
+
+func (w *xmlWriter) newline() {
+	if w.pretty {
+		w.buf.WriteByte('\n')
+	}
+}
+
+func (w *xmlWriter) start(name string, attrs [][2]string) {
+	w.buf.WriteByte('<')
+	w.buf.WriteString(name)
+	for _, a := range attrs {
+		w.buf.WriteString(" " + a[0] + `="`)
+		_ = xml.EscapeText(&w.buf, []byte(a[1]))
+		w.buf.WriteByte('"')
 	}
+	w.buf.WriteByte('>')
+}
+
+func (w *xmlWriter) open(name string, attrs [][2]string) {
+	w.indent()
+	w.start(name, attrs)
+	w.newline()
+	w.depth++
+}
+
+func (w *xmlWriter) close(name string) {
+	w.depth--
+	w.indent()
+	w.buf.WriteString("</" + name + ">")
+	w.newline()
+}
+
+func (w *xmlWriter) leaf(name string, attrs [][2]string, text string) {
+	w.indent()
+	w.start(name, attrs)
+	_ = xml.EscapeText(&w.buf, []byte(text))
+	w.buf.WriteString("</" + name + ">")
+	w.newline()
+}
 
-	return xmlOut, nil
+// value escreve um field (ou item de lista, com name vazio) com o tipo.
+// Mapas e listas viram elementos filhos; structs passam por
+// encoding/json.
+func (w *xmlWriter) value(el, name string, v any, depth int) {
+	attrs := func(typ string) [][2]string {
+		if el == "item" {
+			return [][2]string{{"type", typ}}
+		}
+		return [][2]string{{"name", name}, {"type", typ}}
+	}
+	if depth >= xmlMaxDepth {
+		w.leaf(el, attrs("string"), fmt.Sprint(v))
+		return
+	}
+	switch x := v.(type) {
+	case nil:
+		w.leaf(el, attrs("null"), "")
+		return
+	case string:
+		w.leaf(el, attrs("string"), x)
+		return
+	case bool:
+		w.leaf(el, attrs("bool"), strconv.FormatBool(x))
+		return
+	case []byte:
+		w.leaf(el, attrs("bytes"), base64.StdEncoding.EncodeToString(x))
+		return
+	case time.Time:
+		w.leaf(el, attrs("time"), x.Format(time.RFC3339Nano))
+		return
+	case time.Duration:
+		w.leaf(el, attrs("duration"), x.String())
+		return
+	case json.Number:
+		w.leaf(el, attrs("number"), x.String())
+		return
+	case error:
+		w.leaf(el, attrs("error"), x.Error())
+		return
+	case fmt.Stringer:
+		w.leaf(el, attrs("string"), x.String())
+		return
+	}
 
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		w.leaf(el, attrs("int"), strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		w.leaf(el, attrs("uint"), strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		w.leaf(el, attrs("float"), strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()))
+	case reflect.String:
+		w.leaf(el, attrs("string"), rv.String())
+	case reflect.Bool:
+		w.leaf(el, attrs("bool"), strconv.FormatBool(rv.Bool()))
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			w.value(el, name, nil, depth)
+			return
+		}
+		w.value(el, name, rv.Elem().Interface(), depth+1)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			w.value(el, name, nil, depth)
+			return
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			w.value(el, name, b, depth)
+			return
+		}
+		w.open(el, attrs("list"))
+		for i := 0; i < rv.Len(); i++ {
+			w.value("item", "", rv.Index(i).Interface(), depth+1)
+		}
+		w.close(el)
+	case reflect.Map:
+		if rv.IsNil() {
+			w.value(el, name, nil, depth)
+			return
+		}
+		m := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = iter.Value().Interface()
+		}
+		w.open(el, attrs("map"))
+		for _, k := range sortedFieldKeys(m) {
+			w.value("field", k, m[k], depth+1)
+		}
+		w.close(el)
+	case reflect.Struct:
+		b, err := json.Marshal(v)
+		var generic any
+		if err == nil {
+			dec := json.NewDecoder(bytes.NewReader(b))
+			dec.UseNumber()
+			err = dec.Decode(&generic)
+		}
+		if err != nil {
+			w.leaf(el, attrs("string"), fmt.Sprintf("%+v", v))
+			return
+		}
+		w.value(el, name, generic, depth+1)
+	default:
+		w.leaf(el, attrs("string"), fmt.Sprint(v))
+	}
 }
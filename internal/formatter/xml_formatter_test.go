@@ -0,0 +1,116 @@
+package formatter_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/internal/writer"
+)
+
+type xmlField struct {
+	Name   string     `xml:"name,attr"`
+	Type   string     `xml:"type,attr"`
+	Text   string     `xml:",chardata"`
+	Fields []xmlField `xml:"field"`
+	Items  []xmlField `xml:"item"`
+}
+
+type xmlEntry struct {
+	Level  string     `xml:"level"`
+	Msg    string     `xml:"msg"`
+	Error  string     `xml:"error"`
+	Tags   []xmlField `xml:"tags>tag"`
+	Fields []xmlField `xml:"fields>field"`
+}
+
+type xmlDoc struct {
+	XMLName xml.Name   `xml:"app"`
+	Entries []xmlEntry `xml:"entry"`
+}
+
+// No modo documento, o DocumentWriter produz um XML válido com tipos,
+// nomes de field arbitrários e texto escapado.
+func TestXMLDocument(t *testing.T) {
+	for _, pretty := range []bool{false, true} {
+		f := &formatter.XMLFormatter{Pretty: pretty, Document: true, Root: "app"}
+		var out bytes.Buffer
+		d := writer.NewDocumentWriter(&out, f.Header(), f.Footer())
+
+		e := consoleEntry(kbx.LevelWarn, `a < b & "c"`, consoleTime)
+		e.WithFields(map[string]any{
+			"weird key<>": "v",
+			"n":           3,
+			"ok":          true,
+			"list":        []any{1.5, "x"},
+			"nested":      map[string]any{"in": nil},
+			"raw":         []byte("hi"),
+		}).WithError(errors.New("boom"))
+		e.Tags = map[string]string{"env": "prod"}
+		for _, entry := range []kbx.Entry{e, consoleEntry(kbx.LevelInfo, "second", consoleTime)} {
+			b, err := f.Format(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d.Write(b)
+		}
+		if err := d.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		var doc xmlDoc
+		if err := xml.Unmarshal(out.Bytes(), &doc); err != nil {
+			t.Fatalf("pretty=%v: %v\n%s", pretty, err, out.String())
+		}
+		if len(doc.Entries) != 2 {
+			t.Fatalf("pretty=%v: %d entries", pretty, len(doc.Entries))
+		}
+		got := doc.Entries[0]
+		if got.Level != "warn" || got.Msg != `a < b & "c"` || got.Error != "boom" {
+			t.Errorf("pretty=%v: %+v", pretty, got)
+		}
+		if len(got.Tags) != 1 || got.Tags[0].Name != "env" || got.Tags[0].Text != "prod" {
+			t.Errorf("tags: %+v", got.Tags)
+		}
+		types := map[string]string{}
+		for _, fl := range got.Fields {
+			types[fl.Name] = fl.Type
+		}
+		for name, typ := range map[string]string{
+			"weird key<>": "string",
+			"n":           "int",
+			"ok":          "bool",
+			"list":        "list",
+			"nested":      "map",
+			"raw":         "bytes",
+		} {
+			if types[name] != typ {
+				t.Errorf("pretty=%v: field %q type %q, want %q", pretty, name, types[name], typ)
+			}
+		}
+	}
+}
+
+// Fora do modo documento não há header/footer e cada entry é um
+// fragmento numa linha.
+func TestXMLFragment(t *testing.T) {
+	f := formatter.NewXMLFormatter(false).(*formatter.XMLFormatter)
+	if f.Header() != nil || f.Footer() != nil {
+		t.Error("header/footer outside document mode")
+	}
+	b, err := f.Format(consoleEntry(kbx.LevelInfo, "hi", consoleTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "\n") || !strings.HasPrefix(string(b), "<entry>") {
+		t.Errorf("got %q", b)
+	}
+	def := formatter.NewXMLDocumentFormatter(false).(*formatter.XMLFormatter)
+	if !strings.HasSuffix(string(def.Header()), "<logs>\n") || string(def.Footer()) != "</logs>\n" {
+		t.Errorf("default root: %q %q", def.Header(), def.Footer())
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 type YamlFormatter struct {
 	Pretty bool
+	Time   kbx.TimeFormat
 }
 
 func NewYamlFormatter(pretty bool) Formatter {
@@ -18,15 +19,24 @@ func (f *YamlFormatter) Name() string {
 }
 
 type yamlOutput struct {
-	Entries []kbx.Entry `yaml:"entries"`
+	Entries []any `yaml:"entries"`
 }
 
 func (f *YamlFormatter) Format(e kbx.Entry) ([]byte, error) {
 	if err := e.Validate(); err != nil {
 		return nil, err
 	}
-	if f.Pretty {
-		return yaml.Marshal(yamlOutput{Entries: []kbx.Entry{e}})
-	}
-	return yaml.Marshal(yamlOutput{Entries: []kbx.Entry{e}})
+	e = resolved(e)
+	return yaml.Marshal(yamlOutput{Entries: []any{timed(e, f.Time)}})
+}
+
+// Clone implementa Cloner.
+func (f *YamlFormatter) Clone() Formatter {
+	c := *f
+	return &c
+}
+
+// SetTimeFormat implementa TimeFormatter.
+func (f *YamlFormatter) SetTimeFormat(tf kbx.TimeFormat) {
+	f.Time = tf
 }
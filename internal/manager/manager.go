@@ -3,6 +3,7 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"io"
 	"sync"
 	"sync/atomic"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/kubex-ecosystem/logz/interfaces"
 	"github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/formatter"
 	"github.com/kubex-ecosystem/logz/internal/module/kbx"
 )
 
@@ -71,6 +73,10 @@ func (m *Manager) Process(ctx context.Context, entry *core.Entry) error {
 
 	// ---- Stage 3: format ----------------------------------------------------
 	b, err := m.stageFormat(entry)
+	if errors.Is(err, formatter.ErrDropped) {
+		// descartada por filtro do DynamicFormatter: nada a escrever.
+		return m.done()
+	}
 	if err != nil {
 		m.advance(control.StepFailed)
 		return err
@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+type formatterFunc func(e *core.Entry) ([]byte, error)
+
+func (f formatterFunc) Format(e *core.Entry) ([]byte, error) { return f(e) }
+
+// Entry descartada pelo filtro do formatter não é escrita nem é erro;
+// outros erros do formatter continuam subindo.
+func TestProcessDroppedEntry(t *testing.T) {
+	var buf bytes.Buffer
+	m := &Manager{
+		writer: &buf,
+		formatter: formatterFunc(func(e *core.Entry) ([]byte, error) {
+			if e.GetMessage() == "drop" {
+				return nil, formatter.ErrDropped
+			}
+			return []byte(e.GetMessage()), nil
+		}),
+	}
+
+	newEntry := func(msg string) *core.Entry {
+		return core.NewLogzEntry(kbx.LevelInfo).WithMessage(msg).(*core.Entry)
+	}
+	if err := m.Process(context.Background(), newEntry("drop")); err != nil {
+		t.Fatalf("dropped entry: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("dropped entry was written: %q", buf.String())
+	}
+	if err := m.Process(context.Background(), newEntry("keep")); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "keep\n" {
+		t.Errorf("got %q", buf.String())
+	}
+
+	boom := errors.New("boom")
+	m.formatter = formatterFunc(func(*core.Entry) ([]byte, error) { return nil, boom })
+	if err := m.Process(context.Background(), newEntry("x")); !errors.Is(err, boom) {
+		t.Errorf("got %v, want %v", err, boom)
+	}
+}
@@ -0,0 +1,187 @@
+// Package middleware integra o logz com servidores e clientes de rede.
+package middleware
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// HTTPOptions ajusta o middleware de access log.
+type HTTPOptions struct {
+	// RequestIDHeader é lido da requisição e devolvido na resposta.
+	// Padrão: X-Request-ID.
+	RequestIDHeader string
+	// SkipPaths não geram access log (ex: /healthz). O logger filho
+	// continua disponível no contexto.
+	SkipPaths []string
+	// NewRequestID gera o ID quando a requisição não traz um. Padrão: UUID.
+	NewRequestID func() string
+}
+
+// HTTP devolve um middleware net/http que:
+//   - lê (ou gera) o request ID e o trace (traceparent / B3 / span OTel);
+//   - injeta no contexto um logger filho com request_id, method, path e
+//     o trace, recuperável com core.LoggerFromContext;
+//   - ao fim, loga method, path, status, bytes, duração, remote addr e user
+//     agent, com nível pelo status (5xx error, 4xx warn, resto info).
+func HTTP(logger *C.LoggerZ[kbx.Entry], opts *HTTPOptions) func(http.Handler) http.Handler {
+	if opts == nil {
+		opts = &HTTPOptions{}
+	}
+	header := kbx.GetValueOrDefaultSimple(opts.RequestIDHeader, DefaultRequestIDHeader)
+	newID := opts.NewRequestID
+	if newID == nil {
+		newID = func() string { return uuid.NewString() }
+	}
+	skip := make(map[string]bool, len(opts.SkipPaths))
+	for _, p := range opts.SkipPaths {
+		skip[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			reqID := strings.TrimSpace(r.Header.Get(header))
+			if reqID == "" {
+				reqID = newID()
+			}
+			w.Header().Set(header, reqID)
+
+			tc := TraceFromRequest(r)
+			ctx := kbx.ContextWithTrace(r.Context(), tc)
+
+			reqLogger := logger.With(map[string]any{
+				"request_id": reqID,
+				"method":     r.Method,
+				"path":       r.URL.Path,
+			}).WithTrace(tc)
+			ctx = C.ContextWithLogger(ctx, reqLogger)
+
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			if skip[r.URL.Path] {
+				return
+			}
+			status := rec.Status()
+			entry := C.NewLogzEntry(StatusLevel(status)).
+				WithMessage(fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, status)).
+				WithFields(map[string]any{
+					"status":      status,
+					"bytes":       rec.bytes,
+					"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+					"remote_addr": remoteAddr(r),
+					"user_agent":  r.UserAgent(),
+				})
+			_ = reqLogger.Log(entry.GetLevel(), entry)
+		})
+	}
+}
+
+// StatusLevel escolhe o nível do access log pela classe do status.
+func StatusLevel(status int) kbx.Level {
+	switch {
+	case status >= 500:
+		return kbx.LevelError
+	case status >= 400:
+		return kbx.LevelWarn
+	default:
+		return kbx.LevelInfo
+	}
+}
+
+// TraceFromRequest extrai o trace da requisição: um span OTel já presente
+// no contexto, o header traceparent (W3C) ou os headers B3. Sem nenhum
+// deles, gera um trace novo. O span_id é sempre novo: ele identifica este
+// servidor, e o recebido vira o "pai".
+func TraceFromRequest(r *http.Request) kbx.TraceContext {
+	if tc, ok := kbx.TraceFromContext(r.Context()); ok {
+		return tc
+	}
+	if tc, err := kbx.ParseTraceparent(r.Header.Get(kbx.TraceparentHeader)); err == nil {
+		return tc.Child()
+	}
+	if traceID := strings.ToLower(r.Header.Get("X-B3-TraceId")); traceID != "" {
+		if len(traceID) == 16 {
+			traceID = strings.Repeat("0", 16) + traceID
+		}
+		tc := kbx.TraceContext{TraceID: traceID}.Child()
+		if r.Header.Get("X-B3-Sampled") == "1" {
+			tc.TraceFlags = 0x01
+		}
+		if tc.IsValid() {
+			return tc
+		}
+	}
+	return kbx.NewTraceContext()
+}
+
+func remoteAddr(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// statusRecorder captura status e bytes escritos sem esconder as
+// interfaces opcionais do ResponseWriter original.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += int64(n)
+	return n, err
+}
+
+// Status devolve o status enviado (200 se o handler não escreveu nada).
+func (s *statusRecorder) Status() int {
+	if s.status == 0 {
+		return http.StatusOK
+	}
+	return s.status
+}
+
+// Unwrap permite que http.ResponseController alcance o writer original.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := s.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("logz: response writer does not support hijacking")
+}
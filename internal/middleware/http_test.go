@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/middleware"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/logztest"
+)
+
+func TestHTTPAccessLog(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	h := middleware.HTTP(logger, &middleware.HTTPOptions{
+		SkipPaths:    []string{"/healthz"},
+		NewRequestID: func() string { return "req-1" },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l, ok := C.LoggerFromContext[kbx.Entry](r.Context()); ok {
+			_ = l.Log(kbx.LevelInfo, C.NewLogzEntry(kbx.LevelInfo).WithMessage("inside"))
+		}
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, "hello")
+	}))
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	req.Header.Set(kbx.TraceparentHeader, parent)
+	req.Header.Set("User-Agent", "test")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Header().Get(middleware.DefaultRequestIDHeader) != "req-1" {
+		t.Errorf("request id header = %q", rec.Header().Get(middleware.DefaultRequestIDHeader))
+	}
+
+	logs := obs.TakeAll()
+	logztest.AssertCount(t, logs, 2)
+	inside := logztest.AssertLogged(t, logs, "inside")
+	logztest.AssertField(t, inside, "request_id", "req-1")
+	logztest.AssertField(t, inside, "path", "/hello")
+
+	access := logztest.AssertLogged(t, logs, "GET /hello 200")
+	logztest.AssertField(t, access, "status", 200)
+	logztest.AssertField(t, access, "bytes", 5)
+	logztest.AssertField(t, access, "user_agent", "test")
+	if access.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || access.SpanID == "00f067aa0ba902b7" {
+		t.Errorf("trace = %s/%s", access.TraceID, access.SpanID)
+	}
+
+	// 4xx sai em warn; o ID recebido é mantido.
+	req = httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.Header.Set(middleware.DefaultRequestIDHeader, "abc")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	miss := logztest.AssertLogged(t, obs.TakeAll(), "GET /missing 404")
+	if miss.Level != kbx.LevelWarn {
+		t.Errorf("level = %s", miss.Level)
+	}
+	logztest.AssertField(t, miss, "request_id", "abc")
+
+	// caminhos ignorados não geram access log.
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	logztest.AssertNotLogged(t, obs.TakeAll(), "GET /healthz 200")
+}
+
+func TestTraceFromRequestB3(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-B3-TraceId", "a3ce929d0e0e4736")
+	req.Header.Set("X-B3-Sampled", "1")
+	tc := middleware.TraceFromRequest(req)
+	if tc.TraceID != "0000000000000000a3ce929d0e0e4736" || !tc.Sampled() || !tc.IsValid() {
+		t.Errorf("tc = %+v", tc)
+	}
+	if tc := middleware.TraceFromRequest(httptest.NewRequest(http.MethodGet, "/", nil)); !tc.IsValid() {
+		t.Errorf("new trace = %+v", tc)
+	}
+}
+
+func TestStatusLevel(t *testing.T) {
+	for status, want := range map[int]kbx.Level{200: kbx.LevelInfo, 302: kbx.LevelInfo, 404: kbx.LevelWarn, 503: kbx.LevelError} {
+		if got := middleware.StatusLevel(status); got != want {
+			t.Errorf("StatusLevel(%d) = %s, want %s", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/internal/redact"
+)
+
+const (
+	defaultMaxBodyBytes = 4 << 10
+	// bodySlack é lido além do limite pra que um segredo cortado no limite
+	// ainda case com as regras do Redactor antes do truncamento.
+	bodySlack = 1 << 10
+)
+
+// TransportOptions ajusta o RoundTripper de log.
+type TransportOptions struct {
+	// MaxRetries repete requisições idempotentes que falharam na rede ou
+	// receberam 502/503/504. Padrão: 0 (sem retry).
+	MaxRetries int
+	// Backoff entre tentativas (dobra a cada uma). Padrão: 200ms.
+	Backoff time.Duration
+	// LogBodies despeja os corpos de requisição e resposta em debug,
+	// truncados em MaxBodyBytes e passados pelo Redactor.
+	LogBodies    bool
+	MaxBodyBytes int
+	// Redactor usado nos corpos e na URL. Padrão: regras padrão do logz.
+	Redactor *redact.Redactor
+}
+
+// Transport é um http.RoundTripper que loga as requisições de saída.
+type Transport struct {
+	Base   http.RoundTripper
+	Logger *C.LoggerZ[kbx.Entry]
+	opts   TransportOptions
+	red    *redact.Redactor
+	sleep  func(time.Duration)
+}
+
+// NewTransport embrulha base (nil = http.DefaultTransport). Se a requisição
+// carrega um logger no contexto (ex: o filho criado pelo middleware HTTP),
+// ele tem prioridade sobre logger.
+func NewTransport(base http.RoundTripper, logger *C.LoggerZ[kbx.Entry], opts *TransportOptions) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{Base: base, Logger: logger, sleep: time.Sleep}
+	if opts != nil {
+		t.opts = *opts
+	}
+	if t.opts.MaxBodyBytes <= 0 {
+		t.opts.MaxBodyBytes = defaultMaxBodyBytes
+	}
+	if t.opts.Backoff <= 0 {
+		t.opts.Backoff = 200 * time.Millisecond
+	}
+	t.red = t.opts.Redactor
+	if t.red == nil {
+		t.red, _ = redact.New(nil)
+	}
+	return t
+}
+
+// RoundTrip implementa http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := t.Logger
+	if l, ok := C.LoggerFromContext[kbx.Entry](req.Context()); ok {
+		logger = l
+	}
+
+	// RoundTrippers não podem alterar a requisição recebida.
+	out := req.Clone(req.Context())
+	if out.Header.Get(kbx.TraceparentHeader) == "" {
+		if tc, ok := kbx.TraceFromContext(req.Context()); ok {
+			// mesmo trace, span novo: esta chamada é filha do span corrente.
+			out.Header.Set(kbx.TraceparentHeader, tc.Child().Traceparent())
+		}
+	}
+
+	dumpBodies := t.opts.LogBodies && logger != nil && logger.Enabled(kbx.LevelDebug)
+	var reqBody string
+	if dumpBodies {
+		reqBody = t.peekRequestBody(out)
+	}
+
+	start := time.Now()
+	var (
+		resp    *http.Response
+		err     error
+		retries int
+	)
+	for attempt := 0; ; attempt++ {
+		resp, err = t.Base.RoundTrip(out)
+		if attempt >= t.opts.MaxRetries || !retryable(out, resp, err) {
+			break
+		}
+		// sem corpo pra reenviar, fica a resposta (ou erro) desta tentativa:
+		// ela só é descartada quando a próxima pode mesmo acontecer.
+		if rewindBody(out) != nil {
+			break
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		retries++
+		t.sleep(t.opts.Backoff << attempt)
+	}
+	duration := time.Since(start)
+
+	if logger == nil {
+		return resp, err
+	}
+
+	fields := map[string]any{
+		"method":      out.Method,
+		"url":         t.redactURL(out.URL),
+		"host":        out.URL.Host,
+		"duration_ms": float64(duration.Microseconds()) / 1000,
+		"retries":     retries,
+	}
+	status := 0
+	lvl := kbx.LevelError
+	if resp != nil {
+		status = resp.StatusCode
+		fields["status"] = status
+		if resp.ContentLength >= 0 {
+			fields["bytes"] = resp.ContentLength
+		}
+		lvl = StatusLevel(status)
+	}
+	msg := fmt.Sprintf("http client %s %s%s %d", out.Method, out.URL.Host, out.URL.Path, status)
+	if resp == nil {
+		msg = fmt.Sprintf("http client %s %s%s failed", out.Method, out.URL.Host, out.URL.Path)
+	}
+	entry := C.NewLogzEntry(lvl).
+		WithMessage(msg).
+		WithFields(fields)
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	if tc, terr := kbx.ParseTraceparent(out.Header.Get(kbx.TraceparentHeader)); terr == nil {
+		entry = entry.(*C.Entry).WithTraceContext(tc)
+	}
+	_ = logger.Log(lvl, entry)
+
+	if dumpBodies {
+		dump := map[string]any{"method": out.Method, "url": fields["url"]}
+		if reqBody != "" {
+			dump["request_body"] = reqBody
+		}
+		if resp != nil {
+			if respBody := t.peekResponseBody(resp); respBody != "" {
+				dump["response_body"] = respBody
+			}
+		}
+		if len(dump) > 2 {
+			_ = logger.Log(kbx.LevelDebug, C.NewLogzEntry(kbx.LevelDebug).
+				WithMessage("http client bodies").
+				WithFields(dump))
+		}
+	}
+	return resp, err
+}
+
+// retryable: só métodos idempotentes, e só falha de rede ou 502/503/504.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func rewindBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if req.GetBody == nil {
+		return errors.New("logz: request body cannot be replayed")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+// peekRequestBody lê até MaxBodyBytes do corpo e o recoloca intacto.
+func (t *Transport) peekRequestBody(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+	head, rest, err := peek(req.Body, t.opts.MaxBodyBytes)
+	req.Body = rest
+	if err != nil {
+		return ""
+	}
+	return t.bodyString(head)
+}
+
+func (t *Transport) peekResponseBody(resp *http.Response) string {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return ""
+	}
+	head, rest, err := peek(resp.Body, t.opts.MaxBodyBytes)
+	resp.Body = rest
+	if err != nil {
+		return ""
+	}
+	return t.bodyString(head)
+}
+
+// bodyString redige ANTES de truncar, pra não deixar meio token à mostra.
+func (t *Transport) bodyString(head []byte) string {
+	s := t.red.String(string(head))
+	if len(head) > t.opts.MaxBodyBytes && len(s) > t.opts.MaxBodyBytes {
+		s = strings.ToValidUTF8(s[:t.opts.MaxBodyBytes], "") + "...(truncated)"
+	}
+	return s
+}
+
+// redactURL mascara a query (por nome de parâmetro e por padrão de valor).
+func (t *Transport) redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return t.red.String(u.Redacted())
+	}
+	clean := *u
+	q := clean.Query()
+	for k, vs := range q {
+		for i, v := range vs {
+			if out, drop := t.red.Value(k, v); drop {
+				vs[i] = ""
+			} else {
+				vs[i] = fmt.Sprint(out)
+			}
+		}
+	}
+	clean.RawQuery = q.Encode()
+	return t.red.String(clean.Redacted())
+}
+
+// peek lê até limit+bodySlack+1 bytes e devolve um corpo que reproduz o
+// original por inteiro.
+func peek(body io.ReadCloser, limit int) ([]byte, io.ReadCloser, error) {
+	head, err := io.ReadAll(io.LimitReader(body, int64(limit)+bodySlack+1))
+	rest := &readCloser{Reader: io.MultiReader(bytes.NewReader(head), body), Closer: body}
+	return head, rest, err
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
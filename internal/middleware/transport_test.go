@@ -0,0 +1,152 @@
+package middleware_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/middleware"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/logztest"
+)
+
+func TestTransportRetriesAndReplaysBody(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("attempt %d body = %q", calls.Load(), body)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	logger, obs := logztest.New(nil)
+	tr := middleware.NewTransport(nil, logger, &middleware.TransportOptions{MaxRetries: 3, Backoff: time.Millisecond})
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/items/1?token=s3cret", strings.NewReader("payload"))
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if b, _ := io.ReadAll(resp.Body); string(b) != "ok" || calls.Load() != 3 {
+		t.Fatalf("body=%q calls=%d", b, calls.Load())
+	}
+
+	logs := obs.Logs()
+	logztest.AssertCount(t, logs, 1)
+	e := logs[0]
+	logztest.AssertField(t, e, "retries", 2)
+	logztest.AssertField(t, e, "status", 200)
+	if u := e.Fields["url"].(string); strings.Contains(u, "s3cret") {
+		t.Errorf("url not redacted: %s", u)
+	}
+}
+
+func TestTransportDoesNotRetryPost(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	tr := middleware.NewTransport(nil, nil, &middleware.TransportOptions{MaxRetries: 3, Backoff: time.Millisecond})
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("x"))
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || calls.Load() != 1 {
+		t.Errorf("status=%d calls=%d", resp.StatusCode, calls.Load())
+	}
+}
+
+// Sem como reenviar o corpo, a resposta da última tentativa volta intacta.
+func TestTransportRewindFailureKeepsResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, "try later")
+	}))
+	defer srv.Close()
+
+	tr := middleware.NewTransport(nil, nil, &middleware.TransportOptions{MaxRetries: 2, Backoff: time.Millisecond})
+	req, _ := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("x"))
+	req.GetBody = func() (io.ReadCloser, error) { return nil, errors.New("gone") }
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil || string(b) != "try later" {
+		t.Errorf("body = %q, %v", b, err)
+	}
+}
+
+func TestTransportPropagatesTrace(t *testing.T) {
+	var got atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.Store(r.Header.Get(kbx.TraceparentHeader))
+	}))
+	defer srv.Close()
+
+	logger, obs := logztest.New(nil)
+	tc := kbx.NewTraceContext()
+	req, _ := http.NewRequestWithContext(kbx.ContextWithTrace(t.Context(), tc), http.MethodGet, srv.URL, nil)
+	resp, err := middleware.NewTransport(nil, logger, nil).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	sent, err := kbx.ParseTraceparent(got.Load().(string))
+	if err != nil || sent.TraceID != tc.TraceID || sent.SpanID == tc.SpanID {
+		t.Fatalf("traceparent = %+v, %v", sent, err)
+	}
+	if req.Header.Get(kbx.TraceparentHeader) != "" {
+		t.Error("caller request was modified")
+	}
+	e := obs.Logs()[0]
+	if e.TraceID != tc.TraceID || e.SpanID != sent.SpanID {
+		t.Errorf("entry trace = %s/%s", e.TraceID, e.SpanID)
+	}
+}
+
+func TestTransportBodyDump(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"token":"abc","note":"`+strings.Repeat("x", 100)+`"}`)
+	}))
+	defer srv.Close()
+
+	logger, obs := logztest.New(nil)
+	tr := middleware.NewTransport(nil, logger, &middleware.TransportOptions{LogBodies: true, MaxBodyBytes: 32})
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("password=x Bearer abc.def"))
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if len(full) < 100 {
+		t.Errorf("response body was consumed: %q", full)
+	}
+
+	dump := logztest.AssertLogged(t, obs.Logs(), "http client bodies")
+	if rb := dump.Fields["request_body"].(string); strings.Contains(rb, "abc.def") {
+		t.Errorf("request body not redacted: %s", rb)
+	}
+	if rb := dump.Fields["response_body"].(string); !strings.HasSuffix(rb, "...(truncated)") {
+		t.Errorf("response body not truncated: %s", rb)
+	}
+}
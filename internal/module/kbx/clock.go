@@ -0,0 +1,18 @@
+package kbx
+
+import "time"
+
+// Clock fornece o "agora" do logger. Trocar o relógio permite timestamps
+// determinísticos (testes golden) ou um relógio sincronizado externo.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapta uma função a Clock.
+type ClockFunc func() time.Time
+
+// Now implementa Clock.
+func (f ClockFunc) Now() time.Time { return f() }
+
+// SystemClock é o relógio padrão (time.Now).
+var SystemClock Clock = ClockFunc(time.Now)
@@ -22,10 +22,33 @@ type LogzFormatOptions struct {
 	MaxLevel Level     `json:"max_level,omitempty" yaml:"max_level,omitempty" mapstructure:"max_level,omitempty"`
 	Level    Level     `json:"level,omitempty" yaml:"level,omitempty" mapstructure:"level,omitempty"`
 	Format   string    `json:"format,omitempty" yaml:"format,omitempty" mapstructure:"format,omitempty"`
+
+	// TimeFormat é o layout do timestamp: nome ("rfc3339nano", "unixms",
+	// "datetime"...) ou layout Go. TimeZone: "UTC", "Local" ou IANA.
+	// Vazios = padrão de cada formatter. Env: LOGZ_TIME_FORMAT / LOGZ_TIME_ZONE.
+	TimeFormat string `json:"time_format,omitempty" yaml:"time_format,omitempty" mapstructure:"time_format,omitempty"`
+	TimeZone   string `json:"time_zone,omitempty" yaml:"time_zone,omitempty" mapstructure:"time_zone,omitempty"`
+
+	// Theme é o nome do tema de cores/ícones dos formatters text e pretty
+	// ("default", "ascii", "high-contrast", "monochrome" ou um registrado).
+	// Env: LOGZ_THEME.
+	Theme string `json:"theme,omitempty" yaml:"theme,omitempty" mapstructure:"theme,omitempty"`
+
+	// Dynamic são as regras do formatter "dynamic": nível mínimo -> nome
+	// do formato, e "default" pro resto. Ex: {"error": "json",
+	// "info": "text", "default": "minimal"}. Env: LOGZ_DYNAMIC_FORMAT
+	// ("error=json,info=text,default=minimal"). As regras se somam às
+	// padrão e às de WhenLevel; a de mesmo nível substitui.
+	Dynamic map[string]string `json:"dynamic,omitempty" yaml:"dynamic,omitempty" mapstructure:"dynamic,omitempty"`
+
+	// Security usa as chaves de control.FromLegacyMap ("sanitize", ...).
+	Security map[string]bool `json:"security,omitempty" yaml:"security,omitempty" mapstructure:"security,omitempty"`
 }
 
 type LogzOutputOptions struct {
 	// Output options
+	// OutputTTY força a detecção de terminal da saída (cores/ícones nos
+	// formatters text e pretty); nil = detecta. Ver DetectTerm.
 	OutputTTY    *bool   `json:"output_tty,omitempty" yaml:"output_tty,omitempty" mapstructure:"output_tty,omitempty"`
 	OutputFile   *string `json:"output_file,omitempty" yaml:"output_file,omitempty" mapstructure:"output_file,omitempty"`
 	OutputSyslog *string `json:"output_syslog,omitempty" yaml:"output_syslog,omitempty" mapstructure:"output_syslog,omitempty"`
@@ -53,3 +76,66 @@ type LogzBufferingOptions struct {
 type LogzConfig = InitArgs
 
 func NewConfig() *LogzConfig { return LoggerArgs }
+
+// RedactRule descreve uma regra de redação.
+//
+// Keys casa com o NOME do campo/tag (glob, case-insensitive: "password",
+// "*_secret"); Pattern casa com o VALOR (regex aplicada em mensagem, fields,
+// tags e texto do erro). Strategy: mask | hash | truncate | drop.
+// Validate filtra falsos positivos do Pattern: "luhn" só redige números que
+// passam no dígito verificador de cartão.
+type RedactRule struct {
+	Name        string   `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name,omitempty"`
+	Keys        []string `json:"keys,omitempty" yaml:"keys,omitempty" mapstructure:"keys,omitempty"`
+	Pattern     string   `json:"pattern,omitempty" yaml:"pattern,omitempty" mapstructure:"pattern,omitempty"`
+	Strategy    string   `json:"strategy,omitempty" yaml:"strategy,omitempty" mapstructure:"strategy,omitempty"`
+	Keep        int      `json:"keep,omitempty" yaml:"keep,omitempty" mapstructure:"keep,omitempty"`
+	Replacement string   `json:"replacement,omitempty" yaml:"replacement,omitempty" mapstructure:"replacement,omitempty"`
+	Validate    string   `json:"validate,omitempty" yaml:"validate,omitempty" mapstructure:"validate,omitempty"`
+}
+
+type LogzRedactOptions struct {
+	// Redaction
+	Enabled         *bool        `json:"enabled,omitempty" yaml:"enabled,omitempty" mapstructure:"enabled,omitempty"`
+	Salt            string       `json:"salt,omitempty" yaml:"salt,omitempty" mapstructure:"salt,omitempty"`
+	DisableDefaults bool         `json:"disable_defaults,omitempty" yaml:"disable_defaults,omitempty" mapstructure:"disable_defaults,omitempty"`
+	Rules           []RedactRule `json:"rules,omitempty" yaml:"rules,omitempty" mapstructure:"rules,omitempty"`
+}
+
+// SamplingRate é um token bucket: Rate entries por segundo, rajadas de Burst.
+type SamplingRate struct {
+	Rate  float64 `json:"rate,omitempty" yaml:"rate,omitempty" mapstructure:"rate,omitempty"`
+	Burst int     `json:"burst,omitempty" yaml:"burst,omitempty" mapstructure:"burst,omitempty"`
+}
+
+type LogzSamplingOptions struct {
+	// Sampling / rate limiting
+	Enabled         *bool                   `json:"enabled,omitempty" yaml:"enabled,omitempty" mapstructure:"enabled,omitempty"`
+	Interval        string                  `json:"interval,omitempty" yaml:"interval,omitempty" mapstructure:"interval,omitempty"`
+	First           int                     `json:"first,omitempty" yaml:"first,omitempty" mapstructure:"first,omitempty"`
+	Thereafter      int                     `json:"thereafter,omitempty" yaml:"thereafter,omitempty" mapstructure:"thereafter,omitempty"`
+	RateLimits      map[string]SamplingRate `json:"rate_limits,omitempty" yaml:"rate_limits,omitempty" mapstructure:"rate_limits,omitempty"`
+	Probabilities   map[string]float64      `json:"probabilities,omitempty" yaml:"probabilities,omitempty" mapstructure:"probabilities,omitempty"`
+	SummaryInterval string                  `json:"summary_interval,omitempty" yaml:"summary_interval,omitempty" mapstructure:"summary_interval,omitempty"`
+}
+
+type LogzOTLPOptions struct {
+	// Exportação OTLP/HTTP de logs pra um OpenTelemetry Collector
+	Enabled       *bool             `json:"enabled,omitempty" yaml:"enabled,omitempty" mapstructure:"enabled,omitempty"`
+	Endpoint      string            `json:"endpoint,omitempty" yaml:"endpoint,omitempty" mapstructure:"endpoint,omitempty"`
+	Encoding      string            `json:"encoding,omitempty" yaml:"encoding,omitempty" mapstructure:"encoding,omitempty"`          // protobuf | json
+	Compression   string            `json:"compression,omitempty" yaml:"compression,omitempty" mapstructure:"compression,omitempty"` // gzip | none
+	Headers       map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" mapstructure:"headers,omitempty"`
+	Resource      map[string]string `json:"resource,omitempty" yaml:"resource,omitempty" mapstructure:"resource,omitempty"`
+	BatchSize     int               `json:"batch_size,omitempty" yaml:"batch_size,omitempty" mapstructure:"batch_size,omitempty"`
+	QueueSize     int               `json:"queue_size,omitempty" yaml:"queue_size,omitempty" mapstructure:"queue_size,omitempty"`
+	FlushInterval string            `json:"flush_interval,omitempty" yaml:"flush_interval,omitempty" mapstructure:"flush_interval,omitempty"`
+	Timeout       string            `json:"timeout,omitempty" yaml:"timeout,omitempty" mapstructure:"timeout,omitempty"`
+	MaxRetries    int               `json:"max_retries,omitempty" yaml:"max_retries,omitempty" mapstructure:"max_retries,omitempty"`
+}
+
+type LogzDedupOptions struct {
+	// Colapso de entries consecutivas idênticas
+	Enabled *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty" mapstructure:"enabled,omitempty"`
+	Window  string `json:"window,omitempty" yaml:"window,omitempty" mapstructure:"window,omitempty"`
+}
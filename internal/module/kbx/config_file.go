@@ -0,0 +1,76 @@
+package kbx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+
+	"github.com/kubex-ecosystem/logz/internal/writer"
+)
+
+// LoadConfigFile lê um arquivo de configuração (JSON ou YAML, pela extensão)
+// e devolve um InitArgs pronto pra NewLoggerOptions.
+//
+// O campo "output" é lido como string (stdout, stderr, caminho ou spec
+// com scheme, ver writer.Open) e convertido num writer, já que io.Writer
+// não é serializável; destino que não abre é erro.
+func LoadConfigFile(path string) (*InitArgs, error) {
+	path = os.ExpandEnv(GetValueOrDefaultSimple(path, DefaultConfigFile))
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("logz: reading config file %q: %w", path, err)
+	}
+	return ParseConfig(raw, filepath.Ext(path))
+}
+
+// ParseConfig decodifica o conteúdo de um arquivo de configuração.
+// ext é a extensão do arquivo (".json", ".yaml", ".yml").
+func ParseConfig(raw []byte, ext string) (*InitArgs, error) {
+	doc := map[string]any{}
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return nil, fmt.Errorf("logz: parsing yaml config: %w", err)
+		}
+	default:
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, fmt.Errorf("logz: parsing json config: %w", err)
+		}
+	}
+
+	output, _ := doc["output"].(string)
+	delete(doc, "output")
+
+	// YAML e JSON passam pelo mesmo caminho: map -> json -> InitArgs.
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("logz: normalizing config: %w", err)
+	}
+
+	cfg := &InitArgs{
+		ID:                   uuid.New(),
+		Messages:             []string{},
+		Metadata:             map[string]string{},
+		LogzGeneralOptions:   &LogzGeneralOptions{},
+		LogzFormatOptions:    &LogzFormatOptions{},
+		LogzOutputOptions:    &LogzOutputOptions{},
+		LogzRotatingOptions:  &LogzRotatingOptions{},
+		LogzBufferingOptions: &LogzBufferingOptions{},
+	}
+	if err := json.Unmarshal(normalized, cfg); err != nil {
+		return nil, fmt.Errorf("logz: decoding config: %w", err)
+	}
+	if output != "" {
+		w, err := writer.Open(output)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Output = w
+	}
+	return cfg, nil
+}
@@ -67,6 +67,11 @@ type InitArgs struct {
 
 	*LogzBufferingOptions `json:",inline" yaml:",inline" mapstructure:",squash"`
 
+	Redact   *LogzRedactOptions   `json:"redact,omitempty" yaml:"redact,omitempty" mapstructure:"redact,omitempty"`
+	Sampling *LogzSamplingOptions `json:"sampling,omitempty" yaml:"sampling,omitempty" mapstructure:"sampling,omitempty"`
+	Dedup    *LogzDedupOptions    `json:"dedup,omitempty" yaml:"dedup,omitempty" mapstructure:"dedup,omitempty"`
+	OTLP     *LogzOTLPOptions     `json:"otlp,omitempty" yaml:"otlp,omitempty" mapstructure:"otlp,omitempty"`
+
 	// *LogzAdvancedOptions `json:",inline" yaml:",inline" mapstructure:",squash"`
 }
 
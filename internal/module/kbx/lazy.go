@@ -0,0 +1,110 @@
+package kbx
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// maxResolveDepth limita a resolução encadeada de LogValuers
+// (um LogValuer que devolve outro LogValuer, e assim por diante).
+const maxResolveDepth = 16
+
+// LogValuer é implementado por valores que sabem produzir a sua própria
+// representação de log. O método só é chamado quando a entry passou pelo
+// filtro de nível e está a caminho do formatter.
+type LogValuer interface {
+	LogValue() any
+}
+
+// Lazy embrulha uma função cara de ser computada (payloads de debug, dumps,
+// etc) para que ela só seja avaliada se a entry for realmente escrita.
+//
+//	logger.Debug(map[string]any{
+//		"state": kbx.Lazy(func() any { return dumpState() }),
+//	})
+type Lazy func() any
+
+// LogValue implementa LogValuer.
+func (f Lazy) LogValue() any {
+	if f == nil {
+		return nil
+	}
+	return f()
+}
+
+// ResolveValue resolve v enquanto ele for um LogValuer (nosso ou do slog).
+// Panics dentro de LogValue são capturados e viram o texto do erro, para
+// que um valor quebrado não derrube o pipeline de log.
+func ResolveValue(v any) (out any) {
+	defer func() {
+		if r := recover(); r != nil {
+			out = fmt.Sprintf("!PANIC: %v", r)
+		}
+	}()
+
+	for i := 0; i < maxResolveDepth; i++ {
+		switch lv := v.(type) {
+		case LogValuer:
+			v = lv.LogValue()
+		case slog.Value:
+			v = slogValueToAny(lv.Resolve())
+		case slog.LogValuer:
+			v = lv.LogValue()
+		case map[string]any:
+			return ResolveFields(lv)
+		default:
+			return v
+		}
+	}
+	return v
+}
+
+// ResolveFields devolve fields com os valores preguiçosos resolvidos, em
+// todos os níveis. fields (e os mapas aninhados) nunca são alterados: são
+// do chamador, que pode logar o mesmo mapa de novo ou compartilhá-lo entre
+// goroutines. Havendo algo a resolver, o resultado é uma cópia de cada
+// mapa no caminho até os preguiçosos; não havendo, é o próprio fields, então
+// chamar de novo (um formatter depois do logger, por exemplo) é barato.
+func ResolveFields(fields map[string]any) map[string]any {
+	if !HasLazy(fields) {
+		return fields
+	}
+	out := make(map[string]any, len(fields))
+	for k, v := range fields {
+		out[k] = ResolveValue(v)
+	}
+	return out
+}
+
+// HasLazy diz se fields tem, em algum nível, um valor a resolver.
+func HasLazy(fields map[string]any) bool {
+	return hasLazy(fields, 0)
+}
+
+func hasLazy(fields map[string]any, depth int) bool {
+	if depth >= maxResolveDepth {
+		return false
+	}
+	for _, v := range fields {
+		switch tv := v.(type) {
+		case LogValuer, slog.Value, slog.LogValuer:
+			return true
+		case map[string]any:
+			if hasLazy(tv, depth+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func slogValueToAny(v slog.Value) any {
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+	group := make(map[string]any, len(v.Group()))
+	for _, a := range v.Group() {
+		group[a.Key] = ResolveValue(a.Value)
+	}
+	return group
+}
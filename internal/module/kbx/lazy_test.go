@@ -0,0 +1,76 @@
+package kbx
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type user struct{ name, password string }
+
+func (u user) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("name", u.name))
+}
+
+type chain int
+
+func (c chain) LogValue() any {
+	if c == 0 {
+		return "done"
+	}
+	return c - 1
+}
+
+func TestResolveValue(t *testing.T) {
+	calls := 0
+	lazy := Lazy(func() any { calls++; return 42 })
+	if got := ResolveValue(lazy); got != 42 || calls != 1 {
+		t.Errorf("Lazy = %v (calls %d)", got, calls)
+	}
+	if got := ResolveValue(chain(3)); got != "done" {
+		t.Errorf("chain = %v", got)
+	}
+	if got := ResolveValue(user{"ana", "s3cret"}); got.(map[string]any)["name"] != "ana" || len(got.(map[string]any)) != 1 {
+		t.Errorf("slog.LogValuer = %v", got)
+	}
+	if got := ResolveValue(Lazy(func() any { panic("boom") })); !strings.Contains(got.(string), "boom") {
+		t.Errorf("panic = %v", got)
+	}
+	if got := ResolveValue(Lazy(nil)); got != nil {
+		t.Errorf("nil Lazy = %v", got)
+	}
+}
+
+func TestResolveFields(t *testing.T) {
+	calls := 0
+	lazy := Lazy(func() any { calls++; return "v" })
+	nested := map[string]any{"slog": slog.IntValue(7), "deep": map[string]any{"lazy": lazy}}
+	fields := map[string]any{"n": 1, "lazy": lazy, "nested": nested}
+
+	for i := 1; i <= 2; i++ {
+		got := ResolveFields(fields)
+		// cada chamada reavalia: nada foi gravado de volta no mapa.
+		if got["lazy"] != "v" || calls != 2*i {
+			t.Errorf("lazy = %v (calls %d)", got["lazy"], calls)
+		}
+		n := got["nested"].(map[string]any)
+		if n["slog"] != int64(7) || n["deep"].(map[string]any)["lazy"] != "v" {
+			t.Errorf("nested = %#v", n)
+		}
+	}
+	if _, ok := fields["lazy"].(Lazy); !ok {
+		t.Errorf("input changed: %#v", fields["lazy"])
+	}
+	if _, ok := nested["slog"].(slog.Value); !ok {
+		t.Errorf("nested input changed: %#v", nested["slog"])
+	}
+	if _, ok := nested["deep"].(map[string]any)["lazy"].(Lazy); !ok {
+		t.Error("deep input changed")
+	}
+
+	// sem preguiçosos, o próprio mapa volta.
+	plain := map[string]any{"a": 1, "m": map[string]any{"b": 2}}
+	if got := ResolveFields(plain); len(got) != 2 || !HasLazy(fields) || HasLazy(plain) {
+		t.Errorf("plain: %v", got)
+	}
+}
@@ -65,6 +65,18 @@ func (l Level) Severity() int {
 	}
 }
 
+// IsTerminal indica os níveis que encerram o processo (fatal, critical e
+// panic). Quem pode segurar uma entry (amostragem, dedup) deve deixá-los
+// passar, senão a saída do processo dependeria da taxa de amostragem.
+func (l Level) IsTerminal() bool {
+	switch strings.ToLower(strings.ToValidUTF8(string(l), "")) {
+	case string(LevelFatal), string(LevelCritical), string(LevelPanic):
+		return true
+	default:
+		return false
+	}
+}
+
 // ParseLevel converte string em Level, com fallback para info.
 func ParseLevel(s string) Level {
 	switch strings.ToLower(strings.TrimSpace(strings.ToValidUTF8(s, ""))) {
@@ -0,0 +1,142 @@
+package kbx
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ColorMode é a profundidade de cor que o destino aceita.
+type ColorMode int
+
+const (
+	ColorNone      ColorMode = iota // texto puro
+	ColorBasic                      // 16 cores ANSI
+	Color256                        // paleta xterm de 256 cores
+	ColorTrueColor                  // 24 bits (RGB)
+)
+
+func (m ColorMode) String() string {
+	switch m {
+	case ColorBasic:
+		return "basic"
+	case Color256:
+		return "256"
+	case ColorTrueColor:
+		return "truecolor"
+	}
+	return "none"
+}
+
+// TermInfo descreve o destino de um logger: se é um terminal e quantas
+// cores ele aguenta. Os formatters humanos (text, pretty) usam isso pra
+// decidir entre cores/ícones e texto puro.
+type TermInfo struct {
+	TTY   bool
+	Color ColorMode
+	// Width é a largura do terminal em colunas (0 = desconhecida).
+	Width int
+}
+
+// fdWriter é o que *os.File (e afins) expõem.
+type fdWriter interface {
+	Fd() uintptr
+}
+
+// IsTerminal indica se w escreve num terminal. Writers que embrulham outro
+// (GetIOWriter, como o LogzWriter) são desembrulhados.
+func IsTerminal(w io.Writer) bool {
+	fd, ok := fdOf(w)
+	return ok && (isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd))
+}
+
+// fdOf acha o descritor por trás de w, desembrulhando writers.
+func fdOf(w io.Writer) (uintptr, bool) {
+	for i := 0; w != nil && i < 8; i++ {
+		if f, ok := w.(fdWriter); ok {
+			return f.Fd(), true
+		}
+		u, ok := w.(interface{ GetIOWriter() io.Writer })
+		if !ok {
+			return 0, false
+		}
+		w = u.GetIOWriter()
+	}
+	return 0, false
+}
+
+// DetectTerm descobre o TermInfo de w. tty, quando não nil, força a
+// resposta de "é terminal?" (opção output_tty); nil = detecta.
+//
+// Convenções respeitadas, nesta ordem:
+//   - NO_COLOR (https://no-color.org) ou LOGZ_NO_COLOR: sem cores;
+//   - FORCE_COLOR: 0/false desliga; 1/true = 16 cores, 2 = 256, 3 = truecolor
+//     (vazio também liga), mesmo fora de um terminal;
+//   - fora de um terminal ou com TERM=dumb: sem cores;
+//   - COLORTERM=truecolor|24bit: truecolor; TERM com "256color": 256.
+func DetectTerm(w io.Writer, tty *bool) TermInfo {
+	var t TermInfo
+	if tty != nil {
+		t.TTY = *tty
+	} else {
+		t.TTY = IsTerminal(w)
+	}
+	t.Color = detectColor(t.TTY)
+	if fd, ok := fdOf(w); ok && t.TTY {
+		t.Width = termWidth(fd)
+	}
+	if t.Width == 0 {
+		// COLUMNS vale quando o terminal não responde (ou output_tty forçado).
+		if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+			t.Width = n
+		}
+	}
+	return t
+}
+
+func detectColor(tty bool) ColorMode {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("LOGZ_NO_COLOR") != "" {
+		return ColorNone
+	}
+	if force, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		switch strings.ToLower(strings.TrimSpace(force)) {
+		case "0", "false", "no", "off":
+			return ColorNone
+		case "2":
+			return Color256
+		case "3":
+			return ColorTrueColor
+		default:
+			// "1", "true", "": liga, e aproveita o que o terminal declara.
+			if m := termColor(); m > ColorBasic {
+				return m
+			}
+			return ColorBasic
+		}
+	}
+	if !tty || os.Getenv("TERM") == "dumb" {
+		return ColorNone
+	}
+	// console do Windows sem VT: só com um terminal que declare suporte.
+	if runtime.GOOS == "windows" && os.Getenv("WT_SESSION") == "" &&
+		os.Getenv("ANSICON") == "" && os.Getenv("TERM") == "" && os.Getenv("COLORTERM") == "" {
+		return ColorNone
+	}
+	return termColor()
+}
+
+// termColor lê COLORTERM/TERM; sem pistas, 16 cores.
+func termColor() ColorMode {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ColorTrueColor
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return Color256
+	}
+	return ColorBasic
+}
@@ -0,0 +1,6 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos || windows)
+
+package kbx
+
+// termWidth: sem como perguntar ao terminal nesta plataforma.
+func termWidth(fd uintptr) int { return 0 }
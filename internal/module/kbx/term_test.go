@@ -0,0 +1,76 @@
+package kbx
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"testing"
+)
+
+// termEnv são as variáveis que DetectTerm consulta; cada caso parte de
+// todas ausentes (FORCE_COLOR vazio já liga as cores).
+var termEnv = []string{
+	"NO_COLOR", "LOGZ_NO_COLOR", "FORCE_COLOR", "COLORTERM", "TERM",
+	"WT_SESSION", "ANSICON", "COLUMNS",
+}
+
+func TestDetectTerm(t *testing.T) {
+	yes, no := true, false
+	// console do Windows sem pistas de VT não tem cores; nos outros
+	// sistemas a mesma situação dá 16 cores.
+	bareConsole := ColorBasic
+	if runtime.GOOS == "windows" {
+		bareConsole = ColorNone
+	}
+
+	cases := []struct {
+		name  string
+		env   map[string]string
+		tty   *bool
+		want  ColorMode
+		width int
+	}{
+		{"not a terminal", map[string]string{"TERM": "xterm-256color"}, nil, ColorNone, 0},
+		{"tty override off", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, &no, ColorNone, 0},
+		{"basic terminal", map[string]string{"TERM": "xterm"}, &yes, ColorBasic, 0},
+		{"TERM 256color", map[string]string{"TERM": "xterm-256color"}, &yes, Color256, 0},
+		{"COLORTERM truecolor beats TERM", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, &yes, ColorTrueColor, 0},
+		{"COLORTERM 24bit", map[string]string{"TERM": "xterm", "COLORTERM": "24bit"}, &yes, ColorTrueColor, 0},
+		{"TERM dumb beats COLORTERM", map[string]string{"TERM": "dumb", "COLORTERM": "truecolor"}, &yes, ColorNone, 0},
+		{"NO_COLOR beats FORCE_COLOR", map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "3", "TERM": "xterm"}, &yes, ColorNone, 0},
+		{"LOGZ_NO_COLOR", map[string]string{"LOGZ_NO_COLOR": "1", "TERM": "xterm-256color"}, &yes, ColorNone, 0},
+		{"FORCE_COLOR 0 on a terminal", map[string]string{"FORCE_COLOR": "0", "TERM": "xterm-256color"}, &yes, ColorNone, 0},
+		{"FORCE_COLOR false", map[string]string{"FORCE_COLOR": "false", "TERM": "xterm"}, &yes, ColorNone, 0},
+		{"FORCE_COLOR 1 off a terminal", map[string]string{"FORCE_COLOR": "1"}, nil, ColorBasic, 0},
+		{"FORCE_COLOR beats TERM dumb", map[string]string{"FORCE_COLOR": "1", "TERM": "dumb"}, nil, ColorBasic, 0},
+		{"FORCE_COLOR 2", map[string]string{"FORCE_COLOR": "2"}, nil, Color256, 0},
+		{"FORCE_COLOR 3", map[string]string{"FORCE_COLOR": "3"}, nil, ColorTrueColor, 0},
+		{"FORCE_COLOR empty keeps COLORTERM", map[string]string{"FORCE_COLOR": "", "COLORTERM": "truecolor"}, nil, ColorTrueColor, 0},
+		{"FORCE_COLOR true keeps TERM", map[string]string{"FORCE_COLOR": "true", "TERM": "screen-256color"}, nil, Color256, 0},
+		{"bare console", nil, &yes, bareConsole, 0},
+		{"Windows Terminal", map[string]string{"WT_SESSION": "1"}, &yes, ColorBasic, 0},
+		{"COLUMNS without a terminal size", map[string]string{"COLUMNS": "120"}, &yes, bareConsole, 120},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, k := range termEnv {
+				t.Setenv(k, "")
+				os.Unsetenv(k)
+			}
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			got := DetectTerm(&bytes.Buffer{}, tc.tty)
+			if got.Color != tc.want {
+				t.Errorf("Color = %s, want %s", got.Color, tc.want)
+			}
+			if wantTTY := tc.tty != nil && *tc.tty; got.TTY != wantTTY {
+				t.Errorf("TTY = %v, want %v", got.TTY, wantTTY)
+			}
+			if got.Width != tc.width {
+				t.Errorf("Width = %d, want %d", got.Width, tc.width)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package kbx
+
+import "golang.org/x/sys/windows"
+
+// termWidth lê a largura (em colunas) da janela do console no handle fd.
+func termWidth(fd uintptr) int {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0
+	}
+	return int(info.Window.Right-info.Window.Left) + 1
+}
@@ -0,0 +1,152 @@
+package kbx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Layouts especiais aceitos em TimeFormat além dos layouts do pacote time.
+const (
+	TimeUnix      = "unix"
+	TimeUnixMilli = "unixms"
+	TimeUnixMicro = "unixus"
+	TimeUnixNano  = "unixns"
+)
+
+// namedLayouts: nomes amigáveis pra usar na config/env.
+var namedLayouts = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"rfc1123":     time.RFC1123,
+	"rfc1123z":    time.RFC1123Z,
+	"rfc822":      time.RFC822,
+	"kitchen":     time.Kitchen,
+	"stamp":       time.Stamp,
+	"stampmilli":  time.StampMilli,
+	"stampmicro":  time.StampMicro,
+	"datetime":    time.DateTime,
+	"dateonly":    time.DateOnly,
+	"timeonly":    time.TimeOnly,
+	"unix":        TimeUnix,
+	"unixms":      TimeUnixMilli,
+	"unixmilli":   TimeUnixMilli,
+	"unixus":      TimeUnixMicro,
+	"unixmicro":   TimeUnixMicro,
+	"unixns":      TimeUnixNano,
+	"unixnano":    TimeUnixNano,
+}
+
+// TimeFormat diz como um formatter escreve o timestamp: layout (do pacote
+// time ou um dos especiais unix*) e fuso. O valor zero significa "use o
+// padrão do formatter".
+type TimeFormat struct {
+	Layout   string
+	Location *time.Location
+}
+
+// IsZero indica que nada foi configurado.
+func (tf TimeFormat) IsZero() bool {
+	return tf.Layout == "" && tf.Location == nil
+}
+
+// Or completa o que não foi configurado com def.
+func (tf TimeFormat) Or(def TimeFormat) TimeFormat {
+	if tf.Layout == "" {
+		tf.Layout = def.Layout
+	}
+	if tf.Location == nil {
+		tf.Location = def.Location
+	}
+	return tf
+}
+
+// IsNumeric indica os layouts unix*, que saem como número.
+func (tf TimeFormat) IsNumeric() bool {
+	switch tf.Layout {
+	case TimeUnix, TimeUnixMilli, TimeUnixMicro, TimeUnixNano:
+		return true
+	}
+	return false
+}
+
+// Format escreve t no layout e fuso configurados.
+func (tf TimeFormat) Format(t time.Time) string {
+	switch tf.Layout {
+	case TimeUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimeUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case TimeUnixMicro:
+		return strconv.FormatInt(t.UnixMicro(), 10)
+	case TimeUnixNano:
+		return strconv.FormatInt(t.UnixNano(), 10)
+	}
+	if tf.Location != nil {
+		t = t.In(tf.Location)
+	}
+	layout := tf.Layout
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+	return t.Format(layout)
+}
+
+// JSON devolve o timestamp pronto pra ir num documento JSON: número nos
+// layouts unix*, string nos demais.
+func (tf TimeFormat) JSON(t time.Time) []byte {
+	s := tf.Format(t)
+	if tf.IsNumeric() {
+		return []byte(s)
+	}
+	b, _ := json.Marshal(s)
+	return b
+}
+
+// TimedEntry é implementado pelas entries que sabem serializar o próprio
+// "ts" (JSON/YAML) num TimeFormat. Timed devolve o valor a ser passado pro
+// Marshal; a entry em si não muda, então pode estar em uso por outros
+// formatters ao mesmo tempo.
+type TimedEntry interface {
+	Timed(tf TimeFormat) any
+}
+
+var timeFormatCache sync.Map // "layout\x00zone" -> TimeFormat
+
+// ParseTimeFormat monta um TimeFormat a partir da config:
+//   - layout: nome ("rfc3339nano", "unixms", "datetime"...) ou um layout
+//     Go ("2006-01-02 15:04:05"); vazio = padrão do formatter;
+//   - zone: "UTC", "Local" ou um nome IANA ("America/Sao_Paulo"); vazio =
+//     padrão do formatter.
+func ParseTimeFormat(layout, zone string) (TimeFormat, error) {
+	layout, zone = strings.TrimSpace(layout), strings.TrimSpace(zone)
+	key := layout + "\x00" + zone
+	if v, ok := timeFormatCache.Load(key); ok {
+		return v.(TimeFormat), nil
+	}
+
+	var tf TimeFormat
+	if named, ok := namedLayouts[strings.ToLower(layout)]; ok {
+		tf.Layout = named
+	} else {
+		tf.Layout = layout
+	}
+	switch strings.ToLower(zone) {
+	case "":
+	case "utc", "z":
+		tf.Location = time.UTC
+	case "local":
+		tf.Location = time.Local
+	default:
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return TimeFormat{}, fmt.Errorf("logz: invalid time zone %q: %w", zone, err)
+		}
+		tf.Location = loc
+	}
+	timeFormatCache.Store(key, tf)
+	return tf, nil
+}
@@ -0,0 +1,140 @@
+package kbx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceparentHeader é o header W3C Trace Context.
+const TraceparentHeader = "traceparent"
+
+// TraceContext identifica o span corrente, no formato W3C:
+// trace_id com 32 hex, span_id com 16 hex e as trace flags (bit 0 = sampled).
+type TraceContext struct {
+	TraceID    string
+	SpanID     string
+	TraceFlags byte
+}
+
+// IsValid indica se trace_id e span_id estão presentes e não são só zeros.
+func (tc TraceContext) IsValid() bool {
+	return isHexID(tc.TraceID, 32) && isHexID(tc.SpanID, 16)
+}
+
+// Sampled indica se o bit "sampled" está ligado.
+func (tc TraceContext) Sampled() bool {
+	return tc.TraceFlags&0x01 == 0x01
+}
+
+// Flags devolve as trace flags em hex ("01", "00"...).
+func (tc TraceContext) Flags() string {
+	return fmt.Sprintf("%02x", tc.TraceFlags)
+}
+
+// Traceparent monta o valor do header traceparent (versão 00).
+func (tc TraceContext) Traceparent() string {
+	if !tc.IsValid() {
+		return ""
+	}
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-" + tc.Flags()
+}
+
+// ParseTraceparent lê um header W3C traceparent:
+//
+//	00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+//
+// Versões futuras (!= 00) são aceitas desde que os quatro primeiros campos
+// tenham o formato esperado, como manda a especificação.
+func ParseTraceparent(s string) (TraceContext, error) {
+	s = strings.TrimSpace(s)
+	parts := strings.Split(s, "-")
+	if len(parts) < 4 {
+		return TraceContext{}, fmt.Errorf("logz: invalid traceparent %q", s)
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || !isHex(version) || version == "ff" {
+		return TraceContext{}, fmt.Errorf("logz: invalid traceparent version %q", version)
+	}
+	if version == "00" && len(parts) != 4 {
+		return TraceContext{}, fmt.Errorf("logz: invalid traceparent %q", s)
+	}
+	if !isHexID(traceID, 32) {
+		return TraceContext{}, fmt.Errorf("logz: invalid traceparent trace-id %q", traceID)
+	}
+	if !isHexID(spanID, 16) {
+		return TraceContext{}, fmt.Errorf("logz: invalid traceparent parent-id %q", spanID)
+	}
+	if len(flags) != 2 || !isHex(flags) {
+		return TraceContext{}, fmt.Errorf("logz: invalid traceparent flags %q", flags)
+	}
+	b, _ := hex.DecodeString(flags)
+	return TraceContext{TraceID: traceID, SpanID: spanID, TraceFlags: b[0]}, nil
+}
+
+// NewTraceContext gera um trace novo (amostrado), pra quando a requisição
+// não trouxe nenhum.
+func NewTraceContext() TraceContext {
+	return TraceContext{TraceID: randomHex(16), SpanID: randomHex(8), TraceFlags: 0x01}
+}
+
+// Child devolve um span filho: mesmo trace e flags, span_id novo.
+func (tc TraceContext) Child() TraceContext {
+	tc.SpanID = randomHex(8)
+	return tc
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type traceCtxKey struct{}
+
+// ContextWithTrace guarda tc no contexto. Serve pra quem não usa o SDK do
+// OpenTelemetry mas quer correlacionar logs (ex: a partir de um traceparent).
+func ContextWithTrace(ctx context.Context, tc TraceContext) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, traceCtxKey{}, tc)
+}
+
+// TraceFromContext devolve o span corrente do contexto. Um span do
+// OpenTelemetry tem prioridade; na falta dele vale o que foi guardado com
+// ContextWithTrace.
+func TraceFromContext(ctx context.Context) (TraceContext, bool) {
+	if ctx == nil {
+		return TraceContext{}, false
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return TraceContext{
+			TraceID:    sc.TraceID().String(),
+			SpanID:     sc.SpanID().String(),
+			TraceFlags: byte(sc.TraceFlags()),
+		}, true
+	}
+	if tc, ok := ctx.Value(traceCtxKey{}).(TraceContext); ok && tc.IsValid() {
+		return tc, true
+	}
+	return TraceContext{}, false
+}
+
+func isHexID(s string, n int) bool {
+	return len(s) == n && isHex(s) && strings.Trim(s, "0") != ""
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package kbx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const tid, sid = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	tests := []struct {
+		in      string
+		ok      bool
+		sampled bool
+	}{
+		{"00-" + tid + "-" + sid + "-01", true, true},
+		{" 00-" + tid + "-" + sid + "-00 ", true, false},
+		{"01-" + tid + "-" + sid + "-01-extra", true, true},
+		{"00-" + tid + "-" + sid + "-01-extra", false, false},
+		{"ff-" + tid + "-" + sid + "-01", false, false},
+		{"00-" + "00000000000000000000000000000000" + "-" + sid + "-01", false, false},
+		{"00-" + tid + "-0000000000000000-01", false, false},
+		{"00-" + tid[:31] + "-" + sid + "-01", false, false},
+		{"00-" + tid + "-" + sid + "-zz", false, false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-" + sid + "-01", false, false},
+		{"garbage", false, false},
+	}
+	for _, tt := range tests {
+		tc, err := ParseTraceparent(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseTraceparent(%q) err = %v, want ok=%v", tt.in, err, tt.ok)
+			continue
+		}
+		if !tt.ok {
+			continue
+		}
+		if tc.TraceID != tid || tc.SpanID != sid || tc.Sampled() != tt.sampled {
+			t.Errorf("ParseTraceparent(%q) = %+v", tt.in, tc)
+		}
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	tc := NewTraceContext()
+	if !tc.IsValid() || !tc.Sampled() {
+		t.Fatalf("NewTraceContext = %+v", tc)
+	}
+	back, err := ParseTraceparent(tc.Traceparent())
+	if err != nil || back != tc {
+		t.Fatalf("round trip = %+v, %v", back, err)
+	}
+	child := tc.Child()
+	if child.TraceID != tc.TraceID || child.SpanID == tc.SpanID || child.TraceFlags != tc.TraceFlags {
+		t.Errorf("Child = %+v", child)
+	}
+	if (TraceContext{}).Traceparent() != "" {
+		t.Error("invalid context should have no traceparent")
+	}
+}
+
+func TestTraceFromContext(t *testing.T) {
+	if _, ok := TraceFromContext(context.Background()); ok {
+		t.Error("empty context has a trace")
+	}
+	tc := NewTraceContext()
+	got, ok := TraceFromContext(ContextWithTrace(context.Background(), tc))
+	if !ok || got != tc {
+		t.Errorf("TraceFromContext = %+v, %v", got, ok)
+	}
+}
@@ -0,0 +1,425 @@
+// Package redact implementa o motor de redação de segredos e PII do logz.
+package redact
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/kubex-ecosystem/logz/interfaces"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// Strategy define o que acontece com um valor que casou com uma regra.
+type Strategy string
+
+const (
+	StrategyMask     Strategy = "mask"
+	StrategyHash     Strategy = "hash"
+	StrategyTruncate Strategy = "truncate"
+	StrategyDrop     Strategy = "drop"
+)
+
+const defaultMask = "********"
+
+// Rule é uma regra já compilada.
+type Rule struct {
+	Name        string
+	Keys        []string
+	Pattern     *regexp.Regexp
+	Strategy    Strategy
+	Keep        int
+	Replacement string
+
+	// Validate filtra falsos positivos do Pattern (ex: Luhn em cartões):
+	// só o que passa é redigido.
+	Validate func(string) bool
+}
+
+// validators são os nomes aceitos em RedactRule.Validate.
+var validators = map[string]func(string) bool{
+	"luhn": luhn,
+}
+
+// DefaultRules são as regras aplicadas quando a configuração não
+// desliga os padrões (DisableDefaults).
+func DefaultRules() []kbx.RedactRule {
+	return []kbx.RedactRule{
+		{
+			Name: "secret_keys",
+			Keys: []string{
+				"password", "passwd", "pwd", "secret", "token", "authorization",
+				"api_key", "apikey", "cookie", "set_cookie", "private_key",
+				"*_secret", "*_token", "*_password",
+			},
+			Strategy: string(StrategyMask),
+		},
+		{
+			Name:     "bearer",
+			Pattern:  `(?i)bearer\s+[a-z0-9\-._~+/]+=*`,
+			Strategy: string(StrategyMask),
+		},
+		{
+			Name:     "jwt",
+			Pattern:  `eyJ[a-zA-Z0-9_-]{4,}\.[a-zA-Z0-9_-]{4,}\.[a-zA-Z0-9_-]*`,
+			Strategy: string(StrategyMask),
+		},
+		{
+			Name:     "credit_card",
+			Pattern:  `\b(?:\d[ -]?){12,18}\d\b`,
+			Strategy: string(StrategyMask),
+			Keep:     4,
+			Validate: "luhn",
+		},
+		{
+			Name:     "email",
+			Pattern:  `[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`,
+			Strategy: string(StrategyMask),
+		},
+	}
+}
+
+// Redactor aplica as regras em Message, Fields, Tags e no texto do Error
+// de uma entry, antes da formatação.
+type Redactor struct {
+	salt     []byte
+	keyRules []*Rule
+	valRules []*Rule
+}
+
+// New compila as regras da configuração. Com opts nil, usa só os padrões.
+func New(opts *kbx.LogzRedactOptions) (*Redactor, error) {
+	if opts == nil {
+		opts = &kbx.LogzRedactOptions{}
+	}
+	r := &Redactor{
+		salt: []byte(kbx.GetValueOrDefaultSimple(opts.Salt, kbx.GetEnvOrDefault("LOGZ_REDACT_SALT", ""))),
+	}
+
+	rules := make([]kbx.RedactRule, 0, len(opts.Rules)+5)
+	rules = append(rules, opts.Rules...)
+	if !opts.DisableDefaults {
+		rules = append(rules, DefaultRules()...)
+	}
+
+	for _, rr := range rules {
+		rule, err := compile(rr)
+		if err != nil {
+			return nil, err
+		}
+		if len(rule.Keys) > 0 {
+			r.keyRules = append(r.keyRules, rule)
+		}
+		if rule.Pattern != nil {
+			r.valRules = append(r.valRules, rule)
+		}
+	}
+	return r, nil
+}
+
+func compile(rr kbx.RedactRule) (*Rule, error) {
+	rule := &Rule{
+		Name:        rr.Name,
+		Strategy:    Strategy(strings.ToLower(kbx.GetValueOrDefaultSimple(rr.Strategy, string(StrategyMask)))),
+		Keep:        rr.Keep,
+		Replacement: rr.Replacement,
+	}
+	switch rule.Strategy {
+	case StrategyMask, StrategyHash, StrategyTruncate, StrategyDrop:
+	default:
+		return nil, fmt.Errorf("logz: redact rule %q: unknown strategy %q", rr.Name, rr.Strategy)
+	}
+	for _, k := range rr.Keys {
+		rule.Keys = append(rule.Keys, normalizeKey(k))
+	}
+	if rr.Pattern != "" {
+		re, err := regexp.Compile(rr.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("logz: redact rule %q: %w", rr.Name, err)
+		}
+		rule.Pattern = re
+	}
+	if len(rule.Keys) == 0 && rule.Pattern == nil {
+		return nil, fmt.Errorf("logz: redact rule %q: needs keys or pattern", rr.Name)
+	}
+	if rr.Validate != "" {
+		v, ok := validators[strings.ToLower(rr.Validate)]
+		if !ok {
+			return nil, fmt.Errorf("logz: redact rule %q: unknown validate %q", rr.Name, rr.Validate)
+		}
+		if rule.Pattern == nil {
+			return nil, fmt.Errorf("logz: redact rule %q: validate needs pattern", rr.Name)
+		}
+		rule.Validate = v
+	}
+	return rule, nil
+}
+
+// Apply redige a entry IN PLACE.
+func (r *Redactor) Apply(e kbx.LogzEntry) {
+	if r == nil || e == nil {
+		return
+	}
+	if msg := e.GetMessage(); msg != "" {
+		e.WithMessage(r.String(msg))
+	}
+	if fields := e.GetFields(); fields != nil {
+		r.redactMap(fields)
+	}
+	if tags := e.GetTags(); tags != nil {
+		for k, v := range tags {
+			if rule := r.keyRule(k); rule != nil {
+				if rule.Strategy == StrategyDrop {
+					delete(tags, k)
+					continue
+				}
+				tags[k] = r.apply(rule, v)
+				continue
+			}
+			tags[k] = r.String(v)
+		}
+	}
+	if ee, ok := e.(interface{ GetError() error }); ok {
+		if err := ee.GetError(); err != nil {
+			if red := r.String(err.Error()); red != err.Error() {
+				e.WithError(&redactedError{msg: red, err: err})
+			}
+		}
+	}
+}
+
+// Hook devolve o Redactor como um interfaces.Hook, pra quem prefere
+// plugar a redação via AddHook.
+func (r *Redactor) Hook() interfaces.Hook {
+	return func(record kbx.Entry) error {
+		if e, ok := record.(kbx.LogzEntry); ok {
+			r.Apply(e)
+		}
+		return nil
+	}
+}
+
+// String aplica as regras de valor (regex) num texto livre.
+func (r *Redactor) String(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	for _, rule := range r.valRules {
+		s = rule.Pattern.ReplaceAllStringFunc(s, func(m string) string {
+			if rule.Validate != nil && !rule.Validate(m) {
+				return m
+			}
+			if rule.Strategy == StrategyDrop {
+				return ""
+			}
+			return r.apply(rule, m)
+		})
+	}
+	return s
+}
+
+// Value redige um valor associado a key. drop=true indica que o campo
+// deve ser removido. Mapas e slices aninhados voltam copiados: o valor do
+// chamador nunca é alterado. Structs (e mapas/slices de outros tipos) são
+// redigidos pela forma JSON: campos não exportados ou com `json:"-"` não
+// são vistos.
+func (r *Redactor) Value(key string, v any) (out any, drop bool) {
+	if r == nil {
+		return v, false
+	}
+	if rule := r.keyRule(key); rule != nil {
+		if rule.Strategy == StrategyDrop {
+			return nil, true
+		}
+		return r.apply(rule, fmt.Sprint(v)), false
+	}
+	return r.value(v), false
+}
+
+// value redige v pelas regras de valor (e de chave, nos mapas aninhados).
+func (r *Redactor) value(v any) any {
+	switch tv := v.(type) {
+	case string:
+		return r.String(tv)
+	case []byte:
+		return r.String(string(tv))
+	case error:
+		// só troca o tipo do valor se algo foi de fato redigido.
+		if red := r.String(tv.Error()); red != tv.Error() {
+			return red
+		}
+		return v
+	case fmt.Stringer:
+		if red := r.String(tv.String()); red != tv.String() {
+			return red
+		}
+		return v
+	case map[string]any:
+		out := make(map[string]any, len(tv))
+		for k, s := range tv {
+			out[k] = s
+		}
+		r.redactMap(out)
+		return out
+	case map[string]string:
+		out := make(map[string]any, len(tv))
+		for k, s := range tv {
+			out[k] = s
+		}
+		r.redactMap(out)
+		return out
+	case []any:
+		out := make([]any, len(tv))
+		for i, s := range tv {
+			out[i] = r.value(s)
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, len(tv))
+		for i, s := range tv {
+			out[i] = r.value(s)
+		}
+		return out
+	case []string:
+		out := make([]string, len(tv))
+		for i, s := range tv {
+			out[i] = r.String(s)
+		}
+		return out
+	}
+	if red, ok := r.viaJSON(v); ok {
+		return red
+	}
+	return v
+}
+
+// viaJSON redige structs, ponteiros pra struct e mapas/slices que value não
+// conhece pela forma JSON deles. ok=false quando nada foi redigido: aí o
+// valor original (e o tipo dele) fica como está.
+func (r *Redactor) viaJSON(v any) (any, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return nil, false
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var plain any
+	if err := dec.Decode(&plain); err != nil {
+		return nil, false
+	}
+	// plain é nosso: redigir dentro dele não mexe no valor do chamador.
+	red := r.value(plain)
+	if reflect.DeepEqual(red, plain) {
+		return nil, false
+	}
+	return red, true
+}
+
+// redactMap redige m IN PLACE; só é chamado em mapas do próprio logz (os
+// Fields da entry ou cópias feitas por value).
+func (r *Redactor) redactMap(m map[string]any) {
+	for k, v := range m {
+		out, drop := r.Value(k, v)
+		if drop {
+			delete(m, k)
+			continue
+		}
+		m[k] = out
+	}
+}
+
+func (r *Redactor) keyRule(key string) *Rule {
+	k := normalizeKey(key)
+	for _, rule := range r.keyRules {
+		for _, pattern := range rule.Keys {
+			if ok, _ := path.Match(pattern, k); ok {
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Redactor) apply(rule *Rule, s string) string {
+	switch rule.Strategy {
+	case StrategyHash:
+		mac := hmac.New(sha256.New, r.salt)
+		mac.Write([]byte(s))
+		return "sha256:" + hex.EncodeToString(mac.Sum(nil))[:16]
+	case StrategyTruncate:
+		keep := rule.Keep
+		if keep <= 0 {
+			keep = 4
+		}
+		// corta por rune: cortar no meio de um caractere multibyte deixaria
+		// UTF-8 inválido na saída.
+		rs := []rune(s)
+		if len(rs) <= keep {
+			return s
+		}
+		return string(rs[:keep]) + "..."
+	case StrategyDrop:
+		return ""
+	default:
+		if rule.Replacement != "" {
+			return rule.Replacement
+		}
+		if rs := []rune(s); rule.Keep > 0 && len(rs) > rule.Keep {
+			return strings.Repeat("*", len(rs)-rule.Keep) + string(rs[len(rs)-rule.Keep:])
+		}
+		return defaultMask
+	}
+}
+
+func normalizeKey(k string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(k)), "-", "_")
+}
+
+// luhn valida o dígito verificador de números de cartão.
+func luhn(s string) bool {
+	sum, n, double := 0, 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
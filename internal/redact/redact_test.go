@@ -0,0 +1,223 @@
+package redact_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/internal/redact"
+)
+
+const defaultMask = "********"
+
+func newRedactor(t *testing.T, opts *kbx.LogzRedactOptions) *redact.Redactor {
+	t.Helper()
+	r, err := redact.New(opts)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return r
+}
+
+func TestStringDefaults(t *testing.T) {
+	r := newRedactor(t, nil)
+	tests := []struct {
+		name, in, want string
+	}{
+		{"bearer", "auth: Bearer abc.def-123", "auth: ********"},
+		{"jwt", "tok eyJhbGciOi.eyJzdWIiOi.sig", "tok ********"},
+		{"email", "from ana@example.com now", "from ******** now"},
+		{"card", "card 4111 1111 1111 1111", "card ***************1111"},
+		{"card failing luhn", "id 4111 1111 1111 1112", "id 4111 1111 1111 1112"},
+		{"plain", "nothing here", "nothing here"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.String(tt.in); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValueKeyRules(t *testing.T) {
+	r := newRedactor(t, &kbx.LogzRedactOptions{
+		Salt: "s",
+		Rules: []kbx.RedactRule{
+			{Name: "drop", Keys: []string{"ssn"}, Strategy: "drop"},
+			{Name: "hash", Keys: []string{"user_id"}, Strategy: "hash"},
+			{Name: "trunc", Keys: []string{"ip"}, Strategy: "truncate", Keep: 3},
+		},
+	})
+	if _, drop := r.Value("SSN", "123"); !drop {
+		t.Error("ssn should be dropped")
+	}
+	if got, _ := r.Value("db-password", "hunter2"); got != defaultMask {
+		t.Errorf("db-password = %v, want mask", got)
+	}
+	h1, _ := r.Value("user_id", 42)
+	h2, _ := r.Value("user_id", "42")
+	if h1 != h2 || !strings.HasPrefix(h1.(string), "sha256:") {
+		t.Errorf("hash = %v / %v", h1, h2)
+	}
+	if got, _ := r.Value("ip", "10.0.0.1"); got != "10...." {
+		t.Errorf("ip = %v", got)
+	}
+	if got, _ := r.Value("err", errors.New("to ana@example.com")); got != "to ********" {
+		t.Errorf("err = %v", got)
+	}
+}
+
+func TestValueDoesNotMutateCaller(t *testing.T) {
+	r := newRedactor(t, nil)
+	inner := map[string]any{"password": "hunter2", "list": []any{map[string]any{"token": "t"}}}
+	outer := map[string]any{"inner": inner, "emails": []string{"a@b.io"}}
+
+	got, _ := r.Value("data", outer)
+
+	if inner["password"] != "hunter2" {
+		t.Errorf("caller map changed: %v", inner["password"])
+	}
+	if inner["list"].([]any)[0].(map[string]any)["token"] != "t" {
+		t.Error("caller slice element changed")
+	}
+	if outer["emails"].([]string)[0] != "a@b.io" {
+		t.Error("caller []string changed")
+	}
+	red := got.(map[string]any)["inner"].(map[string]any)
+	if red["password"] != defaultMask {
+		t.Errorf("password = %v", red["password"])
+	}
+	if red["list"].([]any)[0].(map[string]any)["token"] != defaultMask {
+		t.Error("nested token not redacted")
+	}
+}
+
+// truncate e mask com Keep contam runes, não bytes: a saída continua
+// UTF-8 válido.
+func TestKeepCountsRunes(t *testing.T) {
+	r := newRedactor(t, &kbx.LogzRedactOptions{
+		DisableDefaults: true,
+		Rules: []kbx.RedactRule{
+			{Name: "trunc", Keys: []string{"name"}, Strategy: "truncate", Keep: 3},
+			{Name: "mask", Keys: []string{"city"}, Strategy: "mask", Keep: 2},
+		},
+	})
+	tests := []struct {
+		key, in, want string
+	}{
+		{"name", "Joãozinho", "Joã..."},
+		{"name", "日本語テキスト", "日本語..."},
+		{"name", "ção", "ção"},
+		{"city", "São Paulo", "*******lo"},
+		{"city", "東京都", "*京都"},
+	}
+	for _, tt := range tests {
+		got, _ := r.Value(tt.key, tt.in)
+		if got != tt.want {
+			t.Errorf("Value(%q, %q) = %q, want %q", tt.key, tt.in, got, tt.want)
+		}
+		if !utf8.ValidString(got.(string)) {
+			t.Errorf("Value(%q, %q) = %q is not valid UTF-8", tt.key, tt.in, got)
+		}
+	}
+}
+
+type account struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+	Contact  struct {
+		Email string `json:"email"`
+	} `json:"contact"`
+}
+
+type plain struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Structs são redigidos pela forma JSON; sem nada a redigir, o valor
+// fica como veio.
+func TestValueStructs(t *testing.T) {
+	r := newRedactor(t, nil)
+	acc := account{User: "ana", Password: "hunter2"}
+	acc.Contact.Email = "ana@example.com"
+
+	for name, v := range map[string]any{"value": acc, "pointer": &acc, "slice": []account{acc}} {
+		t.Run(name, func(t *testing.T) {
+			got, _ := r.Value("data", v)
+			red, ok := got.(map[string]any)
+			if list, isList := got.([]any); isList && len(list) == 1 {
+				red, ok = list[0].(map[string]any)
+			}
+			if !ok {
+				t.Fatalf("Value = %#v, want the redacted JSON form", got)
+			}
+			if red["user"] != "ana" || red["password"] != defaultMask {
+				t.Errorf("user=%v password=%v", red["user"], red["password"])
+			}
+			if email := red["contact"].(map[string]any)["email"]; email != defaultMask {
+				t.Errorf("contact.email = %v", email)
+			}
+		})
+	}
+	if acc.Password != "hunter2" || acc.Contact.Email != "ana@example.com" {
+		t.Errorf("caller struct changed: %+v", acc)
+	}
+
+	p := plain{ID: 7, Name: "ana"}
+	if got, _ := r.Value("data", p); got != p {
+		t.Errorf("struct without secrets = %#v, want it unchanged", got)
+	}
+}
+
+func TestValidateIsExplicit(t *testing.T) {
+	r := newRedactor(t, &kbx.LogzRedactOptions{
+		DisableDefaults: true,
+		Rules: []kbx.RedactRule{
+			{Name: "pan", Pattern: `\b\d{16}\b`, Validate: "luhn"},
+			{Name: "credit_card", Pattern: `\bX\d{3}\b`},
+		},
+	})
+	if got := r.String("4111111111111111"); got != defaultMask {
+		t.Errorf("valid card = %q", got)
+	}
+	if got := r.String("4111111111111112"); got != "4111111111111112" {
+		t.Errorf("invalid card redacted: %q", got)
+	}
+	// o nome não liga mais a validação.
+	if got := r.String("X123"); got != defaultMask {
+		t.Errorf("credit_card without validate = %q", got)
+	}
+
+	if _, err := redact.New(&kbx.LogzRedactOptions{Rules: []kbx.RedactRule{{Name: "x", Pattern: "a", Validate: "nope"}}}); err == nil {
+		t.Error("unknown validate should fail")
+	}
+}
+
+func TestApplyEntryTagsAndError(t *testing.T) {
+	r := newRedactor(t, nil)
+	e, _ := core.NewEntry(kbx.LevelInfo)
+	e.WithMessage("login ana@example.com").
+		WithFields(map[string]any{"token": "abc", "keep": 1}).
+		WithError(errors.New("bad token Bearer xyz"))
+	e.Tags = map[string]string{"api_key": "k", "env": "prod"}
+
+	r.Apply(e)
+
+	if e.Message != "login ********" {
+		t.Errorf("msg = %q", e.Message)
+	}
+	if e.Fields["token"] != defaultMask || e.Fields["keep"] != 1 {
+		t.Errorf("fields = %v", e.Fields)
+	}
+	if e.Tags["api_key"] != defaultMask || e.Tags["env"] != "prod" {
+		t.Errorf("tags = %v", e.Tags)
+	}
+	if err := e.GetError(); err == nil || err.Error() != "bad token ********" {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,298 @@
+// Package sampling implementa amostragem e rate limiting de entries repetitivas.
+package sampling
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+const (
+	defaultInterval = time.Second
+	maxSummaryKeys  = 10
+)
+
+// Bucket é um token bucket por nível: Rate entries por segundo, com
+// rajadas de até Burst.
+type Bucket struct {
+	Rate  float64
+	Burst int
+
+	tokens float64
+	last   time.Time
+}
+
+// Summary resume o que foi suprimido numa janela.
+type Summary struct {
+	Start      time.Time
+	End        time.Time
+	Suppressed int
+	ByLevel    map[kbx.Level]int
+	ByMessage  map[string]int
+}
+
+// Fields devolve o resumo como fields de uma entry.
+func (s Summary) Fields() map[string]any {
+	byLevel := make(map[string]any, len(s.ByLevel))
+	for l, n := range s.ByLevel {
+		byLevel[string(l)] = n
+	}
+	byMessage := make(map[string]any, len(s.ByMessage))
+	for m, n := range s.ByMessage {
+		byMessage[m] = n
+	}
+	return map[string]any{
+		"suppressed":   s.Suppressed,
+		"by_level":     byLevel,
+		"by_message":   byMessage,
+		"window_start": s.Start,
+		"window_end":   s.End,
+	}
+}
+
+// Message é a mensagem padrão da entry de resumo.
+func (s Summary) Message() string {
+	return fmt.Sprintf("logz: sampling suppressed %d entries", s.Suppressed)
+}
+
+// Sampler decide se uma entry segue no pipeline. As políticas são
+// avaliadas nesta ordem; a primeira que recusar suprime a entry:
+//
+//  1. probabilística por nível (típico pra debug/trace);
+//  2. token bucket por nível;
+//  3. primeiras First por (nível, mensagem) dentro de Interval, depois
+//     uma a cada Thereafter.
+//
+// Entries de níveis terminais (fatal/critical/panic, ver Level.IsTerminal)
+// nunca são amostradas.
+type Sampler struct {
+	mu sync.Mutex
+
+	Interval        time.Duration
+	First           int
+	Thereafter      int
+	Buckets         map[kbx.Level]*Bucket
+	Probabilities   map[kbx.Level]float64
+	SummaryInterval time.Duration
+
+	now       func() time.Time
+	rand      func() float64
+	onSummary func(Summary)
+
+	windowStart time.Time
+	counts      map[string]int
+
+	pending    Summary
+	timerArmed bool
+}
+
+// New cria um Sampler a partir das opções da config.
+func New(opts *kbx.LogzSamplingOptions) (*Sampler, error) {
+	s := &Sampler{
+		Interval:        defaultInterval,
+		SummaryInterval: defaultInterval,
+		Buckets:         map[kbx.Level]*Bucket{},
+		Probabilities:   map[kbx.Level]float64{},
+		now:             time.Now,
+		rand:            rand.Float64,
+	}
+	if opts == nil {
+		return s, nil
+	}
+
+	var err error
+	if s.Interval, err = parseDuration(opts.Interval, defaultInterval); err != nil {
+		return nil, fmt.Errorf("logz: sampling interval: %w", err)
+	}
+	if s.SummaryInterval, err = parseDuration(opts.SummaryInterval, s.Interval); err != nil {
+		return nil, fmt.Errorf("logz: sampling summary_interval: %w", err)
+	}
+	s.First = opts.First
+	s.Thereafter = opts.Thereafter
+
+	for lvl, rl := range opts.RateLimits {
+		if !kbx.IsLevel(lvl) {
+			return nil, fmt.Errorf("logz: sampling rate_limits: unknown level %q", lvl)
+		}
+		s.Buckets[kbx.ParseLevel(lvl)] = &Bucket{Rate: rl.Rate, Burst: rl.Burst}
+	}
+	for lvl, p := range opts.Probabilities {
+		if !kbx.IsLevel(lvl) {
+			return nil, fmt.Errorf("logz: sampling probabilities: unknown level %q", lvl)
+		}
+		if p < 0 || p > 1 {
+			return nil, fmt.Errorf("logz: sampling probabilities: %q must be between 0 and 1", lvl)
+		}
+		s.Probabilities[kbx.ParseLevel(lvl)] = p
+	}
+	return s, nil
+}
+
+// OnSummary registra quem recebe os resumos periódicos.
+func (s *Sampler) OnSummary(fn func(Summary)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onSummary = fn
+}
+
+// Allow indica se a entry deve ser escrita.
+func (s *Sampler) Allow(e kbx.Entry) bool {
+	if s == nil || e == nil {
+		return true
+	}
+	lvl := e.GetLevel()
+	if lvl.IsTerminal() {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+	if s.allow(lvl, e.GetMessage(), now) {
+		return true
+	}
+	s.suppress(lvl, e.GetMessage(), now)
+	return false
+}
+
+func (s *Sampler) allow(lvl kbx.Level, msg string, now time.Time) bool {
+	if p, ok := s.Probabilities[lvl]; ok && s.random() >= p {
+		return false
+	}
+	if b, ok := s.Buckets[lvl]; ok && !b.take(now) {
+		return false
+	}
+	if s.First <= 0 && s.Thereafter <= 0 {
+		return true
+	}
+
+	if s.counts == nil || now.Sub(s.windowStart) >= s.Interval {
+		s.counts = make(map[string]int)
+		s.windowStart = now
+	}
+	key := string(lvl) + "\x00" + msg
+	s.counts[key]++
+	n := s.counts[key]
+	if n <= s.First {
+		return true
+	}
+	return s.Thereafter > 0 && (n-s.First)%s.Thereafter == 0
+}
+
+func (s *Sampler) suppress(lvl kbx.Level, msg string, now time.Time) {
+	if s.pending.Suppressed == 0 {
+		s.pending = Summary{
+			Start:     now,
+			ByLevel:   map[kbx.Level]int{},
+			ByMessage: map[string]int{},
+		}
+	}
+	s.pending.Suppressed++
+	s.pending.End = now
+	s.pending.ByLevel[lvl]++
+	if _, ok := s.pending.ByMessage[msg]; ok || len(s.pending.ByMessage) < maxSummaryKeys {
+		s.pending.ByMessage[msg]++
+	}
+
+	// o timer só existe enquanto há algo suprimido: logger ocioso não
+	// mantém goroutine nenhuma viva.
+	if !s.timerArmed && s.onSummary != nil && s.SummaryInterval > 0 {
+		s.timerArmed = true
+		time.AfterFunc(s.SummaryInterval, s.emit)
+	}
+}
+
+func (s *Sampler) emit() {
+	if sum, ok := s.Flush(); ok {
+		s.mu.Lock()
+		fn := s.onSummary
+		s.mu.Unlock()
+		if fn != nil {
+			fn(sum)
+		}
+	}
+}
+
+// Flush devolve (e zera) o resumo pendente. Útil no shutdown, pra não
+// perder a contagem da última janela.
+func (s *Sampler) Flush() (Summary, bool) {
+	if s == nil {
+		return Summary{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timerArmed = false
+	if s.pending.Suppressed == 0 {
+		return Summary{}, false
+	}
+	sum := s.pending
+	s.pending = Summary{}
+	return sum, true
+}
+
+// String descreve as políticas ativas (útil em debug).
+func (s *Sampler) String() string {
+	if s == nil {
+		return "Sampler(nil)"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parts := []string{}
+	if s.First > 0 || s.Thereafter > 0 {
+		parts = append(parts, fmt.Sprintf("first=%d thereafter=%d interval=%s", s.First, s.Thereafter, s.Interval))
+	}
+	lvls := make([]string, 0, len(s.Buckets)+len(s.Probabilities))
+	for l, b := range s.Buckets {
+		lvls = append(lvls, fmt.Sprintf("rate[%s]=%g/s burst=%d", l, b.Rate, b.Burst))
+	}
+	for l, p := range s.Probabilities {
+		lvls = append(lvls, fmt.Sprintf("p[%s]=%g", l, p))
+	}
+	sort.Strings(lvls)
+	parts = append(parts, lvls...)
+	return "Sampler(" + strings.Join(parts, " ") + ")"
+}
+
+func (s *Sampler) random() float64 {
+	if s.rand != nil {
+		return s.rand()
+	}
+	return rand.Float64()
+}
+
+func (b *Bucket) take(now time.Time) bool {
+	burst := float64(b.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else {
+		b.tokens += now.Sub(b.last).Seconds() * b.Rate
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func parseDuration(s string, def time.Duration) (time.Duration, error) {
+	if strings.TrimSpace(s) == "" {
+		return def, nil
+	}
+	return time.ParseDuration(s)
+}
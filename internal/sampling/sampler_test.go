@@ -0,0 +1,145 @@
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// fakeEntry só responde o que o Sampler lê.
+type fakeEntry struct {
+	kbx.Entry
+	lvl kbx.Level
+	msg string
+}
+
+func (e fakeEntry) GetLevel() kbx.Level     { return e.lvl }
+func (e fakeEntry) GetMessage() string      { return e.msg }
+func (e fakeEntry) GetTimestamp() time.Time { return time.Time{} }
+
+func entry(t *testing.T, lvl kbx.Level, msg string) kbx.Entry {
+	t.Helper()
+	return fakeEntry{lvl: lvl, msg: msg}
+}
+
+func TestFirstThereafter(t *testing.T) {
+	s, err := New(&kbx.LogzSamplingOptions{First: 2, Thereafter: 3, Interval: "1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(0, 0)
+	s.now = func() time.Time { return now }
+
+	var got []bool
+	for i := 0; i < 8; i++ {
+		got = append(got, s.Allow(entry(t, kbx.LevelInfo, "same")))
+	}
+	want := []bool{true, true, false, false, true, false, false, true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Allow sequence = %v, want %v", got, want)
+		}
+	}
+	// outra mensagem tem contagem própria.
+	if !s.Allow(entry(t, kbx.LevelInfo, "other")) {
+		t.Error("different message was sampled")
+	}
+	// nova janela zera as contagens.
+	now = now.Add(time.Minute)
+	if !s.Allow(entry(t, kbx.LevelInfo, "same")) {
+		t.Error("new window should allow again")
+	}
+
+	sum, ok := s.Flush()
+	if !ok || sum.Suppressed != 4 || sum.ByLevel[kbx.LevelInfo] != 4 || sum.ByMessage["same"] != 4 {
+		t.Errorf("summary = %+v", sum)
+	}
+	if _, ok := s.Flush(); ok {
+		t.Error("second Flush should be empty")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s, err := New(&kbx.LogzSamplingOptions{
+		RateLimits: map[string]kbx.SamplingRate{"error": {Rate: 1, Burst: 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(0, 0)
+	s.now = func() time.Time { return now }
+
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if s.Allow(entry(t, kbx.LevelError, "boom")) {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("burst allowed %d, want 2", allowed)
+	}
+	now = now.Add(time.Second)
+	if !s.Allow(entry(t, kbx.LevelError, "boom")) {
+		t.Error("token should refill after 1s")
+	}
+	if !s.Allow(entry(t, kbx.LevelWarn, "boom")) {
+		t.Error("level without limit was sampled")
+	}
+}
+
+func TestProbability(t *testing.T) {
+	s, err := New(&kbx.LogzSamplingOptions{Probabilities: map[string]float64{"debug": 0.25}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := 0.0
+	s.rand = func() float64 { return r }
+	if !s.Allow(entry(t, kbx.LevelDebug, "x")) {
+		t.Error("rand 0 should pass p=0.25")
+	}
+	r = 0.5
+	if s.Allow(entry(t, kbx.LevelDebug, "x")) {
+		t.Error("rand 0.5 should not pass p=0.25")
+	}
+}
+
+func TestTerminalLevelsNeverSampled(t *testing.T) {
+	opts := &kbx.LogzSamplingOptions{
+		First:         1,
+		Probabilities: map[string]float64{},
+		RateLimits:    map[string]kbx.SamplingRate{},
+	}
+	levels := []kbx.Level{kbx.LevelFatal, kbx.LevelCritical, kbx.LevelPanic}
+	for _, l := range levels {
+		opts.Probabilities[string(l)] = 0
+		opts.RateLimits[string(l)] = kbx.SamplingRate{Rate: 0.001, Burst: 1}
+	}
+	s, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range levels {
+		for i := 0; i < 3; i++ {
+			if !s.Allow(entry(t, l, "down")) {
+				t.Fatalf("%s entry #%d was sampled", l, i)
+			}
+		}
+	}
+	if !s.Allow(entry(t, kbx.LevelError, "down")) || s.Allow(entry(t, kbx.LevelError, "down")) {
+		t.Error("non-terminal levels should still be sampled")
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	bad := []*kbx.LogzSamplingOptions{
+		{Interval: "soon"},
+		{RateLimits: map[string]kbx.SamplingRate{"loud": {Rate: 1}}},
+		{Probabilities: map[string]float64{"info": 2}},
+	}
+	for i, o := range bad {
+		if _, err := New(o); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package writer
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DocumentWriter embrulha um destino cujo conteúdo tem que ser um
+// documento único (ex: XML com prólogo e elemento raiz). O header vai
+// antes da primeira escrita e o footer no Close, no Finish ou na troca de
+// destino (Rotate/SetOutput), então cada arquivo fica válido por si.
+//
+// Sobre um RotatingFile o header e o footer são repassados a ele
+// (SetDocument), que fecha e abre o documento a cada rotação.
+//
+// O destino deve começar vazio: em modo append sobre um documento já
+// fechado o resultado não é XML válido.
+type DocumentWriter struct {
+	mu     sync.Mutex
+	output io.Writer
+	header []byte
+	footer []byte
+	open   bool
+	doc    documentOutput // output (ou o que ele embrulha) cuida do documento
+}
+
+// documentOutput é implementado pelos destinos que escrevem header e
+// footer sozinhos (RotatingFile).
+type documentOutput interface {
+	SetDocument(header, footer []byte)
+	Finish() error
+}
+
+// NewDocumentWriter cria o DocumentWriter sobre w.
+func NewDocumentWriter(w io.Writer, header, footer []byte) *DocumentWriter {
+	d := &DocumentWriter{header: header, footer: footer}
+	d.attach(w)
+	return d
+}
+
+// attach passa a escrever em w, entregando header/footer a ele se for
+// (ou embrulhar, via GetIOWriter) um documentOutput.
+func (d *DocumentWriter) attach(w io.Writer) {
+	d.output, d.doc = w, nil
+	for range 8 { // limite contra embrulhos que devolvem a si mesmos
+		if do, ok := w.(documentOutput); ok {
+			d.doc = do
+			do.SetDocument(d.header, d.footer)
+			return
+		}
+		inner, ok := w.(interface{ GetIOWriter() io.Writer })
+		if !ok {
+			return
+		}
+		w = inner.GetIOWriter()
+	}
+}
+
+// detach devolve ao destino atual o controle do próprio conteúdo.
+func (d *DocumentWriter) detach() {
+	if d.doc != nil {
+		d.doc.SetDocument(nil, nil)
+		d.doc = nil
+	}
+}
+
+func (d *DocumentWriter) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.output == nil {
+		return 0, nil
+	}
+	if d.doc != nil {
+		return d.output.Write(p)
+	}
+	if !d.open {
+		if len(d.header) > 0 {
+			if _, err := d.output.Write(d.header); err != nil {
+				return 0, err
+			}
+		}
+		d.open = true
+	}
+	return d.output.Write(p)
+}
+
+func (d *DocumentWriter) WriteLogz(p []byte) error {
+	_, err := d.Write(p)
+	return err
+}
+
+// Finish fecha o documento corrente (escreve o footer) sem fechar o
+// destino; a próxima escrita abre um documento novo.
+func (d *DocumentWriter) Finish() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.finish()
+}
+
+func (d *DocumentWriter) finish() error {
+	if d.doc != nil {
+		return d.doc.Finish()
+	}
+	if !d.open {
+		return nil
+	}
+	d.open = false
+	if len(d.footer) == 0 || d.output == nil {
+		return nil
+	}
+	_, err := d.output.Write(d.footer)
+	return err
+}
+
+// Rotate fecha o documento no destino atual, fecha o destino (se for um
+// io.Closer) e passa a escrever em next.
+func (d *DocumentWriter) Rotate(next io.Writer) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	err := d.finish()
+	d.detach()
+	if c, ok := d.output.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	d.attach(next)
+	return err
+}
+
+// Close fecha o documento e o destino.
+func (d *DocumentWriter) Close() error {
+	return d.Rotate(nil)
+}
+
+func (d *DocumentWriter) GetIOWriter() io.Writer {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.output
+}
+
+// SetOutput fecha o documento no destino atual e troca o destino, sem
+// fechá-lo (quem passou o destino continua dono dele).
+func (d *DocumentWriter) SetOutput(w io.Writer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_ = d.finish()
+	d.detach()
+	d.attach(w)
+}
+
+func (d *DocumentWriter) GetOutput() io.Writer { return d.GetIOWriter() }
+
+func (d *DocumentWriter) Sync() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if syncer, ok := d.output.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
+
+func (d *DocumentWriter) String() string {
+	return fmt.Sprintf("DocumentWriter(%T)", d.GetIOWriter())
+}
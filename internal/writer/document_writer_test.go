@@ -0,0 +1,155 @@
+package writer_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubex-ecosystem/logz/internal/writer"
+)
+
+const (
+	docHeader = "<logs>\n"
+	docFooter = "</logs>\n"
+	docRecord = "<entry>x</entry>\n"
+)
+
+// checkDocument confere que o arquivo é um documento XML completo com n
+// entries.
+func checkDocument(t *testing.T, path string) int {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(b)
+	if !strings.HasPrefix(s, docHeader) || !strings.HasSuffix(s, docFooter) {
+		t.Fatalf("%s: not a document: %q", filepath.Base(path), s)
+	}
+	dec := xml.NewDecoder(strings.NewReader(s))
+	for {
+		if _, err := dec.Token(); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("%s: %v: %q", filepath.Base(path), err, s)
+		}
+	}
+	return strings.Count(s, "<entry>")
+}
+
+// Cada arquivo rotacionado fecha o próprio documento e o seguinte abre
+// um novo.
+func TestDocumentWriterRotatingFile(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		open func(path string) (io.Writer, error)
+	}{
+		{"RotatingFile", func(path string) (io.Writer, error) {
+			return writer.OpenRotatingFile(path, writer.RotateOptions{MaxSize: 64})
+		}},
+		{"spec", func(path string) (io.Writer, error) {
+			return writer.Open("file://" + path + "?rotate=true&max_size=64")
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.xml")
+			out, err := tc.open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := writer.NewDocumentWriter(out, []byte(docHeader), []byte(docFooter))
+			const n = 10
+			for range n {
+				if _, err := d.Write([]byte(docRecord)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := d.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			files, _ := filepath.Glob(filepath.Join(dir, "app*.xml"))
+			if len(files) < 2 {
+				t.Fatalf("no rotation happened: %v", files)
+			}
+			total := 0
+			for _, f := range files {
+				total += checkDocument(t, f)
+			}
+			if total != n {
+				t.Errorf("got %d entries across %d files, want %d", total, len(files), n)
+			}
+		})
+	}
+}
+
+// Rotate forçado e Finish também fecham o documento.
+func TestRotatingFileDocument(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.xml")
+	rf, err := writer.OpenRotatingFile(path, writer.RotateOptions{
+		Header: []byte(docHeader),
+		Footer: []byte(docFooter),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rf.Write([]byte(docRecord)); err != nil {
+		t.Fatal(err)
+	}
+	if err := rf.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	// arquivo novo sem escrita fica vazio, sem header solto.
+	if b, _ := os.ReadFile(path); len(b) != 0 {
+		t.Errorf("fresh file: %q", b)
+	}
+	if _, err := rf.Write([]byte(docRecord)); err != nil {
+		t.Fatal(err)
+	}
+	if err := rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "app*.xml"))
+	if len(files) != 2 {
+		t.Fatalf("got %v", files)
+	}
+	for _, f := range files {
+		if got := checkDocument(t, f); got != 1 {
+			t.Errorf("%s: %d entries", f, got)
+		}
+	}
+}
+
+// Sem RotatingFile, o DocumentWriter escreve header e footer ele mesmo;
+// SetOutput fecha o documento no destino antigo sem fechá-lo.
+func TestDocumentWriterPlain(t *testing.T) {
+	var a, b strings.Builder
+	d := writer.NewDocumentWriter(&a, []byte(docHeader), []byte(docFooter))
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if a.Len() != 0 {
+		t.Errorf("empty document written: %q", a.String())
+	}
+
+	d = writer.NewDocumentWriter(&a, []byte(docHeader), []byte(docFooter))
+	d.Write([]byte(docRecord))
+	d.SetOutput(&b)
+	d.Write([]byte(docRecord))
+	d.Write([]byte(docRecord))
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := docHeader + docRecord + docFooter; a.String() != want {
+		t.Errorf("first: got %q, want %q", a.String(), want)
+	}
+	if want := docHeader + docRecord + docRecord + docFooter; b.String() != want {
+		t.Errorf("second: got %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,430 @@
+package writer
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateOptions configura o RotatingFile.
+type RotateOptions struct {
+	// MaxSize é o tamanho (bytes) a partir do qual o arquivo é rotacionado;
+	// 0 = nunca rotaciona por tamanho.
+	MaxSize int64
+	// MaxBackups é quantos arquivos rotacionados manter; 0 = todos.
+	MaxBackups int
+	// MaxAge apaga os rotacionados mais velhos que isso; 0 = não apaga.
+	MaxAge time.Duration
+	// Compress grava os rotacionados em gzip (.gz), em segundo plano.
+	Compress bool
+	// Perm é a permissão do arquivo criado (0644 se zero).
+	Perm os.FileMode
+	// Header e Footer abrem e fecham cada arquivo como um documento (ver
+	// SetDocument).
+	Header []byte
+	Footer []byte
+	// Clock dá o horário da rotação (nome do rotacionado e corte do
+	// MaxAge); nil = relógio do sistema. Um kbx.Clock serve.
+	Clock interface{ Now() time.Time }
+}
+
+// systemClock é o relógio padrão do RotatingFile (kbx importa writer, então
+// não dá pra usar kbx.SystemClock aqui).
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// defaultRotateMaxSize vale quando a spec pede rotate sem max_size.
+const defaultRotateMaxSize = 100 << 20
+
+// backupTimeLayout vai no nome dos rotacionados: app-20240501T120000.000.log.
+const backupTimeLayout = "20060102T150405.000"
+
+// RotatingFile é um arquivo de log que, ao passar de MaxSize, é renomeado
+// com o horário da rotação (app.log -> app-20240501T120000.000.log) e
+// reaberto vazio. Compressão e limpeza dos antigos rodam em segundo plano;
+// Close espera as pendentes.
+type RotatingFile struct {
+	mu   sync.Mutex
+	path string
+	opts RotateOptions
+	file *os.File
+	size int64
+
+	header, footer []byte
+	docOpen        bool // header já escrito no arquivo atual
+
+	postMu sync.Mutex     // serializa compressão/limpeza
+	post   sync.WaitGroup // pendentes, esperadas no Close
+	clock  interface{ Now() time.Time }
+}
+
+// OpenRotatingFile abre (ou cria) path em modo append.
+func OpenRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	if opts.Perm == 0 {
+		opts.Perm = 0o644
+	}
+	if opts.Clock == nil {
+		opts.Clock = systemClock{}
+	}
+	f := &RotatingFile{path: path, opts: opts, clock: opts.Clock, header: opts.Header, footer: opts.Footer}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	fh, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, f.opts.Perm)
+	if err != nil {
+		return err
+	}
+	st, err := fh.Stat()
+	if err != nil {
+		fh.Close()
+		return err
+	}
+	f.file, f.size = fh, st.Size()
+	return nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		// fechado: reabre (ex: escrita depois do Close).
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.opts.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.opts.MaxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	if !f.docOpen && len(f.header) > 0 {
+		n, err := f.file.Write(f.header)
+		f.size += int64(n)
+		if err != nil {
+			return 0, err
+		}
+	}
+	f.docOpen = true
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate força a rotação (ex: num SIGHUP).
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+// SetDocument faz de cada arquivo um documento: header antes da primeira
+// escrita de cada arquivo e footer antes de rotacioná-lo ou fechá-lo. É
+// o que o DocumentWriter usa sobre um RotatingFile, pra que os
+// rotacionados também fiquem válidos. nil desliga.
+func (f *RotatingFile) SetDocument(header, footer []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.header, f.footer = header, footer
+}
+
+// Finish escreve o footer no arquivo atual sem rotacioná-lo; a próxima
+// escrita abre um documento novo.
+func (f *RotatingFile) Finish() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.finish()
+}
+
+func (f *RotatingFile) finish() error {
+	if !f.docOpen || f.file == nil {
+		return nil
+	}
+	f.docOpen = false
+	if len(f.footer) == 0 {
+		return nil
+	}
+	n, err := f.file.Write(f.footer)
+	f.size += int64(n)
+	return err
+}
+
+func (f *RotatingFile) rotate() error {
+	if f.file != nil {
+		if err := f.finish(); err != nil {
+			return err
+		}
+		if err := f.file.Close(); err != nil {
+			return err
+		}
+		f.file = nil
+	}
+	backup := f.backupName(f.clock.Now())
+	if err := os.Rename(f.path, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.post.Add(1)
+	go func() {
+		defer f.post.Done()
+		f.postMu.Lock()
+		defer f.postMu.Unlock()
+		if f.opts.Compress {
+			_ = gzipFile(backup)
+		}
+		f.cleanup()
+	}()
+	return nil
+}
+
+// backupName monta o nome do rotacionado; dois no mesmo milissegundo
+// ganham um sufixo.
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, base := filepath.Split(f.path)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	name := filepath.Join(dir, stem+"-"+t.Format(backupTimeLayout)+ext)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			if _, err := os.Stat(name + ".gz"); os.IsNotExist(err) {
+				return name
+			}
+		}
+		name = filepath.Join(dir, fmt.Sprintf("%s-%s.%d%s", stem, t.Format(backupTimeLayout), i, ext))
+	}
+}
+
+// cleanup apaga os rotacionados além de MaxBackups ou mais velhos que
+// MaxAge. Erros são ignorados: a limpeza roda de novo na próxima rotação.
+func (f *RotatingFile) cleanup() {
+	if f.opts.MaxBackups <= 0 && f.opts.MaxAge <= 0 {
+		return
+	}
+	type backup struct {
+		path string
+		t    time.Time
+		seq  int // sufixo de quem rotacionou no mesmo milissegundo
+	}
+	dir, base := filepath.Split(f.path)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+	entries, err := os.ReadDir(filepath.Clean(dir + "."))
+	if err != nil {
+		return
+	}
+	var backups []backup
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".gz")
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		if len(stamp) < len(backupTimeLayout) {
+			continue
+		}
+		t, err := time.ParseInLocation(backupTimeLayout, stamp[:len(backupTimeLayout)], time.Local)
+		if err != nil {
+			continue
+		}
+		seq, _ := strconv.Atoi(strings.TrimPrefix(stamp[len(backupTimeLayout):], "."))
+		backups = append(backups, backup{filepath.Join(dir, e.Name()), t, seq})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].t.Equal(backups[j].t) {
+			return backups[i].t.After(backups[j].t)
+		}
+		return backups[i].seq > backups[j].seq
+	})
+	cutoff := f.clock.Now().Add(-f.opts.MaxAge)
+	for i, b := range backups {
+		if (f.opts.MaxBackups > 0 && i >= f.opts.MaxBackups) || (f.opts.MaxAge > 0 && b.t.Before(cutoff)) {
+			_ = os.Remove(b.path)
+		}
+	}
+}
+
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Close fecha o arquivo (escrevendo o footer, se houver documento aberto)
+// e espera compressões/limpezas pendentes.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.finish()
+		if cerr := f.file.Close(); err == nil {
+			err = cerr
+		}
+		f.file = nil
+	}
+	f.mu.Unlock()
+	f.post.Wait()
+	return err
+}
+
+func (f *RotatingFile) String() string {
+	return "RotatingFile(" + f.path + ")"
+}
+
+// openFile abre "file://" (e caminhos sem scheme). Query:
+//
+//	rotate=true        liga a rotação (max_size padrão: 100MB)
+//	max_size=100MB     tamanho máximo (B, KB, MB, GB; base 1024)
+//	max_backups=7      rotacionados a manter
+//	max_age=7d         idade máxima dos rotacionados (d, h, m; número = dias)
+//	compress=true      gzip nos rotacionados
+//	mode=0600          permissão do arquivo
+func openFile(u *url.URL) (io.Writer, error) {
+	path := u.Path
+	if u.Host != "" && u.Host != "localhost" {
+		// file://./logs/app.log, file://logs/app.log: caminho relativo.
+		path = u.Host + u.Path
+	}
+	if path == "" {
+		return nil, fmt.Errorf("file output without path")
+	}
+	q := u.Query()
+	var opts RotateOptions
+	if m := q.Get("mode"); m != "" {
+		perm, err := strconv.ParseUint(m, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mode %q", m)
+		}
+		opts.Perm = os.FileMode(perm)
+	}
+	rotate, err := queryBool(q, "rotate")
+	if err != nil {
+		return nil, err
+	}
+	if !rotate && q.Get("max_size") == "" {
+		perm := opts.Perm
+		if perm == 0 {
+			perm = 0o644
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perm)
+	}
+	opts.MaxSize = defaultRotateMaxSize
+	if s := q.Get("max_size"); s != "" {
+		if opts.MaxSize, err = ParseSize(s); err != nil {
+			return nil, err
+		}
+	}
+	if s := q.Get("max_backups"); s != "" {
+		if opts.MaxBackups, err = strconv.Atoi(s); err != nil || opts.MaxBackups < 0 {
+			return nil, fmt.Errorf("invalid max_backups %q", s)
+		}
+	}
+	if s := q.Get("max_age"); s != "" {
+		if opts.MaxAge, err = parseAge(s); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Compress, err = queryBool(q, "compress"); err != nil {
+		return nil, err
+	}
+	return OpenRotatingFile(path, opts)
+}
+
+// ParseSize lê tamanhos como "512", "64KB", "100MB", "1GiB" (base 1024).
+func ParseSize(s string) (int64, error) {
+	t := strings.ToUpper(strings.TrimSpace(s))
+	mult := int64(1)
+	for _, u := range []struct {
+		suffix string
+		mult   int64
+	}{
+		{"GIB", 1 << 30}, {"MIB", 1 << 20}, {"KIB", 1 << 10},
+		{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	} {
+		if strings.HasSuffix(t, u.suffix) {
+			t, mult = strings.TrimSpace(strings.TrimSuffix(t, u.suffix)), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
+
+// parseAge lê "7d", "36h", "90m" ou um número de dias.
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	days := strings.TrimSuffix(s, "d")
+	if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid max_age %q", s)
+	}
+	return d, nil
+}
+
+func queryBool(q url.Values, key string) (bool, error) {
+	s := q.Get(key)
+	if s == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q", key, s)
+	}
+	return b, nil
+}
@@ -0,0 +1,185 @@
+package writer_test
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/writer"
+)
+
+// manualClock só anda quando o teste manda; a limpeza roda em outra
+// goroutine, daí o lock.
+type manualClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *manualClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+// rotateAt escreve "gen-N" e rotaciona uma vez por passo, avançando o
+// relógio antes de cada rotação (o primeiro passo costuma ser 0). Fecha o
+// arquivo no fim, o que espera compressões e limpezas pendentes.
+func rotateAt(t *testing.T, opts writer.RotateOptions, clock *manualClock, steps ...time.Duration) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.log")
+	opts.Clock = clock
+	rf, err := writer.OpenRotatingFile(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, d := range steps {
+		clock.Add(d)
+		if _, err := fmt.Fprintf(rf, "gen-%d\n", i+1); err != nil {
+			t.Fatal(err)
+		}
+		if err := rf.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Dir(path)
+}
+
+// backups lê os rotacionados em dir (descomprimindo os .gz): nome -> conteúdo.
+func backups(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := map[string]string{}
+	for _, e := range entries {
+		if e.Name() == "app.log" {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r io.Reader = f
+		if strings.HasSuffix(e.Name(), ".gz") {
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				f.Close()
+				t.Fatalf("%s: %v", e.Name(), err)
+			}
+			r = zr
+		}
+		b, err := io.ReadAll(r)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", e.Name(), err)
+		}
+		out[e.Name()] = string(b)
+	}
+	return out
+}
+
+var t0 = time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+
+// backupName repete o formato dos rotacionados: app-20240501T120000.000[.N].log.
+func backupName(t time.Time, seq int, gz bool) string {
+	name := "app-" + t.Format("20060102T150405.000")
+	if seq > 0 {
+		name += fmt.Sprintf(".%d", seq)
+	}
+	name += ".log"
+	if gz {
+		name += ".gz"
+	}
+	return name
+}
+
+func TestRotatingFileMaxBackups(t *testing.T) {
+	clock := &manualClock{t: t0}
+	dir := rotateAt(t, writer.RotateOptions{MaxBackups: 2}, clock, 0, time.Second, time.Second, time.Second)
+
+	want := map[string]string{
+		backupName(t0.Add(2*time.Second), 0, false): "gen-3\n",
+		backupName(t0.Add(3*time.Second), 0, false): "gen-4\n",
+	}
+	if got := backups(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("backups = %v, want %v", got, want)
+	}
+}
+
+func TestRotatingFileMaxAge(t *testing.T) {
+	clock := &manualClock{t: t0}
+	// rotações em t0, +30m, +90m e +150m; com MaxAge de 1h, o corte fica em
+	// +90m e só os dois primeiros ficam velhos demais.
+	dir := rotateAt(t, writer.RotateOptions{MaxAge: time.Hour}, clock,
+		0, 30*time.Minute, time.Hour, time.Hour)
+
+	want := map[string]string{
+		backupName(t0.Add(90*time.Minute), 0, false):  "gen-3\n",
+		backupName(t0.Add(150*time.Minute), 0, false): "gen-4\n",
+	}
+	if got := backups(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("backups = %v, want %v", got, want)
+	}
+}
+
+func TestRotatingFileCompress(t *testing.T) {
+	clock := &manualClock{t: t0}
+	dir := rotateAt(t, writer.RotateOptions{Compress: true}, clock, 0, time.Minute)
+
+	want := map[string]string{
+		backupName(t0, 0, true):                  "gen-1\n",
+		backupName(t0.Add(time.Minute), 0, true): "gen-2\n",
+	}
+	if got := backups(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("backups = %v, want %v", got, want)
+	}
+}
+
+// Rotações no mesmo milissegundo ganham .1, .2...; o sufixo maior é o
+// mais novo, inclusive pro MaxBackups, e um .gz já existente ocupa o nome.
+func TestRotatingFileSameMillisecond(t *testing.T) {
+	tests := []struct {
+		name string
+		opts writer.RotateOptions
+		want map[string]string
+	}{
+		{"all kept", writer.RotateOptions{}, map[string]string{
+			backupName(t0, 0, false): "gen-1\n",
+			backupName(t0, 1, false): "gen-2\n",
+			backupName(t0, 2, false): "gen-3\n",
+		}},
+		{"newest kept", writer.RotateOptions{MaxBackups: 2}, map[string]string{
+			backupName(t0, 1, false): "gen-2\n",
+			backupName(t0, 2, false): "gen-3\n",
+		}},
+		{"compressed", writer.RotateOptions{MaxBackups: 2, Compress: true}, map[string]string{
+			backupName(t0, 1, true): "gen-2\n",
+			backupName(t0, 2, true): "gen-3\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := rotateAt(t, tt.opts, &manualClock{t: t0}, 0, 0, 0)
+			if got := backups(t, dir); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("backups = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
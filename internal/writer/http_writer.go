@@ -0,0 +1,524 @@
+package writer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Codificações do corpo do HTTPWriter.
+const (
+	HTTPEncodingNDJSON = "ndjson"
+	HTTPEncodingJSON   = "json"
+)
+
+// Padrões do HTTPWriter.
+const (
+	defaultHTTPBatchCount = 100
+	defaultHTTPBatchBytes = 1 << 20
+	defaultHTTPInterval   = time.Second
+	defaultHTTPTimeout    = 10 * time.Second
+	defaultHTTPRetries    = 3
+	defaultHTTPQueue      = 4096
+)
+
+// ErrHTTPClosed é devolvido por escritas depois do Close.
+var ErrHTTPClosed = errors.New("logz: http writer closed")
+
+// HTTPOptions configura o HTTPWriter.
+type HTTPOptions struct {
+	// URL é o endpoint que recebe os POSTs.
+	URL string
+	// Encoding é "ndjson" (um registro por linha, padrão) ou "json" (array;
+	// registros que não são JSON entram como string).
+	Encoding string
+	// ContentType troca o Content-Type derivado do Encoding.
+	ContentType string
+	// Headers vão em todo POST; BearerToken vira "Authorization: Bearer".
+	Headers     map[string]string
+	BearerToken string
+	// Um lote é enviado ao chegar a BatchCount registros ou BatchBytes
+	// bytes, ou a cada FlushInterval (100, 1MB e 1s se zero).
+	BatchCount    int
+	BatchBytes    int
+	FlushInterval time.Duration
+	// Gzip comprime o corpo (Content-Encoding: gzip).
+	Gzip bool
+	// MaxRetries é quantas vezes repetir um lote em 5xx, 429 ou erro de
+	// rede (3 se zero, negativo = nenhuma). A espera dobra a partir de
+	// BackoffMin até BackoffMax (500ms e 30s se zero); Retry-After da
+	// resposta tem prioridade.
+	MaxRetries int
+	BackoffMin time.Duration
+	BackoffMax time.Duration
+	// Timeout limita cada POST (10s se zero).
+	Timeout time.Duration
+	// QueueSize limita os registros esperando envio (4096 se zero); com a
+	// fila cheia eles são descartados e contados em Dropped.
+	QueueSize int
+	// Client troca o http.Client (ex: transporte com mTLS).
+	Client *http.Client
+}
+
+// HTTPWriter junta os registros em lotes e os envia por POST pra um
+// coletor genérico (webhook, Vector, Fluent Bit, Loki push via proxy...).
+// Quem loga nunca espera a rede: os registros entram numa fila limitada e
+// uma goroutine monta e envia os lotes, com retry.
+type HTTPWriter struct {
+	opts      HTTPOptions
+	url       *url.URL
+	onError   atomic.Pointer[func(error)]
+	dropped   atomic.Int64
+	queue     chan []byte
+	flushReq  chan chan error
+	done      chan struct{}
+	closeOnce sync.Once
+	closeMu   sync.RWMutex
+	closed    bool
+	sleep     func(time.Duration)
+}
+
+// NewHTTPWriter valida as opções e inicia o envio em background.
+func NewHTTPWriter(opts HTTPOptions) (*HTTPWriter, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("http output needs an http(s) URL with host, got %q", u.Redacted())
+	}
+	opts.Encoding = strings.ToLower(opts.Encoding)
+	switch opts.Encoding {
+	case "":
+		opts.Encoding = HTTPEncodingNDJSON
+	case HTTPEncodingNDJSON, HTTPEncodingJSON:
+	default:
+		return nil, fmt.Errorf("http output encoding %q (want ndjson or json)", opts.Encoding)
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = "application/x-ndjson"
+		if opts.Encoding == HTTPEncodingJSON {
+			opts.ContentType = "application/json"
+		}
+	}
+	if opts.BatchCount <= 0 {
+		opts.BatchCount = defaultHTTPBatchCount
+	}
+	if opts.BatchBytes <= 0 {
+		opts.BatchBytes = defaultHTTPBatchBytes
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultHTTPInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultHTTPTimeout
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultHTTPRetries
+	} else if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.BackoffMin <= 0 {
+		opts.BackoffMin = 500 * time.Millisecond
+	}
+	if opts.BackoffMax < opts.BackoffMin {
+		opts.BackoffMax = max(defaultBackoffMax, opts.BackoffMin)
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultHTTPQueue
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{}
+	}
+	w := &HTTPWriter{
+		opts:     opts,
+		url:      u,
+		queue:    make(chan []byte, opts.QueueSize),
+		flushReq: make(chan chan error),
+		done:     make(chan struct{}),
+		sleep:    time.Sleep,
+	}
+	go w.run()
+	return w, nil
+}
+
+// OnError registra quem recebe as falhas de envio (lote descartado depois
+// de esgotar os retries). Por padrão elas vão pro stderr.
+func (w *HTTPWriter) OnError(fn func(error)) {
+	w.onError.Store(&fn)
+}
+
+// Dropped devolve quantos registros foram descartados (fila cheia ou
+// falha definitiva no envio).
+func (w *HTTPWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Write enfileira um registro (uma escrita do logger = um registro).
+func (w *HTTPWriter) Write(p []byte) (int, error) {
+	rec := bytes.TrimRight(p, "\r\n")
+	if len(bytes.TrimSpace(rec)) == 0 {
+		return len(p), nil
+	}
+	w.closeMu.RLock()
+	defer w.closeMu.RUnlock()
+	if w.closed {
+		return 0, ErrHTTPClosed
+	}
+	select {
+	case w.queue <- bytes.Clone(rec):
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+func (w *HTTPWriter) WriteLogz(p []byte) error {
+	_, err := w.Write(p)
+	return err
+}
+
+func (w *HTTPWriter) GetIOWriter() io.Writer { return w }
+func (w *HTTPWriter) GetOutput() io.Writer   { return w }
+
+// SetOutput não se aplica: o destino é o endpoint.
+func (w *HTTPWriter) SetOutput(io.Writer) {}
+
+// Flush envia imediatamente o que estiver na fila e espera o resultado.
+func (w *HTTPWriter) Flush() error {
+	w.closeMu.RLock()
+	closed := w.closed
+	w.closeMu.RUnlock()
+	if closed {
+		return nil
+	}
+	res := make(chan error, 1)
+	select {
+	case w.flushReq <- res:
+		return <-res
+	case <-w.done:
+		return nil
+	}
+}
+
+// Sync é um alias de Flush.
+func (w *HTTPWriter) Sync() error {
+	return w.Flush()
+}
+
+// Close envia o que falta e encerra o writer.
+func (w *HTTPWriter) Close() error {
+	w.closeOnce.Do(func() {
+		w.closeMu.Lock()
+		w.closed = true
+		close(w.queue)
+		w.closeMu.Unlock()
+		<-w.done
+		w.opts.Client.CloseIdleConnections()
+	})
+	return nil
+}
+
+func (w *HTTPWriter) String() string {
+	return "HTTPWriter(" + w.url.Redacted() + ", " + w.opts.Encoding + ")"
+}
+
+func (w *HTTPWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	var batch [][]byte
+	size := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := w.send(batch)
+		batch, size = nil, 0
+		return err
+	}
+	add := func(rec []byte) {
+		// o lote não passa de BatchBytes; um registro maior vai sozinho.
+		if len(batch) > 0 && size+len(rec)+1 > w.opts.BatchBytes {
+			w.report(flush())
+		}
+		batch = append(batch, rec)
+		size += len(rec) + 1
+		if len(batch) >= w.opts.BatchCount || size >= w.opts.BatchBytes {
+			w.report(flush())
+		}
+	}
+
+	for {
+		select {
+		case rec, ok := <-w.queue:
+			if !ok {
+				w.report(flush())
+				return
+			}
+			add(rec)
+		case <-ticker.C:
+			w.report(flush())
+		case res := <-w.flushReq:
+			// drena o que já está na fila antes de responder.
+			for drained := false; !drained; {
+				select {
+				case rec, ok := <-w.queue:
+					if !ok {
+						drained = true
+						break
+					}
+					add(rec)
+				default:
+					drained = true
+				}
+			}
+			res <- flush()
+		}
+	}
+}
+
+func (w *HTTPWriter) report(err error) {
+	if err == nil {
+		return
+	}
+	if fn := w.onError.Load(); fn != nil && *fn != nil {
+		(*fn)(err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "logz: http output: %v\n", err)
+}
+
+func (w *HTTPWriter) send(batch [][]byte) error {
+	body, err := w.encode(batch)
+	if err != nil {
+		w.dropped.Add(int64(len(batch)))
+		return err
+	}
+	var lastErr error
+	for attempt := 0; attempt <= w.opts.MaxRetries; attempt++ {
+		retryAfter, err := w.post(body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		var perm *permanentHTTPError
+		if errors.As(err, &perm) || attempt == w.opts.MaxRetries {
+			break
+		}
+		w.sleep(w.backoff(attempt, retryAfter))
+	}
+	w.dropped.Add(int64(len(batch)))
+	return fmt.Errorf("dropping %d records: %w", len(batch), lastErr)
+}
+
+func (w *HTTPWriter) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, w.opts.BackoffMax)
+	}
+	return min(w.opts.BackoffMin<<attempt, w.opts.BackoffMax)
+}
+
+// encode monta o corpo: NDJSON, ou array JSON em que registros JSON
+// entram como estão e o resto como string.
+func (w *HTTPWriter) encode(batch [][]byte) ([]byte, error) {
+	var raw bytes.Buffer
+	if w.opts.Encoding == HTTPEncodingJSON {
+		raw.WriteByte('[')
+		for i, rec := range batch {
+			if i > 0 {
+				raw.WriteByte(',')
+			}
+			if json.Valid(rec) {
+				if err := json.Compact(&raw, rec); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			s, err := json.Marshal(string(rec))
+			if err != nil {
+				return nil, err
+			}
+			raw.Write(s)
+		}
+		raw.WriteByte(']')
+	} else {
+		for _, rec := range batch {
+			raw.Write(rec)
+			raw.WriteByte('\n')
+		}
+	}
+	if !w.opts.Gzip {
+		return raw.Bytes(), nil
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(raw.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// permanentHTTPError marca respostas que não adianta repetir (4xx fora o
+// 429, URL inválida...).
+type permanentHTTPError struct{ err error }
+
+func (e *permanentHTTPError) Error() string { return e.err.Error() }
+func (e *permanentHTTPError) Unwrap() error { return e.err }
+
+func (w *HTTPWriter) post(body []byte) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return 0, &permanentHTTPError{err}
+	}
+	req.Header.Set("Content-Type", w.opts.ContentType)
+	if w.opts.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range w.opts.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.opts.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+w.opts.BearerToken)
+	}
+
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+	err = fmt.Errorf("endpoint answered %s", resp.Status)
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return 0, &permanentHTTPError{err}
+	}
+	return retryAfter(resp.Header.Get("Retry-After")), err
+}
+
+// retryAfter lê o Retry-After em segundos ou como data HTTP.
+func retryAfter(s string) time.Duration {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	if n, err := strconv.Atoi(s); err == nil && n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	if t, err := http.ParseTime(s); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// openHTTP abre http:// e https://. Os parâmetros com prefixo "logz_" são
+// da logz e saem da URL; o resto vai pro endpoint:
+//
+//	logz_encoding=ndjson|json    corpo (padrão ndjson)
+//	logz_content_type=...        troca o Content-Type
+//	logz_batch_count=100         registros por lote
+//	logz_batch_bytes=1MB         bytes por lote
+//	logz_flush_interval=1s       envio periódico
+//	logz_gzip=true               corpo em gzip
+//	logz_bearer=TOKEN            Authorization: Bearer TOKEN
+//	logz_bearer_env=VAR          token lido da variável de ambiente VAR
+//	logz_header=Nome:valor       header extra (repetível)
+//	logz_retries=3, logz_timeout=10s, logz_queue=4096
+//	logz_backoff_min=500ms, logz_backoff_max=30s
+func openHTTP(u *url.URL) (io.Writer, error) {
+	q := u.Query()
+	opts := HTTPOptions{
+		Encoding:    q.Get("logz_encoding"),
+		ContentType: q.Get("logz_content_type"),
+		BearerToken: q.Get("logz_bearer"),
+	}
+	if env := q.Get("logz_bearer_env"); env != "" {
+		opts.BearerToken = os.Getenv(env)
+		if opts.BearerToken == "" {
+			return nil, fmt.Errorf("logz_bearer_env: %s is empty", env)
+		}
+	}
+	for _, h := range q["logz_header"] {
+		k, v, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("invalid logz_header %q (want Name:value)", h)
+		}
+		if opts.Headers == nil {
+			opts.Headers = map[string]string{}
+		}
+		opts.Headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	var err error
+	for _, n := range []struct {
+		key string
+		dst *int
+	}{
+		{"logz_batch_count", &opts.BatchCount},
+		{"logz_retries", &opts.MaxRetries},
+		{"logz_queue", &opts.QueueSize},
+	} {
+		if s := q.Get(n.key); s != "" {
+			if *n.dst, err = strconv.Atoi(s); err != nil {
+				return nil, fmt.Errorf("invalid %s %q", n.key, s)
+			}
+		}
+	}
+	if s := q.Get("logz_batch_bytes"); s != "" {
+		size, err := ParseSize(s)
+		if err != nil {
+			return nil, err
+		}
+		opts.BatchBytes = int(size)
+	}
+	for _, d := range []struct {
+		key string
+		dst *time.Duration
+	}{
+		{"logz_flush_interval", &opts.FlushInterval},
+		{"logz_timeout", &opts.Timeout},
+		{"logz_backoff_min", &opts.BackoffMin},
+		{"logz_backoff_max", &opts.BackoffMax},
+	} {
+		if s := q.Get(d.key); s != "" {
+			v, err := time.ParseDuration(s)
+			if err != nil || v < 0 {
+				return nil, fmt.Errorf("invalid %s %q", d.key, s)
+			}
+			*d.dst = v
+		}
+	}
+	if opts.Gzip, err = queryBool(q, "logz_gzip"); err != nil {
+		return nil, err
+	}
+	for k := range q {
+		if strings.HasPrefix(k, "logz_") {
+			q.Del(k)
+		}
+	}
+	target := *u
+	target.RawQuery = q.Encode()
+	opts.URL = target.String()
+	return NewHTTPWriter(opts)
+}
@@ -0,0 +1,256 @@
+package writer_test
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/writer"
+)
+
+// collected é um POST recebido pelo collector.
+type collected struct {
+	header http.Header
+	query  string
+	body   string
+}
+
+// collector sobe um endpoint que guarda os POSTs; status decide a
+// resposta de cada tentativa (nil = 200).
+func collector(t *testing.T, status func(attempt int) int) (*httptest.Server, func() []collected) {
+	t.Helper()
+	var mu sync.Mutex
+	var got []collected
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		n := int(attempts.Add(1))
+		if status != nil {
+			if code := status(n); code != http.StatusOK {
+				rw.WriteHeader(code)
+				return
+			}
+		}
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("gzip: %v", err)
+				return
+			}
+			body = zr
+		}
+		b, _ := io.ReadAll(body)
+		mu.Lock()
+		got = append(got, collected{r.Header.Clone(), r.URL.RawQuery, string(b)})
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []collected {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]collected(nil), got...)
+	}
+}
+
+// Pela spec: lotes por contagem, gzip, headers e os parâmetros logz_
+// fora da URL do endpoint.
+func TestHTTPWriterNDJSON(t *testing.T) {
+	srv, posts := collector(t, nil)
+	t.Setenv("LOGZ_TEST_TOKEN", "s3cret")
+	w, err := writer.Open(srv.URL + "/ingest?tenant=a&logz_batch_count=2&logz_gzip=true" +
+		"&logz_bearer_env=LOGZ_TEST_TOKEN&logz_header=X-Team:core&logz_flush_interval=1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range []string{`{"msg":"a"}` + "\n", `{"msg":"b"}`, "\n", `{"msg":"c"}` + "\n"} {
+		if _, err := w.Write([]byte(rec)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("late")); !errors.Is(err, writer.ErrHTTPClosed) {
+		t.Errorf("write after Close: %v", err)
+	}
+
+	got := posts()
+	if len(got) != 2 {
+		t.Fatalf("got %d POSTs, want 2: %+v", len(got), got)
+	}
+	if got[0].body != "{\"msg\":\"a\"}\n{\"msg\":\"b\"}\n" || got[1].body != "{\"msg\":\"c\"}\n" {
+		t.Errorf("bodies: %q, %q", got[0].body, got[1].body)
+	}
+	h := got[0].header
+	for k, want := range map[string]string{
+		"Content-Type":     "application/x-ndjson",
+		"Content-Encoding": "gzip",
+		"Authorization":    "Bearer s3cret",
+		"X-Team":           "core",
+	} {
+		if h.Get(k) != want {
+			t.Errorf("%s: got %q, want %q", k, h.Get(k), want)
+		}
+	}
+	if got[0].query != "tenant=a" {
+		t.Errorf("query: got %q", got[0].query)
+	}
+}
+
+// Em json o lote vira um array; o que não é JSON entra como string.
+func TestHTTPWriterJSONArray(t *testing.T) {
+	srv, posts := collector(t, nil)
+	w, err := writer.NewHTTPWriter(writer.HTTPOptions{
+		URL:           srv.URL,
+		Encoding:      "JSON",
+		FlushInterval: time.Hour,
+		BearerToken:   "tok",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte("{ \"a\": 1 }\n"))
+	w.Write([]byte("plain \"text\"\n"))
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	got := posts()
+	if len(got) != 1 {
+		t.Fatalf("got %d POSTs", len(got))
+	}
+	if want := `[{"a":1},"plain \"text\""]`; got[0].body != want {
+		t.Errorf("got %s, want %s", got[0].body, want)
+	}
+	if ct := got[0].header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type: %q", ct)
+	}
+	if auth := got[0].header.Get("Authorization"); auth != "Bearer tok" {
+		t.Errorf("Authorization: %q", auth)
+	}
+}
+
+func TestHTTPWriterRetry(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		status   func(int) int
+		retries  int
+		attempts int32
+		dropped  int64
+	}{
+		{"recovers", func(n int) int {
+			return []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}[min(n, 3)-1]
+		}, 3, 3, 0},
+		{"exhausted", func(int) int { return http.StatusInternalServerError }, 2, 3, 2},
+		{"permanent", func(int) int { return http.StatusBadRequest }, 3, 1, 2},
+		{"no retries", func(int) int { return http.StatusBadGateway }, -1, 1, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv, _ := collector(t, func(n int) int {
+				attempts.Add(1)
+				return tc.status(n)
+			})
+			w, err := writer.NewHTTPWriter(writer.HTTPOptions{
+				URL:           srv.URL,
+				FlushInterval: time.Hour,
+				MaxRetries:    tc.retries,
+				BackoffMin:    time.Millisecond,
+				BackoffMax:    time.Millisecond, // limita também o Retry-After
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var reported []error
+			w.OnError(func(err error) { reported = append(reported, err) })
+			w.Write([]byte("a"))
+			w.Write([]byte("b"))
+			// Flush devolve a falha a quem chamou.
+			err = w.Flush()
+			if got := attempts.Load(); got != tc.attempts {
+				t.Errorf("attempts: got %d, want %d", got, tc.attempts)
+			}
+			if got := w.Dropped(); got != tc.dropped {
+				t.Errorf("dropped: got %d, want %d", got, tc.dropped)
+			}
+			if (tc.dropped > 0) != (err != nil) {
+				t.Errorf("Flush: %v", err)
+			}
+
+			// em background (aqui, no Close) ela vai pro OnError.
+			w.Write([]byte("c"))
+			w.Close()
+			if want := tc.dropped > 0; len(reported) > 1 || (len(reported) == 1) != want {
+				t.Errorf("OnError: %v", reported)
+			}
+		})
+	}
+}
+
+// Com o envio travado e a fila cheia, Write não bloqueia: descarta e
+// conta.
+func TestHTTPWriterQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	srv, _ := collector(t, func(int) int {
+		<-release
+		return http.StatusOK
+	})
+	w, err := writer.NewHTTPWriter(writer.HTTPOptions{
+		URL:        srv.URL,
+		BatchCount: 1,
+		QueueSize:  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 5 {
+			w.Write([]byte("x"))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write blocked on a full queue")
+	}
+	// no máximo um em voo e um na fila.
+	if got := w.Dropped(); got < 3 {
+		t.Errorf("dropped: got %d, want >= 3", got)
+	}
+	close(release)
+	w.Close()
+}
+
+func TestHTTPWriterOptionErrors(t *testing.T) {
+	for _, opts := range []writer.HTTPOptions{
+		{URL: "ftp://host/x"},
+		{URL: "http://"},
+		{URL: "http://host", Encoding: "xml"},
+	} {
+		if w, err := writer.NewHTTPWriter(opts); err == nil {
+			w.Close()
+			t.Errorf("%+v: want error", opts)
+		}
+	}
+	for _, q := range []string{
+		"logz_header=NoColon",
+		"logz_bearer_env=LOGZ_TEST_UNSET_VAR",
+		"logz_batch_count=many",
+		"logz_batch_bytes=big",
+		"logz_timeout=-1s",
+		"logz_gzip=perhaps",
+	} {
+		if w, err := writer.Open("http://127.0.0.1:1/x?" + q); err == nil {
+			w.Close()
+			t.Errorf("%s: want error", q)
+		}
+	}
+}
@@ -0,0 +1,475 @@
+package writer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Framing diz como os registros são delimitados no fluxo.
+type Framing int
+
+const (
+	// FrameNone escreve o registro como veio (datagramas: um por pacote).
+	FrameNone Framing = iota
+	// FrameNewline termina cada registro com '\n' (se ainda não tiver).
+	FrameNewline
+	// FrameOctet prefixa o tamanho em decimal e um espaço (RFC 6587),
+	// sem o '\n' final.
+	FrameOctet
+)
+
+// Padrões do NetWriter.
+const (
+	defaultDialTimeout  = 5 * time.Second
+	defaultWriteTimeout = 5 * time.Second
+	defaultNetBuffer    = 1 << 20
+	defaultBackoffMin   = 100 * time.Millisecond
+	defaultBackoffMax   = 30 * time.Second
+)
+
+// NetOptions configura o NetWriter.
+type NetOptions struct {
+	// Network é "tcp", "udp", "unix" (stream) ou "unixgram".
+	Network string
+	// Addr é host:port, ou o caminho do socket unix.
+	Addr string
+	// TLS liga TLS sobre tcp.
+	TLS *tls.Config
+	// Framing delimita os registros; o padrão (FrameNone) em stream vira
+	// FrameNewline, use FrameOctet ou RawStream pra mudar.
+	Framing Framing
+	// RawStream mantém FrameNone em stream (ex: saída binária já
+	// enquadrada por tamanho).
+	RawStream bool
+	// BufferSize é o máximo de bytes guardados em memória enquanto
+	// desconectado (os mais antigos são descartados); 0 = 1MB, negativo =
+	// sem buffer (a escrita desconectada falha).
+	BufferSize int
+	// DialTimeout e WriteTimeout limitam conexão e escrita (5s se zero).
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+	// BackoffMin e BackoffMax limitam a espera entre tentativas de
+	// reconexão, que dobra a cada falha (100ms e 30s se zero).
+	BackoffMin time.Duration
+	BackoffMax time.Duration
+}
+
+// NetWriter escreve cada registro numa conexão tcp (opcionalmente TLS),
+// udp ou unix. Se a conexão cai, os registros vão pra um buffer limitado
+// em memória e uma goroutine reconecta com backoff exponencial; ao
+// reconectar o buffer é reenviado em ordem, antes dos registros novos.
+type NetWriter struct {
+	mu         sync.Mutex
+	opts       NetOptions
+	conn       net.Conn
+	queue      [][]byte
+	queued     int
+	dropped    uint64
+	reconnects uint64
+	retrying   bool
+	closed     bool
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+// DialNet conecta no destino; a falha da primeira conexão é erro. Depois
+// disso, quedas são tratadas com buffer e reconexão.
+func DialNet(opts NetOptions) (*NetWriter, error) {
+	if opts.TLS != nil && opts.Network != "tcp" {
+		return nil, fmt.Errorf("tls needs tcp, not %q", opts.Network)
+	}
+	if opts.Framing == FrameNone && !opts.RawStream && isStream(opts.Network) {
+		opts.Framing = FrameNewline
+	}
+	if opts.BufferSize == 0 {
+		opts.BufferSize = defaultNetBuffer
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = defaultDialTimeout
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = defaultWriteTimeout
+	}
+	if opts.BackoffMin <= 0 {
+		opts.BackoffMin = defaultBackoffMin
+	}
+	if opts.BackoffMax < opts.BackoffMin {
+		opts.BackoffMax = max(defaultBackoffMax, opts.BackoffMin)
+	}
+	w := &NetWriter{opts: opts, done: make(chan struct{})}
+	conn, err := w.dial()
+	if err != nil {
+		return nil, err
+	}
+	w.setConn(conn)
+	return w, nil
+}
+
+// setConn passa a usar conn. Em stream, uma goroutine fica lendo a
+// conexão: o destino não manda nada, então EOF/erro na leitura é queda, e
+// os próximos registros vão pro buffer em vez de sumir num socket morto.
+// Deve ser chamado com w.mu adquirido (ou antes do writer ser publicado).
+func (w *NetWriter) setConn(conn net.Conn) {
+	w.conn = conn
+	if !isStream(w.opts.Network) {
+		return
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		_, _ = io.Copy(io.Discard, conn)
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.conn == conn {
+			conn.Close()
+			w.conn = nil
+			w.reconnect()
+		}
+	}()
+}
+
+func isStream(network string) bool {
+	return network == "tcp" || network == "unix"
+}
+
+func (w *NetWriter) dial() (net.Conn, error) {
+	d := &net.Dialer{Timeout: w.opts.DialTimeout}
+	if w.opts.TLS != nil {
+		return tls.DialWithDialer(d, w.opts.Network, w.opts.Addr, w.opts.TLS)
+	}
+	return d.Dial(w.opts.Network, w.opts.Addr)
+}
+
+// frame aplica o Framing num registro.
+func (w *NetWriter) frame(p []byte) []byte {
+	switch w.opts.Framing {
+	case FrameNewline:
+		if len(p) > 0 && p[len(p)-1] == '\n' {
+			return bytes.Clone(p)
+		}
+		return append(bytes.Clone(p), '\n')
+	case FrameOctet:
+		msg := bytes.TrimRight(p, "\n")
+		out := make([]byte, 0, len(msg)+8)
+		out = strconv.AppendInt(out, int64(len(msg)), 10)
+		out = append(out, ' ')
+		return append(out, msg...)
+	default:
+		return bytes.Clone(p)
+	}
+}
+
+func (w *NetWriter) Write(p []byte) (int, error) {
+	frame := w.frame(p)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, net.ErrClosed
+	}
+	if w.conn != nil && len(w.queue) == 0 {
+		err := w.send(w.conn, frame)
+		if err == nil {
+			return len(p), nil
+		}
+		w.conn.Close()
+		w.conn = nil
+		if w.opts.BufferSize < 0 {
+			w.reconnect()
+			return 0, err
+		}
+	}
+	if w.opts.BufferSize < 0 {
+		w.reconnect()
+		return 0, fmt.Errorf("logz: %s://%s: disconnected", w.opts.Network, w.opts.Addr)
+	}
+	w.enqueue(frame)
+	w.reconnect()
+	return len(p), nil
+}
+
+func (w *NetWriter) send(conn net.Conn, frame []byte) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(w.opts.WriteTimeout))
+	_, err := conn.Write(frame)
+	return err
+}
+
+// enqueue guarda o registro, descartando os mais antigos se passar do
+// BufferSize. Deve ser chamado com w.mu adquirido.
+func (w *NetWriter) enqueue(frame []byte) {
+	if len(frame) > w.opts.BufferSize {
+		w.dropped++
+		return
+	}
+	for w.queued+len(frame) > w.opts.BufferSize {
+		w.queued -= len(w.queue[0])
+		w.queue[0] = nil
+		w.queue = w.queue[1:]
+		w.dropped++
+	}
+	w.queue = append(w.queue, frame)
+	w.queued += len(frame)
+}
+
+// reconnect dispara a goroutine de reconexão, se ainda não estiver
+// rodando. Deve ser chamado com w.mu adquirido.
+func (w *NetWriter) reconnect() {
+	if w.retrying || w.closed {
+		return
+	}
+	w.retrying = true
+	w.wg.Add(1)
+	go w.retry()
+}
+
+func (w *NetWriter) retry() {
+	defer w.wg.Done()
+	backoff := w.opts.BackoffMin
+	for {
+		// jitter: espera entre metade e o total do backoff.
+		wait := backoff/2 + rand.N(backoff/2+1)
+		select {
+		case <-w.done:
+			return
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, w.opts.BackoffMax)
+
+		conn, err := w.dial()
+		if err != nil {
+			continue
+		}
+		w.mu.Lock()
+		if w.closed {
+			w.mu.Unlock()
+			conn.Close()
+			return
+		}
+		if err := w.flush(conn); err != nil {
+			w.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		w.retrying = false
+		w.setConn(conn)
+		w.reconnects++
+		w.mu.Unlock()
+		return
+	}
+}
+
+// flush reenvia o buffer em ordem; o que não foi enviado fica. Deve ser
+// chamado com w.mu adquirido.
+func (w *NetWriter) flush(conn net.Conn) error {
+	for len(w.queue) > 0 {
+		if err := w.send(conn, w.queue[0]); err != nil {
+			return err
+		}
+		w.queued -= len(w.queue[0])
+		w.queue[0] = nil
+		w.queue = w.queue[1:]
+	}
+	return nil
+}
+
+// NetStats é o estado do NetWriter.
+type NetStats struct {
+	Connected  bool
+	Buffered   int    // registros no buffer
+	Dropped    uint64 // descartados por buffer cheio
+	Reconnects uint64
+}
+
+func (w *NetWriter) Stats() NetStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return NetStats{
+		Connected:  w.conn != nil,
+		Buffered:   len(w.queue),
+		Dropped:    w.dropped,
+		Reconnects: w.reconnects,
+	}
+}
+
+// Close para a reconexão, tenta enviar o que estiver no buffer se houver
+// conexão e fecha. O que sobrar no buffer é perdido.
+func (w *NetWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+	var err error
+	if w.conn != nil {
+		_ = w.flush(w.conn)
+		err = w.conn.Close()
+		w.conn = nil
+	}
+	w.mu.Unlock()
+	w.wg.Wait()
+	return err
+}
+
+func (w *NetWriter) WriteLogz(p []byte) error {
+	_, err := w.Write(p)
+	return err
+}
+
+func (w *NetWriter) GetIOWriter() io.Writer { return w }
+func (w *NetWriter) GetOutput() io.Writer   { return w }
+
+// SetOutput não se aplica: o destino é a conexão.
+func (w *NetWriter) SetOutput(io.Writer) {}
+
+// Sync não tem o que fazer: conectado, o buffer está vazio.
+func (w *NetWriter) Sync() error { return nil }
+
+func (w *NetWriter) String() string {
+	return "NetWriter(" + w.opts.Network + "://" + w.opts.Addr + ")"
+}
+
+// openNet abre tcp://host:port, tls://host:port (ou tcp+tls://),
+// udp://host:port, unix:///caminho.sock e unixgram:///caminho.sock.
+// Query:
+//
+//	framing=newline|octet|none  padrão: newline em stream, none em datagrama
+//	buffer=1MB                  buffer enquanto desconectado (0 = sem)
+//	backoff_min=100ms           espera inicial da reconexão
+//	backoff_max=30s             espera máxima da reconexão
+//	dial_timeout=5s, write_timeout=5s
+//	tls=true                    TLS sobre tcp (implícito em tls://)
+//	tls_ca=ca.pem               CAs do servidor (padrão: as do sistema)
+//	tls_cert=c.pem&tls_key=k.pem certificado do cliente
+//	tls_server_name=host        nome esperado no certificado
+//	tls_insecure=true           não verifica o certificado
+func openNet(u *url.URL) (io.Writer, error) {
+	opts, err := netOptions(u)
+	if err != nil {
+		return nil, err
+	}
+	return DialNet(opts)
+}
+
+func netOptions(u *url.URL) (NetOptions, error) {
+	var opts NetOptions
+	q := u.Query()
+	scheme := strings.ToLower(u.Scheme)
+	useTLS := scheme == "tls" || scheme == "tcp+tls"
+	switch scheme {
+	case "tls", "tcp+tls":
+		opts.Network = "tcp"
+	default:
+		opts.Network = scheme
+	}
+	switch opts.Network {
+	case "unix", "unixgram":
+		opts.Addr = u.Host + u.Path
+	case "tcp", "udp":
+		opts.Addr = u.Host
+		if u.Port() == "" {
+			return opts, fmt.Errorf("%s output needs host:port", scheme)
+		}
+	default:
+		return opts, fmt.Errorf("unknown network %q", scheme)
+	}
+	if opts.Addr == "" {
+		return opts, fmt.Errorf("%s output without address", scheme)
+	}
+
+	switch f := strings.ToLower(q.Get("framing")); f {
+	case "":
+	case "newline", "lf":
+		opts.Framing = FrameNewline
+	case "octet", "octet-count", "octet-counting":
+		opts.Framing = FrameOctet
+	case "none", "raw":
+		opts.RawStream = true
+	default:
+		return opts, fmt.Errorf("unknown framing %q", f)
+	}
+	if s := q.Get("buffer"); s != "" {
+		n, err := ParseSize(s)
+		if err != nil {
+			return opts, err
+		}
+		opts.BufferSize = int(n)
+		if n == 0 {
+			opts.BufferSize = -1
+		}
+	}
+	for _, d := range []struct {
+		key string
+		dst *time.Duration
+	}{
+		{"backoff_min", &opts.BackoffMin},
+		{"backoff_max", &opts.BackoffMax},
+		{"dial_timeout", &opts.DialTimeout},
+		{"write_timeout", &opts.WriteTimeout},
+	} {
+		if s := q.Get(d.key); s != "" {
+			v, err := time.ParseDuration(s)
+			if err != nil || v < 0 {
+				return opts, fmt.Errorf("invalid %s %q", d.key, s)
+			}
+			*d.dst = v
+		}
+	}
+
+	tlsOn, err := queryBool(q, "tls")
+	if err != nil {
+		return opts, err
+	}
+	if useTLS || tlsOn {
+		if opts.TLS, err = tlsConfig(q, u.Hostname()); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// tlsConfig monta o tls.Config a partir dos parâmetros tls_* da spec.
+func tlsConfig(q url.Values, host string) (*tls.Config, error) {
+	cfg := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	if s := q.Get("tls_server_name"); s != "" {
+		cfg.ServerName = s
+	}
+	insecure, err := queryBool(q, "tls_insecure")
+	if err != nil {
+		return nil, err
+	}
+	cfg.InsecureSkipVerify = insecure
+	if ca := q.Get("tls_ca"); ca != "" {
+		pem, err := os.ReadFile(ca)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %s", ca)
+		}
+	}
+	cert, key := q.Get("tls_cert"), q.Get("tls_key")
+	if (cert == "") != (key == "") {
+		return nil, fmt.Errorf("tls_cert and tls_key go together")
+	}
+	if cert != "" {
+		pair, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{pair}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,273 @@
+package writer_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/logz/internal/writer"
+)
+
+// eventually espera cond virar verdade.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// acceptAll aceita uma conexão e devolve tudo que chegou nela até o EOF.
+func acceptAll(t *testing.T, ln net.Listener) <-chan string {
+	t.Helper()
+	ch := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			ch <- ""
+			return
+		}
+		defer conn.Close()
+		b, _ := io.ReadAll(conn)
+		ch <- string(b)
+	}()
+	return ch
+}
+
+func recv(t *testing.T, ch <-chan string) string {
+	t.Helper()
+	select {
+	case s := <-ch:
+		return s
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the server")
+		return ""
+	}
+}
+
+func TestNetWriterFraming(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts writer.NetOptions
+		want string
+	}{
+		{"newline", writer.NetOptions{}, "a\nb\n"},
+		{"octet", writer.NetOptions{Framing: writer.FrameOctet}, "1 a1 b"},
+		{"raw", writer.NetOptions{RawStream: true}, "ab\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+			got := acceptAll(t, ln)
+
+			tc.opts.Network, tc.opts.Addr = "tcp", ln.Addr().String()
+			w, err := writer.DialNet(tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, rec := range []string{"a", "b\n"} {
+				if n, err := w.Write([]byte(rec)); err != nil || n != len(rec) {
+					t.Fatalf("Write: %d, %v", n, err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if s := recv(t, got); s != tc.want {
+				t.Errorf("got %q, want %q", s, tc.want)
+			}
+		})
+	}
+}
+
+// Em datagrama cada registro é um pacote, sem '\n' acrescentado.
+func TestNetWriterUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	w, err := writer.Open("udp://" + pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	for _, rec := range []string{"one", "two"} {
+		if _, err := w.Write([]byte(rec)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf := make([]byte, 64)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for _, want := range []string{"one", "two"} {
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(buf[:n]); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestNetWriterUnix(t *testing.T) {
+	// caminho curto: sockets unix têm limite de ~100 bytes.
+	dir, err := os.MkdirTemp("", "logz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "s.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skip("unix sockets:", err)
+	}
+	defer ln.Close()
+	got := acceptAll(t, ln)
+
+	w, err := writer.Open("unix://" + path + "?framing=octet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("hello\n"))
+	w.Close()
+	if s := recv(t, got); s != "5 hello" {
+		t.Errorf("got %q", s)
+	}
+}
+
+// Com o destino fora, os registros vão pro buffer (limitado: os mais
+// antigos saem) e são reenviados em ordem ao reconectar.
+func TestNetWriterReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	w, err := writer.DialNet(writer.NetOptions{
+		Network:    "tcp",
+		Addr:       addr,
+		BufferSize: 8, // cabem dois registros de 3 bytes ("r1\n")
+		BackoffMin: 10 * time.Millisecond,
+		BackoffMax: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// derruba o destino e espera o writer perceber.
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.Close()
+	conn.Close()
+	eventually(t, "disconnect", func() bool { return !w.Stats().Connected })
+
+	for _, rec := range []string{"r1", "r2", "r3", "r4"} {
+		if _, err := w.Write([]byte(rec)); err != nil {
+			t.Fatalf("buffered write: %v", err)
+		}
+	}
+	st := w.Stats()
+	if st.Buffered != 2 || st.Dropped != 2 {
+		t.Errorf("stats while down: %+v", st)
+	}
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skip("could not listen again on", addr, err)
+	}
+	defer ln.Close()
+	conn, err = ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	eventually(t, "reconnect", func() bool { return w.Stats().Connected })
+	if _, err := w.Write([]byte("r5")); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var got []string
+	for range 3 {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, strings.TrimSuffix(line, "\n"))
+	}
+	if strings.Join(got, ",") != "r3,r4,r5" {
+		t.Errorf("got %v, want [r3 r4 r5]", got)
+	}
+	if st := w.Stats(); st.Reconnects != 1 || st.Buffered != 0 {
+		t.Errorf("stats after reconnect: %+v", st)
+	}
+}
+
+// Sem buffer, a escrita desconectada falha em vez de guardar.
+func TestNetWriterNoBuffer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := writer.Open("tcp://" + ln.Addr().String() + "?buffer=0&backoff_min=1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.Close()
+	conn.Close()
+	nw := w.(*writer.NetWriter)
+	eventually(t, "disconnect", func() bool { return !nw.Stats().Connected })
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Error("write while disconnected: want error")
+	}
+	if st := nw.Stats(); st.Buffered != 0 {
+		t.Errorf("buffered without buffer: %+v", st)
+	}
+}
+
+func TestNetWriterErrors(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	// a primeira conexão falhar é erro.
+	if _, err := writer.DialNet(writer.NetOptions{Network: "tcp", Addr: addr, DialTimeout: time.Second}); err == nil {
+		t.Error("dial to closed port: want error")
+	}
+	for _, spec := range []string{
+		"tcp://localhost",
+		"tcp://" + addr + "?framing=fancy",
+		"tcp://" + addr + "?buffer=lots",
+		"tcp://" + addr + "?backoff_min=soon",
+		"udp://" + addr + "?tls=true",
+		"tls://" + addr + "?tls_cert=c.pem",
+		"unix://",
+	} {
+		if _, err := writer.Open(spec); err == nil {
+			t.Errorf("%s: want error", spec)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Opener abre o destino descrito por u (a spec já parseada). Erros de
+// abertura (arquivo sem permissão, host inalcançável...) devem ser
+// devolvidos, nunca trocados por io.Discard.
+type Opener func(u *url.URL) (io.Writer, error)
+
+// ErrUnknownScheme é devolvido (embrulhado) por Open quando nenhum Opener
+// foi registrado pro scheme da spec.
+var ErrUnknownScheme = errors.New("logz: unknown output scheme")
+
+var (
+	openersMu sync.RWMutex
+	// openers mapeia os schemes das specs de saída ("file", "tcp",
+	// "syslog+udp"...) pros Openers.
+	openers = map[string]Opener{
+		"stdout":      func(*url.URL) (io.Writer, error) { return os.Stdout, nil },
+		"stderr":      func(*url.URL) (io.Writer, error) { return os.Stderr, nil },
+		"null":        func(*url.URL) (io.Writer, error) { return io.Discard, nil },
+		"file":        openFile,
+		"tcp":         openNet,
+		"tls":         openNet,
+		"tcp+tls":     openNet,
+		"udp":         openNet,
+		"unix":        openNet,
+		"unixgram":    openNet,
+		"syslog":      openSyslog,
+		"syslog+udp":  openSyslog,
+		"syslog+tcp":  openSyslog,
+		"syslog+tls":  openSyslog,
+		"syslog+unix": openSyslog,
+		"http":        openHTTP,
+		"https":       openHTTP,
+	}
+)
+
+// Register registra (ou substitui) o Opener de um scheme. O scheme não
+// diferencia maiúsculas e passa a valer em "output" na config, em
+// LOGZ_LOG_OUTPUT e no --output da CLI.
+func Register(scheme string, open Opener) error {
+	scheme = strings.ToLower(strings.TrimSpace(scheme))
+	if scheme == "" {
+		return fmt.Errorf("logz: output scheme without name")
+	}
+	if open == nil {
+		return fmt.Errorf("logz: output scheme %q has no opener", scheme)
+	}
+	openersMu.Lock()
+	openers[scheme] = open
+	openersMu.Unlock()
+	return nil
+}
+
+// Schemes lista os schemes registrados, em ordem alfabética.
+func Schemes() []string {
+	openersMu.RLock()
+	defer openersMu.RUnlock()
+	names := make([]string, 0, len(openers))
+	for n := range openers {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open abre o destino descrito por spec:
+//
+//   - "stdout", "stderr" (ou vazio, que é stdout);
+//   - um caminho de arquivo sem scheme ("/var/log/app.log", "app.log");
+//   - uma URL cujo scheme foi registrado: file:///var/log/app.log?rotate=true&max_size=100MB,
+//     syslog+udp://host:514, tcp://host:5000, tls://host:6514,
+//     unix:///run/app.sock, unixgram:///run/app.sock,
+//     https://host/ingest, null://...
+//
+// Scheme desconhecido e falha de abertura são erro.
+func Open(spec string) (LogzWriter, error) {
+	spec = strings.TrimSpace(spec)
+	switch spec {
+	case "", "stdout":
+		return NewLogzWriter(os.Stdout), nil
+	case "stderr":
+		return NewLogzWriter(os.Stderr), nil
+	}
+	if !strings.Contains(spec, "://") {
+		w, err := openFile(&url.URL{Scheme: "file", Path: spec})
+		if err != nil {
+			return nil, fmt.Errorf("logz: opening output %q: %w", spec, err)
+		}
+		return asLogzWriter(w), nil
+	}
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("logz: invalid output %q: %w", spec, err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	openersMu.RLock()
+	open, ok := openers[scheme]
+	openersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownScheme, scheme, strings.Join(Schemes(), ", "))
+	}
+	// a query pode ter tokens (logz_bearer...): fica fora das mensagens.
+	shown := *u
+	shown.RawQuery = ""
+	w, err := open(u)
+	if err != nil {
+		return nil, fmt.Errorf("logz: opening output %q: %w", shown.Redacted(), err)
+	}
+	if w == nil {
+		return nil, fmt.Errorf("logz: opening output %q: opener returned nil", shown.Redacted())
+	}
+	return asLogzWriter(w), nil
+}
+
+func asLogzWriter(w io.Writer) LogzWriter {
+	if lw, ok := w.(LogzWriter); ok {
+		return lw
+	}
+	return NewLogzWriter(w)
+}
+
+// failedWriter é o que ParseWriter devolve quando a spec não abre: cada
+// escrita devolve o erro de abertura, em vez de sumir num io.Discard.
+type failedWriter struct {
+	err error
+}
+
+func (f failedWriter) Write([]byte) (int, error) { return 0, f.err }
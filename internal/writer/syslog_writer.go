@@ -0,0 +1,160 @@
+package writer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+var syslogSeverities = map[string]int{
+	"emerg": 0, "alert": 1, "crit": 2, "err": 3, "error": 3,
+	"warning": 4, "warn": 4, "notice": 5, "info": 6, "debug": 7,
+}
+
+// SyslogWriter manda cada registro como uma mensagem syslog (RFC 5424,
+// ou RFC 3164 com format=rfc3164) por udp, tcp, tls ou socket unix. Em
+// stream as mensagens são enquadradas por contagem de octetos (RFC 6587);
+// em datagramas, uma por pacote. A conexão é um NetWriter: quedas são
+// cobertas por buffer e reconexão.
+//
+// O writer não conhece a entry: facility e severity são fixas por destino
+// (?facility=local0&severity=info).
+type SyslogWriter struct {
+	out      *NetWriter
+	priority int
+	tag      string
+	hostname string
+	rfc3164  bool
+	pid      int
+	now      func() time.Time
+}
+
+// openSyslog abre syslog://host[:514] (udp), syslog+udp://, syslog+tcp://,
+// syslog+tls://host[:6514] e syslog+unix:///dev/log. Query: facility,
+// severity, tag, hostname, format (rfc5424 ou rfc3164), mais os
+// parâmetros de rede e tls_* do openNet.
+func openSyslog(u *url.URL) (io.Writer, error) {
+	q := u.Query()
+	w := &SyslogWriter{pid: os.Getpid(), now: time.Now}
+
+	facility, severity := 1, 6
+	if s := q.Get("facility"); s != "" {
+		f, ok := syslogFacilities[strings.ToLower(s)]
+		if !ok {
+			return nil, fmt.Errorf("unknown syslog facility %q", s)
+		}
+		facility = f
+	}
+	if s := q.Get("severity"); s != "" {
+		sv, ok := syslogSeverities[strings.ToLower(s)]
+		if !ok {
+			return nil, fmt.Errorf("unknown syslog severity %q", s)
+		}
+		severity = sv
+	}
+	w.priority = facility*8 + severity
+	w.tag = q.Get("tag")
+	if w.tag == "" {
+		w.tag = filepath.Base(os.Args[0])
+	}
+	w.hostname = q.Get("hostname")
+	if w.hostname == "" {
+		w.hostname, _ = os.Hostname()
+	}
+	switch strings.ToLower(q.Get("format")) {
+	case "", "rfc5424":
+	case "rfc3164", "bsd":
+		w.rfc3164 = true
+	default:
+		return nil, fmt.Errorf("unknown syslog format %q", q.Get("format"))
+	}
+
+	transport := strings.TrimPrefix(strings.TrimPrefix(u.Scheme, "syslog"), "+")
+	switch transport {
+	case "", "udp", "tcp", "tls":
+		port := "514"
+		switch transport {
+		case "":
+			transport = "udp"
+		case "tls":
+			port = "6514" // RFC 5425
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("syslog output without host")
+		}
+		addr := u.Host
+		if u.Port() == "" {
+			addr = net.JoinHostPort(u.Hostname(), port)
+		}
+		opts, err := netOptions(&url.URL{Scheme: transport, Host: addr, RawQuery: u.RawQuery})
+		if err != nil {
+			return nil, err
+		}
+		if transport != "udp" {
+			opts.Framing = FrameOctet
+		}
+		if w.out, err = DialNet(opts); err != nil {
+			return nil, err
+		}
+	case "unix":
+		path := u.Host + u.Path
+		if path == "" {
+			path = "/dev/log"
+		}
+		// /dev/log costuma ser datagrama; alguns daemons só aceitam stream.
+		var err error
+		if w.out, err = DialNet(NetOptions{Network: "unixgram", Addr: path}); err != nil {
+			if w.out, err = DialNet(NetOptions{Network: "unix", Addr: path, Framing: FrameOctet}); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown syslog transport %q", transport)
+	}
+	return w, nil
+}
+
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	msg := bytes.TrimRight(p, "\r\n")
+	var b bytes.Buffer
+	if w.rfc3164 {
+		fmt.Fprintf(&b, "<%d>%s %s %s[%d]: ", w.priority, w.now().Format(time.Stamp), w.hostname, w.tag, w.pid)
+	} else {
+		fmt.Fprintf(&b, "<%d>1 %s %s %s %d - - ", w.priority,
+			w.now().Format("2006-01-02T15:04:05.000000Z07:00"), nilValue(w.hostname), nilValue(w.tag), w.pid)
+	}
+	b.Write(msg)
+	if _, err := w.out.Write(b.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// nilValue troca campos vazios pelo NILVALUE ("-") da RFC 5424.
+func nilValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(s, " ", "_")
+}
+
+func (w *SyslogWriter) Close() error {
+	return w.out.Close()
+}
+
+func (w *SyslogWriter) String() string {
+	return "SyslogWriter(" + w.out.String() + ")"
+}
@@ -2,6 +2,7 @@
 package writer
 
 import (
+	"fmt"
 	"io"
 	"os"
 )
@@ -66,17 +67,15 @@ func (l *LogzWriterImpl) String() string {
 	return "LogzWriter"
 }
 
-// ParseWriter retorna um Writer baseado na string de output.
+// ParseWriter abre o destino descrito por output (ver Open). Quando a spec
+// não abre, o erro vai pro stderr e cada escrita no writer devolvido
+// falha com ele; nada é descartado em silêncio. Use Open pra tratar o
+// erro na hora.
 func ParseWriter(output string) LogzWriter {
-	switch output {
-	case "stdout":
-		return NewLogzWriter(os.Stdout)
-	case "stderr":
-		return NewLogzWriter(os.Stderr)
-	default:
-		if file, err := os.OpenFile(output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
-			return NewLogzWriter(file)
-		}
-		return NewLogzWriter(io.Discard)
+	w, err := Open(output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return NewLogzWriter(failedWriter{err: err})
 	}
+	return w
 }
@@ -2,8 +2,12 @@
 package logz
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net/http"
 	"os"
 	"sync/atomic"
 
@@ -11,9 +15,15 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/kubex-ecosystem/logz/interfaces"
+	"github.com/kubex-ecosystem/logz/internal/binlog"
 	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/dedup"
+	"github.com/kubex-ecosystem/logz/internal/exporter"
 	"github.com/kubex-ecosystem/logz/internal/formatter"
+	"github.com/kubex-ecosystem/logz/internal/middleware"
 	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/internal/redact"
+	"github.com/kubex-ecosystem/logz/internal/sampling"
 	"github.com/kubex-ecosystem/logz/internal/writer"
 )
 
@@ -30,11 +40,23 @@ type LogzBufferingOptions = kbx.LogzBufferingOptions
 type LogzRotatingOptions = kbx.LogzRotatingOptions
 type LogzFormatOptions = kbx.LogzFormatOptions
 type LogzOutputOptions = kbx.LogzOutputOptions
+type LogzRedactOptions = kbx.LogzRedactOptions
+type RedactRule = kbx.RedactRule
+type LogzSamplingOptions = kbx.LogzSamplingOptions
+type SamplingRate = kbx.SamplingRate
+type LogzDedupOptions = kbx.LogzDedupOptions
+type LogzOTLPOptions = kbx.LogzOTLPOptions
 
 type LogzJSONFormatter = formatter.JSONFormatter
 type LogzTextFormatter = formatter.TextFormatter
 type LogzPrettyFormatter = formatter.PrettyFormatter
+type LogzConsoleFormatter = formatter.ConsoleFormatter
+type LogzXMLFormatter = formatter.XMLFormatter
+type LogzDynamicFormatter = formatter.DynamicFormatter
+type LogzBinaryFormatter = formatter.BinaryFormatter
+type LogzLogfmtFormatter = formatter.LogfmtFormatter
 type LogzFormatter = formatter.Formatter
+type LogzFormatterFunc = formatter.FormatterFunc
 
 type LoggerZ = LogzLoggerZ
 type EntryImpl = C.Entry
@@ -45,10 +67,78 @@ type Writer = writer.Writer
 type LogzWriter = writer.LogzWriter
 type LogzIOWriter = writer.IOWriter
 type LogzMultiWriter = writer.MultiWriter
+type DocumentWriter = writer.DocumentWriter
 type LogzEntry = kbx.LogzEntry
 
 type LogzHooks[T any] = interfaces.LHook[T]
 
+// EntryWriter is a structured destination that receives the (redacted)
+// entry instead of formatted bytes. Attach one with logger.AddExporter or
+// use it directly as the logger output.
+type EntryWriter = interfaces.EntryWriter
+
+// OTLPExporter ships entries to an OpenTelemetry Collector over OTLP/HTTP
+// (protobuf or JSON) with batching, retries and optional gzip.
+type OTLPExporter = exporter.OTLPExporter
+
+// Lazy defers an expensive field value until the entry is actually written:
+//
+//	logger.Debug(map[string]any{"dump": logz.Lazy(func() any { return dump() })})
+type Lazy = kbx.Lazy
+
+// LogValuer is implemented by values that produce their own log representation.
+// LogValue is only called for entries that pass the level filter.
+type LogValuer = kbx.LogValuer
+
+type LogzSlogHandler = C.SlogHandler[Entry]
+
+// TraceContext carries W3C trace_id/span_id/trace_flags. Passing a
+// context.Context among the log arguments fills these fields automatically
+// from an OpenTelemetry span (or from ContextWithTrace).
+type TraceContext = kbx.TraceContext
+
+// TraceparentHeader is the W3C Trace Context header (and gRPC metadata key).
+const TraceparentHeader = kbx.TraceparentHeader
+
+// Redactor masks secrets and PII in Message, Fields, Tags and Error text
+// before an entry is formatted.
+type Redactor = redact.Redactor
+
+// Sampler drops repetitive entries (first-N-then-every-Mth, token bucket,
+// probabilistic) and periodically reports how many were suppressed.
+type Sampler = sampling.Sampler
+type SamplingSummary = sampling.Summary
+
+// Collapser folds consecutive identical entries into a single
+// "repeated N times" line carrying the first/last timestamps.
+type Collapser = dedup.Collapser
+
+// Clock supplies the time used to stamp entries (see Logger.SetClock).
+type Clock = kbx.Clock
+type ClockFunc = kbx.ClockFunc
+
+// TimeFormat is a timestamp layout plus time zone (see ParseTimeFormat).
+type TimeFormat = kbx.TimeFormat
+
+// TermInfo tells the text and pretty formatters whether the output is a
+// terminal and how many colors it supports (see DetectTerm).
+type TermInfo = kbx.TermInfo
+type ColorMode = kbx.ColorMode
+
+const (
+	ColorNone      = kbx.ColorNone
+	ColorBasic     = kbx.ColorBasic
+	Color256       = kbx.Color256
+	ColorTrueColor = kbx.ColorTrueColor
+)
+
+// Theme sets the color, icon and label of each level in the text and
+// pretty formatters. LevelColor holds one color in 16, 256 and 24-bit
+// variants.
+type Theme = formatter.Theme
+type LevelStyle = formatter.LevelStyle
+type LevelColor = formatter.LevelColor
+
 func NewLogzOptions(withDefaults bool) *LogzOptions {
 	if withDefaults {
 		return defaultLoggerOptions()
@@ -80,14 +170,113 @@ func NewLogzOptions(withDefaults bool) *LogzOptions {
 	return opts
 }
 
+// Levels understood by the loggers, formatters and ParseLevel.
+const (
+	LevelNotice   = kbx.LevelNotice
+	LevelDebug    = kbx.LevelDebug
+	LevelTrace    = kbx.LevelTrace
+	LevelSuccess  = kbx.LevelSuccess
+	LevelInfo     = kbx.LevelInfo
+	LevelWarn     = kbx.LevelWarn
+	LevelError    = kbx.LevelError
+	LevelFatal    = kbx.LevelFatal
+	LevelSilent   = kbx.LevelSilent
+	LevelAlert    = kbx.LevelAlert
+	LevelCritical = kbx.LevelCritical
+	LevelAnswer   = kbx.LevelAnswer
+	LevelBug      = kbx.LevelBug
+	LevelPanic    = kbx.LevelPanic
+)
+
 func ParseLevel(level string) Level {
 	return kbx.ParseLevel(level)
 }
 
+// ParseWriter opens output like OpenWriter. If it cannot be opened the
+// error is printed to stderr and every write returns it; use OpenWriter to
+// handle the error directly.
 func ParseWriter(output string) io.Writer {
 	return writer.ParseWriter(output)
 }
 
+// WriterOpener opens the output described by a parsed spec URL.
+type WriterOpener = writer.Opener
+
+// ErrUnknownOutput is wrapped by the errors returned for output specs
+// whose scheme has no registered opener.
+var ErrUnknownOutput = writer.ErrUnknownScheme
+
+// OpenWriter opens an output spec: "stdout", "stderr", a file path, or a
+// URL such as file:///var/log/app.log?rotate=true&max_size=100MB&max_backups=7,
+// syslog+udp://host:514?facility=local0, tcp://host:5000,
+// unix:///run/app.sock, https://host/ingest or null://. Unknown schemes
+// and open failures are errors.
+func OpenWriter(spec string) (LogzWriter, error) {
+	return writer.Open(spec)
+}
+
+// RegisterWriter makes a custom output scheme available to OpenWriter,
+// the "output" config key, LOGZ_LOG_OUTPUT and the CLI --output flag.
+// Registering an existing scheme replaces it.
+func RegisterWriter(scheme string, open WriterOpener) error {
+	return writer.Register(scheme, open)
+}
+
+// WriterSchemes lists the registered output schemes, sorted.
+func WriterSchemes() []string {
+	return writer.Schemes()
+}
+
+// NetWriter sends each record over tcp (optionally TLS), udp or a unix
+// stream/datagram socket. While disconnected, records are kept in a
+// bounded in-memory buffer (oldest dropped first) and a background
+// goroutine reconnects with exponential backoff, replaying the buffer in
+// order.
+type NetWriter = writer.NetWriter
+type NetOptions = writer.NetOptions
+type NetStats = writer.NetStats
+type NetFraming = writer.Framing
+
+const (
+	FrameNone    = writer.FrameNone
+	FrameNewline = writer.FrameNewline
+	FrameOctet   = writer.FrameOctet
+)
+
+// DialNetWriter connects a NetWriter; only the first connection failing
+// is an error. Stream sockets default to newline framing.
+func DialNetWriter(opts NetOptions) (*NetWriter, error) {
+	return writer.DialNet(opts)
+}
+
+// HTTPWriter batches records (by count, bytes and time) and POSTs them as
+// NDJSON or a JSON array to a generic collector, with optional gzip,
+// custom headers and bearer token. Batches are retried with exponential
+// backoff on 5xx, 429 and network errors, honoring Retry-After.
+type HTTPWriter = writer.HTTPWriter
+type HTTPOptions = writer.HTTPOptions
+
+const (
+	HTTPEncodingNDJSON = writer.HTTPEncodingNDJSON
+	HTTPEncodingJSON   = writer.HTTPEncodingJSON
+)
+
+// NewHTTPWriter starts an HTTPWriter. Close (or Flush) it before exiting
+// so the last batch is sent.
+func NewHTTPWriter(opts HTTPOptions) (*HTTPWriter, error) {
+	return writer.NewHTTPWriter(opts)
+}
+
+// RotatingFile is a log file renamed with a timestamp and reopened once it
+// reaches MaxSize; old files can be gzipped and pruned by count or age.
+type RotatingFile = writer.RotatingFile
+type RotateOptions = writer.RotateOptions
+
+// OpenRotatingFile opens (or creates) path in append mode with rotation.
+func OpenRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	return writer.OpenRotatingFile(path, opts)
+}
+
 // defaultLoggerOptions initializes and returns a pointer to a LogzOptions struct
 // with default configuration values for logging.
 func defaultLoggerOptions() *LogzOptions {
@@ -102,6 +291,7 @@ func defaultLoggerOptions() *LogzOptions {
 				Level:    ParseLevel(kbx.DefaultLogLevel),
 				MinLevel: ParseLevel(kbx.DefaultLogMinLevel),
 				MaxLevel: ParseLevel(kbx.DefaultLogMaxLevel),
+				Format:   kbx.GetEnvOrDefault("LOGZ_LOG_FORMAT", kbx.DefaultLogFormat),
 			},
 			LogzOutputOptions:    &LogzOutputOptions{},
 			LogzRotatingOptions:  &LogzRotatingOptions{},
@@ -109,7 +299,11 @@ func defaultLoggerOptions() *LogzOptions {
 		},
 		LogzAdvancedOptions: &LogzAdvancedOptions{},
 	}
-	opts.Formatter = formatter.ParseFormatter(kbx.DefaultLogFormat, kbx.DefaultShowColor)
+	// An unknown LOGZ_LOG_FORMAT leaves Formatter unset: the logger reports
+	// the name and every Log returns the error instead of writing text.
+	if f, err := formatter.New(opts.Format, kbx.DefaultShowColor); err == nil {
+		opts.Formatter = f
+	}
 	return opts
 }
 
@@ -563,15 +757,274 @@ func init() {
 	}
 }
 
+// Close flushes the global loggers and closes their exporters, so the
+// last OTLP batch is sent. Call it before the program exits; the loggers
+// keep writing to their outputs afterwards.
+func Close() error {
+	var errs []error
+	if Logger != nil {
+		errs = append(errs, Logger.Close())
+	}
+	if LoggerLogz != nil && LoggerLogz.Logger != nil {
+		errs = append(errs, LoggerLogz.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// NewLogzFormatter returns the formatter registered under format, or the
+// text formatter when the name is unknown. Use NewFormatter to get an
+// error instead.
 func NewLogzFormatter(args *LogzFormatOptions, format string) LogzFormatter {
-	switch format {
-	case "json":
-		return formatter.NewJSONFormatter(true)
-	case "pretty":
-		return formatter.NewPrettyFormatter(true)
-	default:
-		return formatter.NewTextFormatter(true)
+	return formatter.ParseFormatter(format, true)
+}
+
+// FormatterFactory builds a formatter; pretty enables colors/indentation
+// in the formatters that support it.
+type FormatterFactory = formatter.Factory
+
+// ErrUnknownFormat is wrapped by the errors returned for format names no
+// formatter was registered under.
+var ErrUnknownFormat = formatter.ErrUnknownFormat
+
+// RegisterFormatter makes a custom formatter available by name (case
+// insensitive) to the "format" config key, LOGZ_LOG_FORMAT, the CLI
+// --format flag and the dynamic formatter rules. Registering an existing
+// name replaces it.
+func RegisterFormatter(name string, factory FormatterFactory) error {
+	return formatter.Register(name, factory)
+}
+
+// NewFormatter builds the formatter registered under name; an empty name
+// is the default format. Unknown names return an error wrapping
+// ErrUnknownFormat.
+func NewFormatter(name string, pretty bool) (LogzFormatter, error) {
+	return formatter.New(name, pretty)
+}
+
+// FormatterNames lists the registered format names, sorted.
+func FormatterNames() []string {
+	return formatter.Names()
+}
+
+// BinaryRecord is one entry in the binary (msgpack/cbor/protobuf) log
+// schema; the protobuf form is published in proto/logz/v1/entry.proto.
+type BinaryRecord = binlog.Record
+
+// BinaryReader reads length-prefixed records written by the msgpack, cbor
+// and protobuf formatters; Next returns io.EOF at the end of the stream.
+type BinaryReader = binlog.Reader
+
+// NewBinaryReader reads binary log records from r. The codec of each
+// record is detected from its first byte, so mixed streams are fine.
+func NewBinaryReader(r io.Reader) *BinaryReader {
+	return binlog.NewReader(r)
+}
+
+// EntryFromRecord rebuilds an Entry from a binary record, ready to be
+// formatted or logged again.
+func EntryFromRecord(r *BinaryRecord) *EntryImpl {
+	return C.EntryFromRecord(r)
+}
+
+// ParseTimeFormat builds a TimeFormat from a layout name ("rfc3339nano",
+// "unixms", "datetime"...) or Go layout, and a zone ("UTC", "Local" or an
+// IANA name). Apply it to a formatter with SetTimeFormat, or set
+// time_format/time_zone in the config (LOGZ_TIME_FORMAT/LOGZ_TIME_ZONE).
+func ParseTimeFormat(layout, zone string) (TimeFormat, error) {
+	return kbx.ParseTimeFormat(layout, zone)
+}
+
+// DetectTerm reports whether w is a terminal and which ColorMode it
+// supports, honoring NO_COLOR, FORCE_COLOR, COLORTERM and TERM. Loggers do
+// this on their own output; tty, when not nil, overrides the terminal check
+// like the output_tty option.
+func DetectTerm(w io.Writer, tty *bool) TermInfo {
+	return kbx.DetectTerm(w, tty)
+}
+
+// RegisterTheme adds (or replaces) a named theme, selectable with the
+// "theme" config option or LOGZ_THEME. Built-in themes: default, ascii
+// (no emoji), high-contrast and monochrome.
+func RegisterTheme(t Theme) error {
+	return formatter.RegisterTheme(t)
+}
+
+// LookupTheme returns a copy of a registered theme, to be tweaked and
+// registered under a new name or set with SetTheme on a formatter.
+func LookupTheme(name string) (Theme, bool) {
+	return formatter.LookupTheme(name)
+}
+
+// ThemeNames lists the registered themes.
+func ThemeNames() []string {
+	return formatter.ThemeNames()
+}
+
+// ParseTraceparent parses a W3C traceparent header value.
+func ParseTraceparent(s string) (TraceContext, error) {
+	return kbx.ParseTraceparent(s)
+}
+
+// NewTraceContext generates a fresh, sampled trace (use tc.Child() for a
+// new span in the same trace).
+func NewTraceContext() TraceContext {
+	return kbx.NewTraceContext()
+}
+
+// ContextWithTrace stores tc in ctx so loggers can correlate entries
+// without the OpenTelemetry SDK.
+func ContextWithTrace(ctx context.Context, tc TraceContext) context.Context {
+	return kbx.ContextWithTrace(ctx, tc)
+}
+
+// TraceFromContext returns the current span from ctx: an OpenTelemetry span
+// takes precedence over one stored with ContextWithTrace.
+func TraceFromContext(ctx context.Context) (TraceContext, bool) {
+	return kbx.TraceFromContext(ctx)
+}
+
+// NewRedactor compiles the redaction rules in opts (nil means defaults only).
+func NewRedactor(opts *LogzRedactOptions) (*Redactor, error) {
+	return redact.New(opts)
+}
+
+// NewSampler builds a Sampler from opts; attach it with logger.SetSampler.
+func NewSampler(opts *LogzSamplingOptions) (*Sampler, error) {
+	return sampling.New(opts)
+}
+
+// NewCollapser builds a duplicate-collapsing stage from opts; attach it
+// with logger.SetDedup.
+func NewCollapser(opts *LogzDedupOptions) (*Collapser, error) {
+	return dedup.New(opts)
+}
+
+// NewOTLPExporter starts an OTLP/HTTP logs exporter. Call Close (or Flush)
+// before exiting so buffered records are sent.
+func NewOTLPExporter(opts *LogzOTLPOptions) (*OTLPExporter, error) {
+	return exporter.NewOTLP(opts)
+}
+
+// LoadConfigFile reads a JSON or YAML logz config file. An empty path
+// falls back to the default location ($HOME/.kubex/logz/config.json).
+// An unknown "format" is an error.
+func LoadConfigFile(path string) (*LogzConfig, error) {
+	cfg, err := kbx.LoadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Format != "" {
+		if _, err := formatter.New(cfg.Format, false); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// NewLoggerFromConfig creates a LoggerZ from a config loaded with LoadConfigFile.
+func NewLoggerFromConfig(prefix string, cfg *LogzConfig) *LogzLoggerZ {
+	opts := C.NewLoggerOptions(cfg)
+	if f, err := formatter.New(opts.Format, kbx.DefaultTrue(opts.ShowColor)); err == nil {
+		opts.Formatter = f
 	}
+	return C.NewLoggerZ[Entry](prefix, opts, false)
+}
+
+// NewSlogHandler creates a slog.Handler that forwards records to the given LoggerZ.
+// If logger is nil, the global LoggerZ is used.
+func NewSlogHandler(logger *LogzLoggerZ) *LogzSlogHandler {
+	if logger == nil {
+		logger = GetLoggerZ("")
+	}
+	return C.NewSlogHandler(logger)
+}
+
+// NewSlogLogger creates a *slog.Logger backed by the given LoggerZ.
+func NewSlogLogger(logger *LogzLoggerZ) *slog.Logger {
+	return slog.New(NewSlogHandler(logger))
+}
+
+// LogzIOBridge is an io.Writer that turns every line written to it into a
+// log entry.
+type LogzIOBridge = C.IOBridge[Entry]
+
+// NewIOBridge returns an io.Writer that logs one entry per line, for
+// log.SetOutput or exec.Cmd.Stdout/Stderr. Partial writes are buffered
+// until the newline (call Flush or Close to emit a trailing partial line).
+//
+// The level is detected from common prefixes ("ERROR:", "[WARN]",
+// "level=debug"); lines without one use defaultLevel (info if empty; warn
+// is a good choice for stderr). JSON lines are parsed into message, level
+// and fields (a JSON line without a message key is logged as is). Fatal,
+// critical and panic lines are logged as error, with the original level in
+// the "source_level" field, so nothing written to the bridge can end the
+// process. The stdlib log date/time prefix is dropped. If logger is nil,
+// the global LoggerZ is used.
+func NewIOBridge(logger *LogzLoggerZ, defaultLevel Level) *LogzIOBridge {
+	if logger == nil {
+		logger = GetLoggerZ("")
+	}
+	decode := C.DefaultEntryDecoder(defaultLevel)
+	return C.NewIOBridge(logger, func(p []byte) (Entry, error) {
+		e, err := decode(p)
+		if err != nil || e == nil {
+			return nil, err
+		}
+		return e, nil
+	})
+}
+
+// HTTPMiddlewareOptions tunes the access-log middleware.
+type HTTPMiddlewareOptions = middleware.HTTPOptions
+
+// HTTPMiddleware returns net/http middleware that logs every request
+// (method, path, status, bytes, duration, remote addr, user agent, request
+// ID), extracts or generates the trace from the headers, and injects a
+// child logger into the request context (see FromContext). 5xx responses
+// log as error, 4xx as warn. If logger is nil, the global LoggerZ is used.
+func HTTPMiddleware(logger *LogzLoggerZ, opts *HTTPMiddlewareOptions) func(http.Handler) http.Handler {
+	if logger == nil {
+		logger = GetLoggerZ("")
+	}
+	return middleware.HTTP(logger, opts)
+}
+
+// HTTPTransportOptions tunes the logging http.RoundTripper.
+type HTTPTransportOptions = middleware.TransportOptions
+
+// HTTPTransport is an http.RoundTripper that logs outbound requests.
+type HTTPTransport = middleware.Transport
+
+// NewHTTPTransport wraps base (nil means http.DefaultTransport) with a
+// RoundTripper that logs method, URL, status, duration, retries and errors,
+// and propagates the traceparent from the request context. A logger stored
+// in the request context takes precedence over logger; if both are nil,
+// the global LoggerZ is used.
+func NewHTTPTransport(base http.RoundTripper, logger *LogzLoggerZ, opts *HTTPTransportOptions) *HTTPTransport {
+	if logger == nil {
+		logger = GetLoggerZ("")
+	}
+	return middleware.NewTransport(base, logger, opts)
+}
+
+// ContextWithLogger stores logger in ctx.
+func ContextWithLogger(ctx context.Context, logger *LogzLoggerZ) context.Context {
+	return C.ContextWithLogger(ctx, logger)
+}
+
+// FromContext returns the logger stored in ctx (e.g. the per-request child
+// logger injected by HTTPMiddleware), falling back to the global LoggerZ.
+func FromContext(ctx context.Context) *LogzLoggerZ {
+	if l, ok := LoggerFromContext(ctx); ok {
+		return l
+	}
+	return GetLoggerZ("")
+}
+
+// LoggerFromContext returns the logger stored in ctx, if any, without
+// falling back to the global LoggerZ.
+func LoggerFromContext(ctx context.Context) (*LogzLoggerZ, bool) {
+	return C.LoggerFromContext[Entry](ctx)
 }
 
 func NewLogzWriter(output string, w io.Writer) LogzWriter {
@@ -581,6 +1034,36 @@ func NewLogzWriter(output string, w io.Writer) LogzWriter {
 	return writer.NewLogzWriter(w)
 }
 
+// NewDynamicFormatter returns a formatter that picks another formatter per
+// entry: predicates added with When first, then the highest level
+// threshold (WhenLevel) the entry reaches, then Default. Enrich and Filter
+// run before; a filtered entry is not written. The defaults are error and
+// above as JSON, info to warn as text and debug/trace as minimal; the
+// "dynamic" config key (or LOGZ_DYNAMIC_FORMAT) adds thresholds on top of
+// these and of the WhenLevel ones, replacing those at the same level.
+func NewDynamicFormatter() *LogzDynamicFormatter {
+	return formatter.NewDynamicFormatter(true).(*LogzDynamicFormatter)
+}
+
+// ErrEntryDropped is returned by formatters (DynamicFormatter filters)
+// that decided not to write an entry.
+var ErrEntryDropped = formatter.ErrDropped
+
+// NewDocumentWriter wraps w so that the output of a document formatter
+// (e.g. LogzXMLFormatter with Document set) is a valid document: the
+// header (XML prolog and root element) is written before the first entry
+// and the footer on Close, Finish or Rotate. Over a RotatingFile (e.g.
+// "file://app.xml?rotate=true") each rotated file is closed and the next
+// one opened as a document of its own. Formatters without a document form
+// get a plain pass-through.
+func NewDocumentWriter(w io.Writer, f LogzFormatter) *DocumentWriter {
+	var header, footer []byte
+	if df, ok := f.(formatter.DocumentFormatter); ok {
+		header, footer = df.Header(), df.Footer()
+	}
+	return writer.NewDocumentWriter(w, header, footer)
+}
+
 func NewLogzMultiWriter(outputs ...writer.Writer) LogzWriter {
 	return writer.NewMultiWriter(outputs...)
 }
@@ -0,0 +1,85 @@
+// Package logzadapter holds what the bridges from other loggers (zap,
+// logrus, zerolog) share: building the Entry and handing it to a LoggerZ.
+// Use it to write a bridge for a logger that logz does not ship one for.
+package logzadapter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kubex-ecosystem/logz"
+	C "github.com/kubex-ecosystem/logz/internal/core"
+)
+
+// Record is one log line already translated from the source logger.
+type Record struct {
+	// Source names the library ("zap", "logrus"...).
+	Source string
+	// Level is the equivalent logz level; Original is the name in the
+	// source logger (logged as "<source>.level" when the level is lowered).
+	Level    logz.Level
+	Original string
+	Message  string
+	Time     time.Time
+	// Name becomes the entry's Context (the source logger's name).
+	Name   string
+	Caller string
+	Fields map[string]any
+	Error  error
+}
+
+// Effective returns the level logz actually logs at. Fatal, panic and
+// critical become error: the source library exits (or panics) itself after
+// writing. If logz called os.Exit first, a recoverable panic would end the
+// process.
+func Effective(lvl logz.Level) logz.Level {
+	switch lvl {
+	case logz.LevelFatal, logz.LevelPanic, logz.LevelCritical:
+		return logz.LevelError
+	}
+	return lvl
+}
+
+// Log builds the Entry for r and delivers it to logger.
+func Log(logger *logz.LoggerZ, r Record) error {
+	if logger == nil {
+		return nil
+	}
+	lvl := Effective(r.Level)
+	if !logger.Enabled(lvl) {
+		return nil
+	}
+	fields := r.Fields
+	if lvl != r.Level {
+		if fields == nil {
+			fields = make(map[string]any, 1)
+		}
+		fields[r.Source+".level"] = strings.ToLower(r.Original)
+	}
+
+	entry, err := C.NewEntry(lvl)
+	if err != nil {
+		return err
+	}
+	if !r.Time.IsZero() {
+		entry.WithTimestamp(r.Time)
+	}
+	msg := r.Message
+	if strings.TrimSpace(msg) == "" {
+		msg = "<empty>"
+	}
+	entry.WithMessage(msg)
+	if r.Name != "" {
+		entry.WithContext(r.Name)
+	}
+	if r.Caller != "" {
+		entry.Caller = r.Caller
+	}
+	if r.Error != nil {
+		entry.WithError(r.Error)
+	}
+	if len(fields) > 0 {
+		entry.WithFields(fields)
+	}
+	return logger.Log(lvl, entry)
+}
@@ -0,0 +1,373 @@
+// Package logzgrpc provides gRPC server and client interceptors that log
+// every call through a logz LoggerZ.
+//
+// It is a separate module (go get github.com/kubex-ecosystem/logz/logzgrpc)
+// so that programs which do not use gRPC do not pull google.golang.org/grpc
+// in through the logz module.
+package logzgrpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubex-ecosystem/logz"
+)
+
+// Options tunes the interceptors.
+type Options struct {
+	// LogPayloads logs every request and response message at trace level.
+	LogPayloads bool
+	// Level maps the call's status code to a log level. Default: CodeLevel.
+	Level func(codes.Code) logz.Level
+	// SkipMethods are full method names ("/pkg.Service/Method") that are
+	// not logged, e.g. health checks. The child logger is still injected.
+	SkipMethods []string
+}
+
+type config struct {
+	payloads bool
+	level    func(codes.Code) logz.Level
+	skip     map[string]bool
+}
+
+func newConfig(opts *Options) config {
+	cfg := config{level: CodeLevel, skip: map[string]bool{}}
+	if opts == nil {
+		return cfg
+	}
+	cfg.payloads = opts.LogPayloads
+	if opts.Level != nil {
+		cfg.level = opts.Level
+	}
+	for _, m := range opts.SkipMethods {
+		cfg.skip[m] = true
+	}
+	return cfg
+}
+
+// CodeLevel is the default mapping: OK is info, errors caused by the
+// caller are warn, and server-side failures are error.
+func CodeLevel(code codes.Code) logz.Level {
+	switch code {
+	case codes.OK:
+		return logz.LevelInfo
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return logz.LevelWarn
+	default:
+		return logz.LevelError
+	}
+}
+
+// UnaryServerInterceptor logs each unary call and injects a child logger
+// (grpc.service, grpc.method, peer.address and the trace) into the handler's context,
+// retrievable with logz.FromContext. If logger is nil, the global LoggerZ is
+// used.
+func UnaryServerInterceptor(logger *logz.LoggerZ, opts *Options) grpc.UnaryServerInterceptor {
+	cfg := newConfig(opts)
+	if logger == nil {
+		logger = logz.GetLoggerZ("")
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, callLogger := serverContext(ctx, logger, info.FullMethod)
+		if cfg.payloads {
+			logPayload(callLogger, "grpc request", req)
+		}
+		resp, err := handler(ctx, req)
+		if cfg.payloads && err == nil {
+			logPayload(callLogger, "grpc response", resp)
+		}
+		if !cfg.skip[info.FullMethod] {
+			logCall(callLogger, cfg, "grpc server", info.FullMethod, start, err, nil)
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor. With LogPayloads, every message sent and received
+// on the stream is logged.
+func StreamServerInterceptor(logger *logz.LoggerZ, opts *Options) grpc.StreamServerInterceptor {
+	cfg := newConfig(opts)
+	if logger == nil {
+		logger = logz.GetLoggerZ("")
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, callLogger := serverContext(ss.Context(), logger, info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx, logger: callLogger, payloads: cfg.payloads})
+		if !cfg.skip[info.FullMethod] {
+			logCall(callLogger, cfg, "grpc server", info.FullMethod, start, err, map[string]any{
+				"grpc.stream": streamKind(info.IsClientStream, info.IsServerStream),
+			})
+		}
+		return err
+	}
+}
+
+// UnaryClientInterceptor logs each outgoing unary call and propagates the
+// trace from ctx to the server in the traceparent metadata.
+func UnaryClientInterceptor(logger *logz.LoggerZ, opts *Options) grpc.UnaryClientInterceptor {
+	cfg := newConfig(opts)
+	if logger == nil {
+		logger = logz.GetLoggerZ("")
+	}
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		start := time.Now()
+		ctx, callLogger := clientContext(ctx, logger)
+		if cfg.payloads {
+			logPayload(callLogger, "grpc request", req)
+		}
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		if cfg.payloads && err == nil {
+			logPayload(callLogger, "grpc response", reply)
+		}
+		if !cfg.skip[method] {
+			logCall(callLogger, cfg, "grpc client", method, start, err, map[string]any{
+				"grpc.target": cc.Target(),
+			})
+		}
+		return err
+	}
+}
+
+// StreamClientInterceptor logs each outgoing stream when it ends (the
+// server closes it, or a send or receive fails).
+func StreamClientInterceptor(logger *logz.LoggerZ, opts *Options) grpc.StreamClientInterceptor {
+	cfg := newConfig(opts)
+	if logger == nil {
+		logger = logz.GetLoggerZ("")
+	}
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		ctx, callLogger := clientContext(ctx, logger)
+		fields := map[string]any{
+			"grpc.target": cc.Target(),
+			"grpc.stream": streamKind(desc.ClientStreams, desc.ServerStreams),
+		}
+		done := func(err error) {
+			if !cfg.skip[method] {
+				logCall(callLogger, cfg, "grpc client", method, start, err, fields)
+			}
+		}
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			done(err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, logger: callLogger, payloads: cfg.payloads, single: !desc.ServerStreams, done: done}, nil
+	}
+}
+
+// serverContext extracts the trace from the incoming metadata (or an OTel
+// span already in ctx), generating one if absent, and returns ctx carrying
+// the trace and the per-call child logger.
+func serverContext(ctx context.Context, logger *logz.LoggerZ, method string) (context.Context, *logz.LoggerZ) {
+	tc := TraceFromIncoming(ctx)
+	ctx = logz.ContextWithTrace(ctx, tc)
+	service, name := splitMethod(method)
+	fields := map[string]any{"grpc.service": service, "grpc.method": name}
+	if addr := peerAddress(ctx); addr != "" {
+		fields["peer.address"] = addr
+	}
+	callLogger := logger.With(fields).WithTrace(tc)
+	return logz.ContextWithLogger(ctx, callLogger), callLogger
+}
+
+// clientContext picks the logger (one in ctx wins), injects a child span
+// of the current trace into the outgoing metadata and returns the logger
+// stamped with that span.
+func clientContext(ctx context.Context, logger *logz.LoggerZ) (context.Context, *logz.LoggerZ) {
+	if l, ok := logz.LoggerFromContext(ctx); ok {
+		logger = l
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if vals := md.Get(logz.TraceparentHeader); len(vals) > 0 {
+		if tc, err := logz.ParseTraceparent(vals[0]); err == nil {
+			return ctx, logger.WithTrace(tc)
+		}
+	}
+	tc, ok := logz.TraceFromContext(ctx)
+	if !ok {
+		return ctx, logger
+	}
+	tc = tc.Child()
+	ctx = metadata.AppendToOutgoingContext(ctx, logz.TraceparentHeader, tc.Traceparent())
+	return ctx, logger.WithTrace(tc)
+}
+
+// TraceFromIncoming returns the trace of a server call: an OTel span in
+// ctx, the traceparent metadata (as the parent of a new span), or a freshly
+// generated trace.
+func TraceFromIncoming(ctx context.Context) logz.TraceContext {
+	if tc, ok := logz.TraceFromContext(ctx); ok {
+		return tc
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(logz.TraceparentHeader); len(vals) > 0 {
+			if tc, err := logz.ParseTraceparent(vals[0]); err == nil {
+				return tc.Child()
+			}
+		}
+	}
+	return logz.NewTraceContext()
+}
+
+func logCall(logger *logz.LoggerZ, cfg config, kind, method string, start time.Time, err error, extra map[string]any) {
+	if logger == nil {
+		return
+	}
+	code := status.Code(err)
+	lvl := cfg.level(code)
+	service, name := splitMethod(method)
+	fields := map[string]any{
+		"grpc.service": service,
+		"grpc.method":  name,
+		"grpc.code":    code.String(),
+		"duration_ms":  float64(time.Since(start).Microseconds()) / 1000,
+	}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	entry := logz.NewLogzEntry(lvl).
+		WithMessage(fmt.Sprintf("%s %s %s", kind, method, code)).
+		WithFields(fields)
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	_ = logger.Log(lvl, entry)
+}
+
+func logPayload(logger *logz.LoggerZ, msg string, payload any) {
+	if logger == nil || !logger.Enabled(logz.LevelTrace) {
+		return
+	}
+	_ = logger.Log(logz.LevelTrace, logz.NewLogzEntry(logz.LevelTrace).
+		WithMessage(msg).
+		WithFields(map[string]any{
+			"grpc.payload_type": fmt.Sprintf("%T", payload),
+			"grpc.payload":      payload,
+		}))
+}
+
+// splitMethod turns "/pkg.Service/Method" into ("pkg.Service", "Method").
+func splitMethod(full string) (string, string) {
+	full = strings.TrimPrefix(full, "/")
+	if i := strings.LastIndex(full, "/"); i >= 0 {
+		return full[:i], full[i+1:]
+	}
+	return "unknown", full
+}
+
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
+
+func streamKind(client, server bool) string {
+	switch {
+	case client && server:
+		return "bidi"
+	case client:
+		return "client"
+	case server:
+		return "server"
+	}
+	return "unary"
+}
+
+// serverStream replaces the stream context so handlers see the child
+// logger and the trace.
+type serverStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	logger   *logz.LoggerZ
+	payloads bool
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if s.payloads && err == nil {
+		logPayload(s.logger, "grpc stream send", m)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if s.payloads && err == nil {
+		logPayload(s.logger, "grpc stream recv", m)
+	}
+	return err
+}
+
+// clientStream logs the call once, when the stream finishes.
+type clientStream struct {
+	grpc.ClientStream
+	logger   *logz.LoggerZ
+	payloads bool
+	// single: the server answers with one message (client streaming), so
+	// the first successful RecvMsg already ends the call.
+	single bool
+	done   func(error)
+	// SendMsg and RecvMsg may run on different goroutines; only the first
+	// one to see the end logs the call.
+	finished sync.Once
+}
+
+func (s *clientStream) finish(err error) {
+	s.finished.Do(func() {
+		if err == io.EOF {
+			err = nil
+		}
+		s.done(err)
+	})
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err != nil {
+		// the real status comes from RecvMsg; io.EOF here just means the
+		// server already ended the stream.
+		if err != io.EOF {
+			s.finish(err)
+		}
+		return err
+	}
+	if s.payloads {
+		logPayload(s.logger, "grpc stream send", m)
+	}
+	return nil
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.finish(err)
+		return err
+	}
+	if s.payloads {
+		logPayload(s.logger, "grpc stream recv", m)
+	}
+	if s.single {
+		s.finish(nil)
+	}
+	return nil
+}
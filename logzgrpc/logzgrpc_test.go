@@ -0,0 +1,201 @@
+package logzgrpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/kubex-ecosystem/logz"
+	"github.com/kubex-ecosystem/logz/logzgrpc"
+	"github.com/kubex-ecosystem/logz/logztest"
+)
+
+// echo is a service without generated code: Unary returns the string it
+// gets (or NotFound for "missing"); Bidi echoes every message until the
+// client closes; Hold reads nothing until the call is canceled.
+var echo = grpc.ServiceDesc{
+	ServiceName: "test.Echo",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Unary",
+		Handler: func(_ any, ctx context.Context, dec func(any) error, icpt grpc.UnaryServerInterceptor) (any, error) {
+			in := new(wrapperspb.StringValue)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			h := func(ctx context.Context, req any) (any, error) {
+				if req.(*wrapperspb.StringValue).Value == "missing" {
+					return nil, status.Error(codes.NotFound, "no such thing")
+				}
+				return req, nil
+			}
+			if icpt == nil {
+				return h(ctx, in)
+			}
+			return icpt(ctx, in, &grpc.UnaryServerInfo{FullMethod: "/test.Echo/Unary"}, h)
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Bidi",
+		ServerStreams: true,
+		ClientStreams: true,
+		Handler: func(_ any, stream grpc.ServerStream) error {
+			for {
+				m := new(wrapperspb.StringValue)
+				if err := stream.RecvMsg(m); err != nil {
+					if err == io.EOF {
+						return nil
+					}
+					return err
+				}
+				if err := stream.SendMsg(m); err != nil {
+					return err
+				}
+			}
+		},
+	}, {
+		StreamName:    "Hold",
+		ServerStreams: true,
+		ClientStreams: true,
+		Handler: func(_ any, stream grpc.ServerStream) error {
+			<-stream.Context().Done()
+			return nil
+		},
+	}},
+}
+
+func dial(t *testing.T) (*grpc.ClientConn, *logztest.Observer, *logztest.Observer) {
+	t.Helper()
+	serverLog, serverObs := logztest.New(nil)
+	clientLog, clientObs := logztest.New(nil)
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(logzgrpc.UnaryServerInterceptor(serverLog, nil)),
+		grpc.StreamInterceptor(logzgrpc.StreamServerInterceptor(serverLog, nil)),
+	)
+	srv.RegisterService(&echo, struct{}{})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	cc, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(logzgrpc.UnaryClientInterceptor(clientLog, nil)),
+		grpc.WithStreamInterceptor(logzgrpc.StreamClientInterceptor(clientLog, nil)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return cc, serverObs, clientObs
+}
+
+func TestUnaryCalls(t *testing.T) {
+	cc, serverObs, clientObs := dial(t)
+	tc := logz.NewTraceContext()
+	ctx := logz.ContextWithTrace(context.Background(), tc)
+
+	out := new(wrapperspb.StringValue)
+	if err := cc.Invoke(ctx, "/test.Echo/Unary", wrapperspb.String("hi"), out); err != nil || out.Value != "hi" {
+		t.Fatalf("Invoke = %v, %v", out, err)
+	}
+	err := cc.Invoke(ctx, "/test.Echo/Unary", wrapperspb.String("missing"), out)
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Invoke(missing) = %v", err)
+	}
+
+	srv := serverObs.Logs()
+	logztest.AssertCount(t, srv, 2)
+	ok := srv.WithField("grpc.code", "OK")[0]
+	logztest.AssertField(t, ok, "grpc.service", "test.Echo")
+	logztest.AssertField(t, ok, "grpc.method", "Unary")
+	// the server continues the client's trace with its own span.
+	if ok.TraceID != tc.TraceID || ok.SpanID == tc.SpanID {
+		t.Errorf("server trace = %s/%s", ok.TraceID, ok.SpanID)
+	}
+	nf := srv.WithField("grpc.code", "NotFound")[0]
+	if nf.Level != logzgrpc.CodeLevel(codes.NotFound) {
+		t.Errorf("NotFound level = %s", nf.Level)
+	}
+	logztest.AssertCount(t, clientObs.Logs(), 2)
+}
+
+// With SendMsg and RecvMsg on different goroutines the call is logged once
+// and without a data race (run with -race).
+func TestClientStreamLogsOnce(t *testing.T) {
+	cc, _, clientObs := dial(t)
+	for i := 0; i < 20; i++ {
+		stream, err := cc.NewStream(context.Background(), &echo.Streams[0], "/test.Echo/Bidi")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				if err := stream.SendMsg(wrapperspb.String("x")); err != nil {
+					return
+				}
+			}
+			_ = stream.CloseSend()
+		}()
+		go func() {
+			defer wg.Done()
+			for {
+				if err := stream.RecvMsg(new(wrapperspb.StringValue)); err != nil {
+					if !errors.Is(err, io.EOF) {
+						t.Errorf("RecvMsg: %v", err)
+					}
+					return
+				}
+			}
+		}()
+		wg.Wait()
+	}
+	logs := clientObs.TakeAll()
+	logztest.AssertCount(t, logs, 20)
+	if n := logs.WithField("grpc.code", "OK").Len(); n != 20 {
+		t.Errorf("%d OK entries, want 20:\n%s", n, logs)
+	}
+
+	// canceled while SendMsg is blocked on flow control and RecvMsg is
+	// waiting: both sides see the end at the same time.
+	payload := wrapperspb.String(string(make([]byte, 32<<10)))
+	for i := 0; i < 20; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := cc.NewStream(ctx, &echo.Streams[1], "/test.Echo/Hold")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for stream.SendMsg(payload) == nil {
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_ = stream.RecvMsg(new(wrapperspb.StringValue))
+		}()
+		cancel()
+		wg.Wait()
+	}
+	logs = clientObs.TakeAll()
+	logztest.AssertCount(t, logs, 20)
+	if n := logs.WithField("grpc.code", "Canceled").Len(); n != 20 {
+		t.Errorf("%d Canceled entries, want 20:\n%s", n, logs)
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/kubex-ecosystem/logz/logzlogrus
+
+go 1.26.2
+
+require (
+	github.com/kubex-ecosystem/logz v1.7.0
+	github.com/sirupsen/logrus v1.10.2
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	go.opentelemetry.io/otel v1.47.0 // indirect
+	go.opentelemetry.io/otel/trace v1.47.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	google.golang.org/protobuf v1.36.12 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
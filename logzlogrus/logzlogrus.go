@@ -0,0 +1,118 @@
+// Package logzlogrus forwards logrus entries to a logz LoggerZ, either as a
+// logrus.Hook or as a logrus.Formatter.
+//
+//	logzlogrus.Install(logrus.StandardLogger(), logz.GetLoggerZ(""))
+//
+// It is a separate module (go get github.com/kubex-ecosystem/logz/logzlogrus)
+// so that the logz module does not depend on logrus.
+package logzlogrus
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kubex-ecosystem/logz"
+	"github.com/kubex-ecosystem/logz/logzadapter"
+)
+
+// Level maps a logrus level to the logz level.
+func Level(l logrus.Level) logz.Level {
+	switch l {
+	case logrus.TraceLevel:
+		return logz.LevelTrace
+	case logrus.DebugLevel:
+		return logz.LevelDebug
+	case logrus.InfoLevel:
+		return logz.LevelInfo
+	case logrus.WarnLevel:
+		return logz.LevelWarn
+	case logrus.ErrorLevel:
+		return logz.LevelError
+	case logrus.FatalLevel:
+		return logz.LevelFatal
+	case logrus.PanicLevel:
+		return logz.LevelPanic
+	}
+	return logz.LevelInfo
+}
+
+// Hook is a logrus.Hook that sends every entry to a LoggerZ.
+type Hook struct {
+	logger *logz.LoggerZ
+	levels []logrus.Level
+}
+
+// NewHook returns a hook for all logrus levels. If logger is nil, the
+// global LoggerZ is used.
+func NewHook(logger *logz.LoggerZ) *Hook {
+	if logger == nil {
+		logger = logz.GetLoggerZ("")
+	}
+	return &Hook{logger: logger, levels: logrus.AllLevels}
+}
+
+// Levels implements logrus.Hook.
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire implements logrus.Hook.
+func (h *Hook) Fire(e *logrus.Entry) error {
+	return logzadapter.Log(h.logger, record(e))
+}
+
+// Formatter is a logrus.Formatter that sends the entry to a LoggerZ and
+// returns no bytes, so logrus writes nothing to its own output.
+type Formatter struct {
+	logger *logz.LoggerZ
+}
+
+// NewFormatter returns a Formatter. If logger is nil, the global LoggerZ is
+// used.
+func NewFormatter(logger *logz.LoggerZ) *Formatter {
+	if logger == nil {
+		logger = logz.GetLoggerZ("")
+	}
+	return &Formatter{logger: logger}
+}
+
+// Format implements logrus.Formatter.
+func (f *Formatter) Format(e *logrus.Entry) ([]byte, error) {
+	return nil, logzadapter.Log(f.logger, record(e))
+}
+
+// Install routes lr through logger: it adds a Hook and discards logrus'
+// own output. lr's level still gates what reaches the hook; entries that
+// pass it are then filtered by the LoggerZ level.
+func Install(lr *logrus.Logger, logger *logz.LoggerZ) {
+	lr.AddHook(NewHook(logger))
+	lr.SetOutput(io.Discard)
+}
+
+func record(e *logrus.Entry) logzadapter.Record {
+	r := logzadapter.Record{
+		Source:   "logrus",
+		Level:    Level(e.Level),
+		Original: e.Level.String(),
+		Message:  e.Message,
+		Time:     e.Time,
+	}
+	if len(e.Data) > 0 {
+		r.Fields = make(map[string]any, len(e.Data))
+		for k, v := range e.Data {
+			if k == logrus.ErrorKey {
+				if err, ok := v.(error); ok {
+					r.Error = err
+					continue
+				}
+			}
+			r.Fields[k] = v
+		}
+	}
+	if e.HasCaller() {
+		r.Caller = fmt.Sprintf("%s:%d %s", e.Caller.File, e.Caller.Line, e.Caller.Function)
+	}
+	return r
+}
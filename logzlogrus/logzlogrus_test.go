@@ -0,0 +1,60 @@
+package logzlogrus_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kubex-ecosystem/logz"
+	"github.com/kubex-ecosystem/logz/logzlogrus"
+	"github.com/kubex-ecosystem/logz/logztest"
+)
+
+func TestInstall(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	lr := logrus.New()
+	var own bytes.Buffer
+	lr.SetOutput(&own)
+	lr.SetReportCaller(true)
+	logzlogrus.Install(lr, logger)
+
+	lr.WithField("user", "ana").Info("login")
+	lr.WithError(errors.New("denied")).Warn("login failed")
+
+	logs := obs.Logs()
+	logztest.AssertCount(t, logs, 2)
+	login := logztest.AssertLogged(t, logs, "login")
+	logztest.AssertField(t, login, "user", "ana")
+	if login.Caller == "" {
+		t.Error("caller missing")
+	}
+	failed := logztest.AssertLogged(t, logs, "login failed")
+	if failed.Level != logz.LevelWarn || failed.Error == nil || failed.Error.Error() != "denied" {
+		t.Errorf("level=%s error=%v", failed.Level, failed.Error)
+	}
+	if _, ok := failed.Fields[logrus.ErrorKey]; ok {
+		t.Error("error duplicated in fields")
+	}
+	if own.Len() != 0 {
+		t.Errorf("logrus wrote to its own output: %q", own.String())
+	}
+}
+
+func TestFormatterDemotesPanic(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	lr := logrus.New()
+	lr.SetFormatter(logzlogrus.NewFormatter(logger))
+
+	// logrus panics after writing; logz only logs it as error.
+	func() {
+		defer func() { _ = recover() }()
+		lr.Panic("boom")
+	}()
+	e := logztest.AssertLogged(t, obs.Logs(), "boom")
+	if e.Level != logz.LevelError {
+		t.Errorf("level = %s", e.Level)
+	}
+	logztest.AssertField(t, e, "logrus.level", "panic")
+}
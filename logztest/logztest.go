@@ -0,0 +1,315 @@
+// Package logztest helps unit tests assert on what code logs.
+//
+// An Observer captures the structured entries (not formatted bytes) that
+// reach a LoggerZ, so tests can filter and assert on levels, messages and
+// fields:
+//
+//	logger, obs := logztest.New(t)
+//	svc := NewService(logger)
+//	svc.Do()
+//	logztest.AssertLogged(t, obs.Logs().ByLevel(logz.ParseLevel("error")), "payment failed")
+//
+// Entries logged at fatal, panic or critical still end the process, as in
+// production; use error-level logs for code paths under test.
+package logztest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/logz"
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+)
+
+// Observer records every entry it receives. It works as the logger output
+// (it implements logz.EntryWriter) or as a hook on an existing logger (see
+// Hook). Safe for concurrent use.
+type Observer struct {
+	mu      sync.Mutex
+	entries []*C.Entry
+	tb      testing.TB
+	format  logz.LogzFormatter
+}
+
+// NewObserver returns an empty Observer. If tb is not nil, every entry is
+// also written to tb.Log, so it shows up next to the failing test.
+func NewObserver(tb testing.TB) *Observer {
+	o := &Observer{tb: tb}
+	if tb != nil {
+		o.format = logz.NewLogzFormatter(nil, "logfmt")
+	}
+	return o
+}
+
+// New returns a LoggerZ at trace level whose output is a new Observer.
+// If tb is not nil, entries are echoed to tb.Log.
+func New(tb testing.TB) (*logz.LoggerZ, *Observer) {
+	obs := NewObserver(tb)
+	logger := logz.NewLogger("logztest")
+	logger.SetMinLevel(kbx.LevelTrace)
+	logger.SetOutput(obs)
+	return logger, obs
+}
+
+// WriteEntry implements logz.EntryWriter.
+func (o *Observer) WriteEntry(e kbx.Entry) error {
+	entry, ok := e.(*C.Entry)
+	if !ok || entry == nil {
+		return nil
+	}
+	clone := entry.Clone().(*C.Entry)
+
+	o.mu.Lock()
+	o.entries = append(o.entries, clone)
+	o.mu.Unlock()
+
+	if o.tb != nil {
+		o.tb.Helper()
+		if b, err := o.format.Format(clone); err == nil {
+			o.tb.Log(strings.TrimRight(string(b), "\n"))
+		}
+	}
+	return nil
+}
+
+// Write implements io.Writer so the Observer can be set as a logger
+// output. Loggers hand entries to WriteEntry instead; bytes written
+// directly are ignored.
+func (o *Observer) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// Hook returns a hook that records entries from a logger whose output
+// should stay untouched:
+//
+//	logger.AddHook(obs.Hook())
+func (o *Observer) Hook() func(kbx.Entry) error {
+	return o.WriteEntry
+}
+
+// Logs returns a snapshot of the recorded entries.
+func (o *Observer) Logs() Logs {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append(Logs(nil), o.entries...)
+}
+
+// TakeAll returns the recorded entries and clears the Observer.
+func (o *Observer) TakeAll() Logs {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := Logs(o.entries)
+	o.entries = nil
+	return out
+}
+
+// Len returns how many entries were recorded.
+func (o *Observer) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// Reset drops the recorded entries.
+func (o *Observer) Reset() {
+	o.mu.Lock()
+	o.entries = nil
+	o.mu.Unlock()
+}
+
+// Logs is a list of captured entries with filtering helpers. Filters
+// return a new list and can be chained.
+type Logs []*C.Entry
+
+// Filter keeps the entries for which keep returns true.
+func (ls Logs) Filter(keep func(*C.Entry) bool) Logs {
+	var out Logs
+	for _, e := range ls {
+		if keep(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// ByLevel keeps the entries at level.
+func (ls Logs) ByLevel(level kbx.Level) Logs {
+	return ls.Filter(func(e *C.Entry) bool { return e.Level == level })
+}
+
+// AtLeast keeps the entries at level or above.
+func (ls Logs) AtLeast(level kbx.Level) Logs {
+	return ls.Filter(func(e *C.Entry) bool { return e.Level.Severity() >= level.Severity() })
+}
+
+// ByMessage keeps the entries whose message is exactly msg.
+func (ls Logs) ByMessage(msg string) Logs {
+	return ls.Filter(func(e *C.Entry) bool { return e.Message == msg })
+}
+
+// ByMessageContains keeps the entries whose message contains sub.
+func (ls Logs) ByMessageContains(sub string) Logs {
+	return ls.Filter(func(e *C.Entry) bool { return strings.Contains(e.Message, sub) })
+}
+
+// WithField keeps the entries that have key set to value (compared with
+// reflect.DeepEqual, after numeric normalization so 1 matches int64(1)).
+func (ls Logs) WithField(key string, value any) Logs {
+	return ls.Filter(func(e *C.Entry) bool {
+		v, ok := e.Fields[key]
+		return ok && equal(v, value)
+	})
+}
+
+// WithFieldKey keeps the entries that have key set, whatever the value.
+func (ls Logs) WithFieldKey(key string) Logs {
+	return ls.Filter(func(e *C.Entry) bool {
+		_, ok := e.Fields[key]
+		return ok
+	})
+}
+
+// Len returns the number of entries.
+func (ls Logs) Len() int { return len(ls) }
+
+// Messages returns the messages, in order.
+func (ls Logs) Messages() []string {
+	out := make([]string, len(ls))
+	for i, e := range ls {
+		out[i] = e.Message
+	}
+	return out
+}
+
+// String lists the entries as "level: message", one per line; used in
+// assertion failures.
+func (ls Logs) String() string {
+	if len(ls) == 0 {
+		return "(no entries)"
+	}
+	var b strings.Builder
+	for _, e := range ls {
+		fmt.Fprintf(&b, "\n  %s: %s", e.Level, e.Message)
+		if len(e.Fields) > 0 {
+			fmt.Fprintf(&b, " %v", e.Fields)
+		}
+	}
+	return b.String()
+}
+
+// AssertLogged fails the test unless some entry has message msg, and
+// returns the first one.
+func AssertLogged(t testing.TB, logs Logs, msg string) *C.Entry {
+	t.Helper()
+	found := logs.ByMessage(msg)
+	if len(found) == 0 {
+		t.Errorf("logztest: no entry with message %q; got:%s", msg, logs)
+		return nil
+	}
+	return found[0]
+}
+
+// AssertNotLogged fails the test if some entry has message msg.
+func AssertNotLogged(t testing.TB, logs Logs, msg string) {
+	t.Helper()
+	if found := logs.ByMessage(msg); len(found) > 0 {
+		t.Errorf("logztest: unexpected entry with message %q:%s", msg, found)
+	}
+}
+
+// AssertCount fails the test unless logs has exactly n entries.
+func AssertCount(t testing.TB, logs Logs, n int) {
+	t.Helper()
+	if len(logs) != n {
+		t.Errorf("logztest: got %d entries, want %d:%s", len(logs), n, logs)
+	}
+}
+
+// AssertField fails the test unless e has key set to want.
+func AssertField(t testing.TB, e *C.Entry, key string, want any) {
+	t.Helper()
+	if e == nil {
+		t.Errorf("logztest: nil entry (looking for field %q)", key)
+		return
+	}
+	got, ok := e.Fields[key]
+	if !ok {
+		t.Errorf("logztest: entry %q has no field %q; fields: %v", e.Message, key, e.Fields)
+		return
+	}
+	if !equal(got, want) {
+		t.Errorf("logztest: entry %q field %q = %#v, want %#v", e.Message, key, got, want)
+	}
+}
+
+// equal compares field values, treating all integer and float kinds as
+// numbers so tests do not depend on the exact numeric type logged.
+func equal(got, want any) bool {
+	if reflect.DeepEqual(got, want) {
+		return true
+	}
+	g, gok := number(got)
+	w, wok := number(want)
+	return gok && wok && g == w
+}
+
+func number(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// Clock is a fake clock for deterministic timestamps in golden tests.
+// Each call to Now returns the current time and then advances it by Step.
+type Clock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+// NewClock returns a Clock starting at start that advances by step on
+// every Now (step may be 0 to freeze time).
+func NewClock(start time.Time, step time.Duration) *Clock {
+	return &Clock{now: start.UTC(), step: step}
+}
+
+// Now returns the clock's time and advances it by the step.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// Set moves the clock to t.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t.UTC()
+	c.mu.Unlock()
+}
+
+// UseClock makes logger (and its children) stamp entries with c.Now()
+// instead of the wall clock.
+func UseClock(logger *logz.LoggerZ, c *Clock) {
+	logger.SetClock(c)
+}
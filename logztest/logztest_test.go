@@ -0,0 +1,115 @@
+package logztest_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/logz"
+	C "github.com/kubex-ecosystem/logz/internal/core"
+	"github.com/kubex-ecosystem/logz/internal/module/kbx"
+	"github.com/kubex-ecosystem/logz/logztest"
+)
+
+func entry(lvl kbx.Level, msg string, fields map[string]any) *C.Entry {
+	return C.NewLogzEntry(lvl).WithMessage(msg).WithFields(fields).(*C.Entry)
+}
+
+func TestObserverFilters(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	_ = logger.Log(kbx.LevelDebug, entry(kbx.LevelDebug, "cache miss", map[string]any{"key": "a"}))
+	_ = logger.Log(kbx.LevelWarn, entry(kbx.LevelWarn, "retrying", map[string]any{"attempt": int64(2)}))
+	_ = logger.Log(kbx.LevelError, entry(kbx.LevelError, "payment failed", map[string]any{"attempt": 3}))
+
+	logs := obs.Logs()
+	logztest.AssertCount(t, logs, 3)
+	if got := logs.AtLeast(kbx.LevelWarn).Messages(); fmt.Sprint(got) != "[retrying payment failed]" {
+		t.Errorf("AtLeast(warn) = %v", got)
+	}
+	if n := logs.ByLevel(kbx.LevelDebug).Len(); n != 1 {
+		t.Errorf("ByLevel(debug) = %d", n)
+	}
+	// 2 (int) matches int64(2): numbers are normalized before comparing.
+	if n := logs.WithField("attempt", 2).Len(); n != 1 {
+		t.Errorf("WithField(attempt, 2) = %d", n)
+	}
+	if n := logs.WithFieldKey("attempt").ByMessageContains("pay").Len(); n != 1 {
+		t.Errorf("chained filters = %d", n)
+	}
+
+	if taken := obs.TakeAll(); len(taken) != 3 || obs.Len() != 0 {
+		t.Errorf("TakeAll = %d, left %d", len(taken), obs.Len())
+	}
+}
+
+// The Observer keeps a copy: changing the entry after logging it does not
+// change what was captured.
+func TestObserverClonesEntries(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	e := entry(kbx.LevelInfo, "created", map[string]any{"id": 1})
+	_ = logger.Log(kbx.LevelInfo, e)
+	e.Fields["id"] = 2
+	e.Message = "changed"
+
+	logztest.AssertField(t, logztest.AssertLogged(t, obs.Logs(), "created"), "id", 1)
+}
+
+func TestObserverAsHook(t *testing.T) {
+	logger := logz.NewLogger("hooked")
+	logger.SetOutput(logztest.NewObserver(nil)) // any output, not under test
+	obs := logztest.NewObserver(nil)
+	logger.AddHook(obs.Hook())
+
+	_ = logger.Log(kbx.LevelInfo, entry(kbx.LevelInfo, "via hook", nil))
+	logztest.AssertLogged(t, obs.Logs(), "via hook")
+}
+
+// recorder is a testing.TB that only records failures.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertionsFail(t *testing.T) {
+	logs := logztest.Logs{entry(kbx.LevelInfo, "ok", map[string]any{"n": 1})}
+	r := &recorder{TB: t}
+
+	logztest.AssertLogged(r, logs, "missing")
+	logztest.AssertNotLogged(r, logs, "ok")
+	logztest.AssertCount(r, logs, 2)
+	logztest.AssertField(r, logs[0], "n", 2)
+	logztest.AssertField(r, logs[0], "absent", 1)
+	logztest.AssertField(r, nil, "n", 1)
+	if len(r.errors) != 6 {
+		t.Errorf("%d failures, want 6: %q", len(r.errors), r.errors)
+	}
+
+	r.errors = nil
+	logztest.AssertField(r, logztest.AssertLogged(r, logs, "ok"), "n", 1.0)
+	logztest.AssertCount(r, logs, 1)
+	if len(r.errors) != 0 {
+		t.Errorf("unexpected failures: %q", r.errors)
+	}
+}
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := logztest.NewClock(start, time.Second)
+	logger, obs := logztest.New(nil)
+	logztest.UseClock(logger, clock)
+
+	_ = logger.Log(kbx.LevelInfo, C.NewLogzEntry(kbx.LevelInfo).WithMessage("first"))
+	clock.Advance(time.Minute)
+	_ = logger.Log(kbx.LevelInfo, C.NewLogzEntry(kbx.LevelInfo).WithMessage("second"))
+
+	logs := obs.Logs()
+	logztest.AssertCount(t, logs, 2)
+	if !logs[0].Timestamp.Equal(start) || !logs[1].Timestamp.Equal(start.Add(time.Minute+time.Second)) {
+		t.Errorf("timestamps = %v, %v", logs[0].Timestamp, logs[1].Timestamp)
+	}
+}
@@ -0,0 +1,125 @@
+// Package logzzap plugs logz into zap: NewCore returns a zapcore.Core that
+// forwards every zap entry, fields included, to a logz LoggerZ.
+//
+//	zl := zap.New(logzzap.NewCore(logz.GetLoggerZ("")), zap.AddCaller())
+//	zap.ReplaceGlobals(zl)
+//
+// It is a separate module (go get github.com/kubex-ecosystem/logz/logzzap)
+// so that the logz module does not depend on zap.
+package logzzap
+
+import (
+	"go.uber.org/zap/zapcore"
+
+	"github.com/kubex-ecosystem/logz"
+	"github.com/kubex-ecosystem/logz/logzadapter"
+)
+
+// Core is a zapcore.Core backed by a LoggerZ. Level filtering is done by
+// the LoggerZ, so changing its level also applies to zap call sites.
+type Core struct {
+	logger *logz.LoggerZ
+	fields []zapcore.Field
+}
+
+// NewCore returns a zapcore.Core that writes to logger. If logger is nil,
+// the global LoggerZ is used.
+func NewCore(logger *logz.LoggerZ) *Core {
+	if logger == nil {
+		logger = logz.GetLoggerZ("")
+	}
+	return &Core{logger: logger}
+}
+
+// Level maps a zap level to the logz level.
+func Level(l zapcore.Level) logz.Level {
+	switch l {
+	case zapcore.DebugLevel:
+		return logz.LevelDebug
+	case zapcore.InfoLevel:
+		return logz.LevelInfo
+	case zapcore.WarnLevel:
+		return logz.LevelWarn
+	case zapcore.ErrorLevel:
+		return logz.LevelError
+	case zapcore.DPanicLevel:
+		return logz.LevelCritical
+	case zapcore.PanicLevel:
+		return logz.LevelPanic
+	case zapcore.FatalLevel:
+		return logz.LevelFatal
+	}
+	if l < zapcore.DebugLevel {
+		return logz.LevelTrace
+	}
+	return logz.LevelError
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (c *Core) Enabled(l zapcore.Level) bool {
+	return c.logger.Enabled(logzadapter.Effective(Level(l)))
+}
+
+// With implements zapcore.Core.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	clone := &Core{logger: c.logger, fields: make([]zapcore.Field, 0, len(c.fields)+len(fields))}
+	clone.fields = append(clone.fields, c.fields...)
+	clone.fields = append(clone.fields, fields...)
+	return clone
+}
+
+// Check implements zapcore.Core.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core. Error fields named "error" become the
+// entry's error; everything else is encoded into the entry's fields.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	var entryErr error
+	add := func(f zapcore.Field) {
+		if f.Type == zapcore.ErrorType && f.Key == "error" {
+			if err, ok := f.Interface.(error); ok {
+				entryErr = err
+				return
+			}
+		}
+		f.AddTo(enc)
+	}
+	for _, f := range c.fields {
+		add(f)
+	}
+	for _, f := range fields {
+		add(f)
+	}
+	if ent.Stack != "" {
+		enc.Fields["stack"] = ent.Stack
+	}
+
+	r := logzadapter.Record{
+		Source:   "zap",
+		Level:    Level(ent.Level),
+		Original: ent.Level.String(),
+		Message:  ent.Message,
+		Time:     ent.Time,
+		Name:     ent.LoggerName,
+		Fields:   enc.Fields,
+		Error:    entryErr,
+	}
+	if ent.Caller.Defined {
+		r.Caller = ent.Caller.String()
+		if ent.Caller.Function != "" {
+			r.Caller += " " + ent.Caller.Function
+		}
+	}
+	return logzadapter.Log(c.logger, r)
+}
+
+// Sync implements zapcore.Core: it flushes the logger's exporters.
+func (c *Core) Sync() error {
+	return c.logger.FlushExporters()
+}
@@ -0,0 +1,57 @@
+package logzzap_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kubex-ecosystem/logz"
+	"github.com/kubex-ecosystem/logz/logztest"
+	"github.com/kubex-ecosystem/logz/logzzap"
+)
+
+func TestCoreForwardsEntries(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	zl := zap.New(logzzap.NewCore(logger), zap.AddCaller()).Named("svc").With(zap.String("tenant", "acme"))
+
+	zl.Info("started", zap.Int("port", 8080))
+	zl.Error("query failed", zap.Error(errors.New("timeout")))
+	zl.DPanic("inconsistent state")
+
+	logs := obs.Logs()
+	logztest.AssertCount(t, logs, 3)
+	started := logztest.AssertLogged(t, logs, "started")
+	logztest.AssertField(t, started, "tenant", "acme")
+	logztest.AssertField(t, started, "port", 8080)
+	if started.Context != "svc" || started.Caller == "" {
+		t.Errorf("context=%q caller=%q", started.Context, started.Caller)
+	}
+
+	failed := logztest.AssertLogged(t, logs, "query failed")
+	if failed.Error == nil || failed.Error.Error() != "timeout" {
+		t.Errorf("error = %v", failed.Error)
+	}
+
+	// DPanic must not end the process through logz: it is logged as error
+	// with the original level kept.
+	dp := logztest.AssertLogged(t, logs, "inconsistent state")
+	if dp.Level != logz.LevelError {
+		t.Errorf("level = %s", dp.Level)
+	}
+	logztest.AssertField(t, dp, "zap.level", "dpanic")
+}
+
+func TestCoreFollowsLoggerLevel(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	logger.SetMinLevel(logz.LevelWarn)
+	zl := zap.New(logzzap.NewCore(logger))
+
+	if zl.Core().Enabled(zap.InfoLevel) {
+		t.Error("info enabled at warn")
+	}
+	zl.Info("dropped")
+	zl.Warn("kept")
+	logztest.AssertNotLogged(t, obs.Logs(), "dropped")
+	logztest.AssertLogged(t, obs.Logs(), "kept")
+}
@@ -0,0 +1,178 @@
+// Package logzzerolog forwards zerolog output to a logz LoggerZ. Writer is
+// a zerolog.LevelWriter that decodes each JSON event back into a message
+// and fields.
+//
+//	zl := zerolog.New(logzzerolog.NewWriter(logz.GetLoggerZ(""))).With().Timestamp().Logger()
+//
+// It is a separate module (go get github.com/kubex-ecosystem/logz/logzzerolog)
+// so that the logz module does not depend on zerolog.
+package logzzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/kubex-ecosystem/logz"
+	"github.com/kubex-ecosystem/logz/logzadapter"
+)
+
+// Level maps a zerolog level to the logz level.
+func Level(l zerolog.Level) logz.Level {
+	switch l {
+	case zerolog.TraceLevel:
+		return logz.LevelTrace
+	case zerolog.DebugLevel:
+		return logz.LevelDebug
+	case zerolog.InfoLevel, zerolog.NoLevel:
+		return logz.LevelInfo
+	case zerolog.WarnLevel:
+		return logz.LevelWarn
+	case zerolog.ErrorLevel:
+		return logz.LevelError
+	case zerolog.FatalLevel:
+		return logz.LevelFatal
+	case zerolog.PanicLevel:
+		return logz.LevelPanic
+	}
+	if l < zerolog.TraceLevel {
+		return logz.LevelTrace
+	}
+	return logz.LevelInfo
+}
+
+// Writer is a zerolog.LevelWriter backed by a LoggerZ. The field names
+// (level, message, time, error, caller) follow zerolog's global settings.
+type Writer struct {
+	logger *logz.LoggerZ
+}
+
+// NewWriter returns a Writer. If logger is nil, the global LoggerZ is used.
+func NewWriter(logger *logz.LoggerZ) *Writer {
+	if logger == nil {
+		logger = logz.GetLoggerZ("")
+	}
+	return &Writer{logger: logger}
+}
+
+// Write implements io.Writer; the level is read from the event itself.
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter.
+func (w *Writer) WriteLevel(l zerolog.Level, p []byte) (int, error) {
+	if len(bytes.TrimSpace(p)) == 0 {
+		return len(p), nil
+	}
+	var m map[string]any
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		// not JSON (e.g. a ConsoleWriter in between): log the line as is.
+		return len(p), logzadapter.Log(w.logger, logzadapter.Record{
+			Source:   "zerolog",
+			Level:    Level(l),
+			Original: l.String(),
+			Message:  strings.TrimSpace(string(p)),
+		})
+	}
+
+	if v, ok := m[zerolog.LevelFieldName].(string); ok {
+		if parsed, err := zerolog.ParseLevel(v); err == nil && (l == zerolog.NoLevel || parsed != zerolog.NoLevel) {
+			l = parsed
+		}
+		delete(m, zerolog.LevelFieldName)
+	}
+	r := logzadapter.Record{
+		Source:   "zerolog",
+		Level:    Level(l),
+		Original: l.String(),
+	}
+	if v, ok := m[zerolog.MessageFieldName]; ok {
+		r.Message = fmt.Sprint(v)
+		delete(m, zerolog.MessageFieldName)
+	}
+	if v, ok := m[zerolog.TimestampFieldName]; ok {
+		if ts, ok := parseTime(v); ok {
+			r.Time = ts
+			delete(m, zerolog.TimestampFieldName)
+		}
+	}
+	if v, ok := m[zerolog.ErrorFieldName].(string); ok {
+		r.Error = errors.New(v)
+		delete(m, zerolog.ErrorFieldName)
+	}
+	if v, ok := m[zerolog.CallerFieldName].(string); ok {
+		r.Caller = v
+		delete(m, zerolog.CallerFieldName)
+	}
+	if len(m) > 0 {
+		for k, v := range m {
+			m[k] = numbers(v)
+		}
+		r.Fields = m
+	}
+	return len(p), logzadapter.Log(w.logger, r)
+}
+
+// numbers turns the json.Number values left by the decoder into int64 (or
+// float64 when they do not fit), so fields keep zerolog's types.
+func numbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]any:
+		for k, e := range v {
+			v[k] = numbers(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = numbers(e)
+		}
+	}
+	return v
+}
+
+// parseTime understands zerolog.TimeFieldFormat, including the Unix
+// variants (seconds, ms, µs, ns).
+func parseTime(v any) (time.Time, bool) {
+	switch zerolog.TimeFieldFormat {
+	case zerolog.TimeFormatUnix, zerolog.TimeFormatUnixMs, zerolog.TimeFormatUnixMicro, zerolog.TimeFormatUnixNano:
+		n, ok := v.(json.Number)
+		if !ok {
+			return time.Time{}, false
+		}
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		switch zerolog.TimeFieldFormat {
+		case zerolog.TimeFormatUnixMs:
+			return time.UnixMilli(i), true
+		case zerolog.TimeFormatUnixMicro:
+			return time.UnixMicro(i), true
+		case zerolog.TimeFormatUnixNano:
+			return time.Unix(0, i), true
+		}
+		return time.Unix(i, 0), true
+	}
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	ts, err := time.Parse(zerolog.TimeFieldFormat, s)
+	return ts, err == nil
+}
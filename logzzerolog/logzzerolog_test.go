@@ -0,0 +1,63 @@
+package logzzerolog_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/kubex-ecosystem/logz"
+	"github.com/kubex-ecosystem/logz/logztest"
+	"github.com/kubex-ecosystem/logz/logzzerolog"
+)
+
+func TestWriterDecodesEvents(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	zl := zerolog.New(logzzerolog.NewWriter(logger)).With().Timestamp().Str("svc", "api").Logger()
+
+	zl.Info().Int("status", 200).Float64("ratio", 0.5).Ints("ports", []int{80}).Msg("served")
+	zl.Error().Err(errors.New("refused")).Msg("dial failed")
+
+	logs := obs.Logs()
+	logztest.AssertCount(t, logs, 2)
+	served := logztest.AssertLogged(t, logs, "served")
+	logztest.AssertField(t, served, "svc", "api")
+	logztest.AssertField(t, served, "status", 200)
+	logztest.AssertField(t, served, "ratio", 0.5)
+	if ports, _ := served.Fields["ports"].([]any); len(ports) != 1 || ports[0] != int64(80) {
+		t.Errorf("ports = %#v", served.Fields["ports"])
+	}
+	if _, ok := served.Fields[zerolog.TimestampFieldName]; ok || time.Since(served.Timestamp) > time.Minute {
+		t.Errorf("timestamp not taken from the event: %v %v", served.Timestamp, served.Fields)
+	}
+	failed := logztest.AssertLogged(t, logs, "dial failed")
+	if failed.Level != logz.LevelError || failed.Error == nil || failed.Error.Error() != "refused" {
+		t.Errorf("level=%s error=%v", failed.Level, failed.Error)
+	}
+}
+
+func TestWriterPlainLines(t *testing.T) {
+	logger, obs := logztest.New(nil)
+	w := logzzerolog.NewWriter(logger)
+	if _, err := w.WriteLevel(zerolog.WarnLevel, []byte("not json\n")); err != nil {
+		t.Fatal(err)
+	}
+	e := logztest.AssertLogged(t, obs.Logs(), "not json")
+	if e.Level != logz.LevelWarn {
+		t.Errorf("level = %s", e.Level)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	for l, want := range map[zerolog.Level]logz.Level{
+		zerolog.TraceLevel: logz.LevelTrace,
+		zerolog.NoLevel:    logz.LevelInfo,
+		zerolog.FatalLevel: logz.LevelFatal,
+		zerolog.PanicLevel: logz.LevelPanic,
+	} {
+		if got := logzzerolog.Level(l); got != want {
+			t.Errorf("Level(%s) = %s, want %s", l, got, want)
+		}
+	}
+}